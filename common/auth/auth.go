@@ -0,0 +1,241 @@
+// Package auth authenticates callers by bearer token and lets them call only
+// the methods their roles allow. A token is either a static key listed in a
+// keys file, or claims signed with a shared key:
+//
+//	base64url(claims JSON) "." base64url(HMAC-SHA256 of the first part)
+//
+// so that tokens can be issued locally without an identity provider.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataKey is the request header that carries the bearer token.
+const metadataKey = "authorization"
+
+type Role string
+
+const (
+	// RoleIntake is held by order intake systems.
+	RoleIntake Role = "intake"
+	// RoleOperator is held by floor operators.
+	RoleOperator Role = "operator"
+	// RoleFulfillment is held by the fulfillment service, which drives the
+	// sorting robot.
+	RoleFulfillment Role = "fulfillment"
+)
+
+// publicServices may be called without a token, so that load balancers can
+// probe health and tools can list the services.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// Claims is who a token was issued to and what they may do.
+type Claims struct {
+	Subject string `json:"sub"`
+	Roles   []Role `json:"roles"`
+	// ExpiresAt is the Unix time in seconds after which the token is no
+	// longer accepted. Zero means never.
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+func (c Claims) hasRole(roles []Role) bool {
+	for _, held := range c.Roles {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Policy lists the roles that may call each method, by full method name.
+// Methods that are not listed may be called by anyone with a valid token.
+type Policy map[string][]Role
+
+// Authenticator checks tokens against a signing key and a set of static keys.
+type Authenticator struct {
+	signingKey []byte
+	staticKeys map[string]Claims
+}
+
+// Load reads the signing key and the static keys, a JSON object of claims by
+// key. Either file may be empty. If both are, authentication is off and nil is
+// returned.
+func Load(signingKeyFile, keysFile string) (*Authenticator, error) {
+	if signingKeyFile == "" && keysFile == "" {
+		return nil, nil
+	}
+
+	a := &Authenticator{staticKeys: map[string]Claims{}}
+	if signingKeyFile != "" {
+		signingKey, err := LoadSigningKey(signingKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		a.signingKey = signingKey
+	}
+
+	if keysFile != "" {
+		content, err := ioutil.ReadFile(keysFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(content, &a.staticKeys); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", keysFile, err)
+		}
+	}
+	return a, nil
+}
+
+// LoadSigningKey reads a signing key, ignoring surrounding whitespace.
+func LoadSigningKey(file string) ([]byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey := []byte(strings.TrimSpace(string(content)))
+	if len(signingKey) == 0 {
+		return nil, fmt.Errorf("signing key %s is empty", file)
+	}
+	return signingKey, nil
+}
+
+// Sign issues a token for claims.
+func Sign(signingKey []byte, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signature(signingKey, encoded)), nil
+}
+
+func signature(signingKey []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// Authenticate returns the claims of a valid token.
+func (a *Authenticator) Authenticate(token string) (Claims, error) {
+	if claims, ok := a.staticKeys[token]; ok {
+		return claims, nil
+	}
+
+	parts := strings.Split(token, ".")
+	if a.signingKey == nil || len(parts) != 2 {
+		return Claims{}, fmt.Errorf("unknown token")
+	}
+
+	tokenSignature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(tokenSignature, signature(a.signingKey, parts[0])) {
+		return Claims{}, fmt.Errorf("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Claims{}, fmt.Errorf("invalid token: %v", err)
+	}
+
+	claims := Claims{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, fmt.Errorf("invalid token: %v", err)
+	}
+
+	if claims.ExpiresAt != 0 && time.Now().Unix() > claims.ExpiresAt {
+		return Claims{}, fmt.Errorf("token of %s has expired", claims.Subject)
+	}
+	return claims, nil
+}
+
+// authorize checks that the caller may call method. A nil Authenticator lets
+// everyone through.
+func (a *Authenticator) authorize(ctx context.Context, policy Policy, method string) error {
+	if a == nil {
+		return nil
+	}
+
+	for _, service := range publicServices {
+		if strings.HasPrefix(method, service) {
+			return nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(metadataKey)
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	claims, err := a.Authenticate(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if roles, ok := policy[method]; ok && !claims.hasRole(roles) {
+		return status.Errorf(codes.PermissionDenied, "%s may not call %s, it requires one of the roles %v", claims.Subject, method, roles)
+	}
+	return nil
+}
+
+func (a *Authenticator) UnaryServerInterceptor(policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, policy, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamServerInterceptor(policy Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), policy, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// TokenCredentials attaches a bearer token to every outgoing call.
+type TokenCredentials struct {
+	token string
+}
+
+// LoadToken reads a token, ignoring surrounding whitespace.
+func LoadToken(file string) (*TokenCredentials, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenCredentials{token: strings.TrimSpace(string(content))}, nil
+}
+
+func (c *TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{metadataKey: "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity allows sending the token without TLS, which is
+// only acceptable on a trusted network.
+func (c *TokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
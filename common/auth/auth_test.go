@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testSigningKey = []byte("test signing key")
+
+// testPolicy lets only the fulfillment service drive the robot and only
+// operators clear faults or resume it.
+var testPolicy = Policy{
+	"/SortingRobot/LoadItems":  {RoleIntake, RoleOperator},
+	"/SortingRobot/SelectItem": {RoleFulfillment},
+	"/SortingRobot/MoveItem":   {RoleFulfillment},
+	"/SortingRobot/ClearFault": {RoleOperator},
+	"/SortingRobot/Resume":     {RoleOperator},
+}
+
+// withAuthenticator writes a signing key and a static key for an operator,
+// and loads them.
+func withAuthenticator(t *testing.T) *Authenticator {
+	dir, err := ioutil.TempDir("", "auth")
+	assert.Equal(t, err, nil, "Creating an auth directory should succeed")
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	signingKeyFile := filepath.Join(dir, "auth.key")
+	assert.Equal(t, ioutil.WriteFile(signingKeyFile, append(testSigningKey, '\n'), 0600), nil, "Writing the signing key should succeed")
+	keysFile := filepath.Join(dir, "keys.json")
+	assert.Equal(t, ioutil.WriteFile(keysFile, []byte(`{"operator-key": {"sub": "floor-1", "roles": ["operator"]}}`), 0600), nil, "Writing the static keys should succeed")
+
+	a, err := Load(signingKeyFile, keysFile)
+	assert.Equal(t, err, nil, "The signing key and static keys should load")
+	return a
+}
+
+func signToken(t *testing.T, signingKey []byte, claims Claims) string {
+	token, err := Sign(signingKey, claims)
+	assert.Equal(t, err, nil, "Claims should be signed")
+	return token
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(metadataKey, "Bearer "+token))
+}
+
+func TestAuthenticationIsOffWithoutKeys(t *testing.T) {
+	a, err := Load("", "")
+	assert.Equal(t, err, nil, "No keys should be valid")
+	assert.Nil(t, a, "Without keys authentication should be off")
+	assert.Equal(t, a.authorize(context.Background(), testPolicy, "/SortingRobot/SelectItem"), nil, "Everyone should be let through when authentication is off")
+}
+
+func TestSignedTokens(t *testing.T) {
+	a := withAuthenticator(t)
+
+	fulfillment := signToken(t, testSigningKey, Claims{Subject: "fulfillment-service", Roles: []Role{RoleFulfillment}})
+	assert.Equal(t, a.authorize(withToken(fulfillment), testPolicy, "/SortingRobot/SelectItem"), nil, "The fulfillment service should drive the robot")
+	assert.Equal(t, a.authorize(withToken(fulfillment), testPolicy, "/SortingRobot/GetRobotStatus"), nil, "Methods outside the policy should be open to any valid token")
+	assert.Equal(t, status.Code(a.authorize(withToken(fulfillment), testPolicy, "/SortingRobot/ClearFault")), codes.PermissionDenied, "The fulfillment service should not clear faults")
+
+	forged := signToken(t, []byte("another key"), Claims{Subject: "intruder", Roles: []Role{RoleFulfillment}})
+	assert.Equal(t, status.Code(a.authorize(withToken(forged), testPolicy, "/SortingRobot/SelectItem")), codes.Unauthenticated, "Tokens signed with another key should be refused")
+
+	expired := signToken(t, testSigningKey, Claims{Subject: "fulfillment-service", Roles: []Role{RoleFulfillment}, ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	assert.Equal(t, status.Code(a.authorize(withToken(expired), testPolicy, "/SortingRobot/SelectItem")), codes.Unauthenticated, "Expired tokens should be refused")
+}
+
+func TestStaticKeys(t *testing.T) {
+	a := withAuthenticator(t)
+
+	assert.Equal(t, a.authorize(withToken("operator-key"), testPolicy, "/SortingRobot/ClearFault"), nil, "Operators should clear faults")
+	assert.Equal(t, a.authorize(withToken("operator-key"), testPolicy, "/SortingRobot/LoadItems"), nil, "Operators should load items")
+	assert.Equal(t, status.Code(a.authorize(withToken("operator-key"), testPolicy, "/SortingRobot/MoveItem")), codes.PermissionDenied, "Operators should not drive the robot")
+	assert.Equal(t, status.Code(a.authorize(withToken("unknown-key"), testPolicy, "/SortingRobot/GetRobotStatus")), codes.Unauthenticated, "Unknown keys should be refused")
+}
+
+func TestMissingToken(t *testing.T) {
+	a := withAuthenticator(t)
+
+	assert.Equal(t, status.Code(a.authorize(context.Background(), testPolicy, "/SortingRobot/GetRobotStatus")), codes.Unauthenticated, "Calls without a token should be refused")
+	assert.Equal(t, a.authorize(context.Background(), testPolicy, "/grpc.health.v1.Health/Check"), nil, "Health checks should not need a token")
+}
+
+func TestInterceptorStopsUnauthorizedCalls(t *testing.T) {
+	a := withAuthenticator(t)
+	interceptor := a.UnaryServerInterceptor(testPolicy)
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err := interceptor(withToken("operator-key"), nil, &grpc.UnaryServerInfo{FullMethod: "/SortingRobot/SelectItem"}, handler)
+	assert.Equal(t, status.Code(err), codes.PermissionDenied, "The call should be refused")
+	assert.False(t, called, "A refused call should not reach the service")
+
+	_, err = interceptor(withToken("operator-key"), nil, &grpc.UnaryServerInfo{FullMethod: "/SortingRobot/Resume"}, handler)
+	assert.Equal(t, err, nil, "The call should be allowed")
+	assert.True(t, called, "An allowed call should reach the service")
+}
+
+func TestTokenCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	assert.Equal(t, err, nil, "Creating an auth directory should succeed")
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	assert.Equal(t, ioutil.WriteFile(tokenFile, []byte(" operator-key\n"), 0600), nil, "Writing the token should succeed")
+
+	credentials, err := LoadToken(tokenFile)
+	assert.Equal(t, err, nil, "The token should load")
+	md, err := credentials.GetRequestMetadata(context.Background())
+	assert.Equal(t, err, nil, "The token should be attached")
+	assert.Equal(t, md, map[string]string{metadataKey: "Bearer operator-key"}, "The token should be sent as a bearer token")
+
+	a := withAuthenticator(t)
+	assert.Equal(t, a.authorize(metadata.NewIncomingContext(context.Background(), metadata.New(md)), testPolicy, "/SortingRobot/Resume"), nil, "The attached token should be accepted")
+}
@@ -0,0 +1,125 @@
+// Package config fills in a service's flags from, in increasing order of
+// precedence, their defaults, config files, the environment and the command
+// line. Every setting is a flag, so all three sources use the flag's name:
+//
+//	"server-address": "localhost:10000"      in <config-path>/config.json
+//	<PREFIX>SERVER_ADDRESS=...               in the environment
+//	-server-address=...                      on the command line
+//
+// A <config-path>/<SERVICE_ENV>.json file, if present, overrides config.json.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const fileName = "config.json"
+
+var path = flag.String("config-path", "", "directory with the service's config files")
+
+// sources records where each flag's effective value came from.
+var sources = map[string]string{}
+
+// Load parses the command line and fills in every flag that was not set on it
+// from the config files and from environment variables named envPrefix
+// followed by the flag's name in upper snake case.
+func Load(envPrefix string) error {
+	flag.Parse()
+	flag.Visit(func(f *flag.Flag) {
+		sources[f.Name] = "flag"
+	})
+
+	if *path != "" {
+		files := []string{filepath.Join(*path, fileName)}
+		if env := os.Getenv("SERVICE_ENV"); env != "" {
+			files = append(files, filepath.Join(*path, env+".json"))
+		}
+
+		for _, file := range files {
+			if err := loadFile(file); err != nil {
+				return err
+			}
+		}
+	}
+
+	return loadEnv(envPrefix)
+}
+
+func loadFile(file string) error {
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", file, err)
+	}
+
+	for name, value := range values {
+		switch value.(type) {
+		case string, json.Number, bool:
+		default:
+			return fmt.Errorf("setting %q in %s must be a string, number or boolean", name, file)
+		}
+
+		if err := set(name, fmt.Sprint(value), file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadEnv(envPrefix string) error {
+	var err error
+	flag.VisitAll(func(f *flag.Flag) {
+		variable := envPrefix + strings.ToUpper(strings.Replace(f.Name, "-", "_", -1))
+		if value, ok := os.LookupEnv(variable); ok && err == nil {
+			err = set(f.Name, value, "env "+variable)
+		}
+	})
+	return err
+}
+
+// set overrides a flag unless it was given on the command line.
+func set(name, value, source string) error {
+	if flag.Lookup(name) == nil {
+		return fmt.Errorf("unknown setting %q in %s", name, source)
+	}
+
+	if sources[name] == "flag" {
+		return nil
+	}
+
+	if err := flag.Set(name, value); err != nil {
+		return fmt.Errorf("invalid value %q for %s in %s: %v", value, name, source, err)
+	}
+
+	sources[name] = source
+	return nil
+}
+
+// Print writes every setting's effective value and where it came from.
+func Print(w io.Writer) {
+	fmt.Fprintln(w, "Effective configuration:")
+	flag.VisitAll(func(f *flag.Flag) {
+		source, ok := sources[f.Name]
+		if !ok {
+			source = "default"
+		}
+		fmt.Fprintf(w, "  %s = %s (%s)\n", f.Name, f.Value.String(), source)
+	})
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const envPrefix = "CONFIG_TEST_"
+
+var (
+	numberOfCubbies  = flag.Int("number-of-cubbies", 10, "number of cubbies")
+	selectionTimeout = flag.Duration("selection-timeout", 30*time.Second, "selection timeout")
+)
+
+// withConfigFile writes a config file and restores every setting it may
+// change once the test is done.
+func withConfigFile(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Equal(t, err, nil, "Creating a config directory should succeed")
+
+	file := filepath.Join(dir, fileName)
+	assert.Equal(t, ioutil.WriteFile(file, []byte(content), 0644), nil, "Writing the config file should succeed")
+
+	previousCubbies, previousTimeout := *numberOfCubbies, *selectionTimeout
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+		*numberOfCubbies, *selectionTimeout = previousCubbies, previousTimeout
+		sources = map[string]string{}
+	})
+	return file
+}
+
+func TestConfigFile(t *testing.T) {
+	file := withConfigFile(t, `{"number-of-cubbies": 12, "selection-timeout": "5s"}`)
+
+	assert.Equal(t, loadFile(file), nil, "A valid config file should load")
+	assert.Equal(t, *numberOfCubbies, 12, "Numbers should be read from the config file")
+	assert.Equal(t, *selectionTimeout, 5*time.Second, "Durations should be read from the config file")
+	assert.Equal(t, sources["number-of-cubbies"], file, "The config file should be recorded as the source")
+}
+
+func TestMissingConfigFileIsIgnored(t *testing.T) {
+	assert.Equal(t, loadFile(filepath.Join(os.TempDir(), "missing", fileName)), nil, "A missing config file should not be an error")
+}
+
+func TestInvalidConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"Unknown setting", `{"number-of-cubes": 12}`},
+		{"Invalid value", `{"number-of-cubbies": "many"}`},
+		{"Nested value", `{"number-of-cubbies": {"value": 12}}`},
+		{"Malformed JSON", `{"number-of-cubbies": 12`},
+	}
+
+	for _, test := range tests {
+		file := withConfigFile(t, test.content)
+		assert.NotNil(t, loadFile(file), test.name+" should be rejected")
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		flag     string
+		expected int
+		source   string
+	}{
+		{"Config file", "", "", 12, "file"},
+		{"Environment over config file", "14", "", 14, "env " + envPrefix + "NUMBER_OF_CUBBIES"},
+		{"Command line over environment and config file", "14", "16", 16, "flag"},
+	}
+
+	for _, test := range tests {
+		file := withConfigFile(t, `{"number-of-cubbies": 12}`)
+		if test.env != "" {
+			os.Setenv(envPrefix+"NUMBER_OF_CUBBIES", test.env)
+		}
+		if test.flag != "" {
+			flag.Set("number-of-cubbies", test.flag)
+			sources["number-of-cubbies"] = "flag"
+		}
+
+		assert.Equal(t, loadFile(file), nil, test.name+": the config file should load")
+		assert.Equal(t, loadEnv(envPrefix), nil, test.name+": the environment should load")
+		os.Unsetenv(envPrefix + "NUMBER_OF_CUBBIES")
+
+		assert.Equal(t, *numberOfCubbies, test.expected, test.name+" should set the value")
+		source := sources["number-of-cubbies"]
+		if test.source == "file" {
+			assert.Equal(t, source, file, test.name+" should be recorded as the source")
+		} else {
+			assert.Equal(t, source, test.source, test.name+" should be recorded as the source")
+		}
+	}
+}
+
+func TestInvalidEnvironment(t *testing.T) {
+	withConfigFile(t, `{}`)
+	os.Setenv(envPrefix+"SELECTION_TIMEOUT", "soon")
+	defer os.Unsetenv(envPrefix + "SELECTION_TIMEOUT")
+
+	assert.NotNil(t, loadEnv(envPrefix), "An invalid value in the environment should be rejected")
+}
+
+func TestPrint(t *testing.T) {
+	file := withConfigFile(t, `{"number-of-cubbies": 12}`)
+	loadFile(file)
+
+	out := &bytes.Buffer{}
+	Print(out)
+	assert.Equal(t, strings.Contains(out.String(), "number-of-cubbies = 12 ("+file+")"), true, "The value from the config file and its source should be printed")
+	assert.Equal(t, strings.Contains(out.String(), "selection-timeout = 30s (default)"), true, "Defaults should be printed as such")
+}
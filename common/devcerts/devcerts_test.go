@@ -0,0 +1,37 @@
+package devcerts
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuedCertificateIsSignedByAuthority(t *testing.T) {
+	authority, err := NewAuthority("test CA")
+	assert.Equal(t, err, nil, "Creating a CA should succeed")
+
+	certificatePEM, keyPEM, err := authority.Issue("sorting-service", "localhost", "127.0.0.1")
+	assert.Equal(t, err, nil, "Issuing a certificate should succeed")
+
+	_, err = tls.X509KeyPair(certificatePEM, keyPEM)
+	assert.Equal(t, err, nil, "The certificate and key should match")
+
+	block, _ := pem.Decode(certificatePEM)
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	assert.Equal(t, err, nil, "The certificate should parse")
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(authority.CertificatePEM)
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+			_, err = certificate.Verify(x509.VerifyOptions{DNSName: host, Roots: roots, KeyUsages: []x509.ExtKeyUsage{usage}})
+			assert.Equal(t, err, nil, "The certificate should be valid for every host and usage")
+		}
+	}
+
+	_, err = certificate.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: roots})
+	assert.NotNil(t, err, "The certificate should not be valid for other hosts")
+}
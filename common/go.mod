@@ -0,0 +1,11 @@
+module github.com/Emoto13/sort-system/common
+
+go 1.16
+
+replace github.com/Emoto13/sort-system/gen => ../gen
+
+require (
+	github.com/Emoto13/sort-system/gen v0.0.0-20210623104657-36fa702e85f3
+	github.com/stretchr/testify v1.7.0
+	google.golang.org/grpc v1.38.0
+)
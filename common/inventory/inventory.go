@@ -0,0 +1,33 @@
+// Package inventory counts items the way the robots report their stock and
+// the fulfillment service reports what it sets aside.
+package inventory
+
+import (
+	"sort"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// Count counts items by code.
+func Count(items []*gen.Item) map[string]int32 {
+	quantities := map[string]int32{}
+	for _, item := range items {
+		quantities[item.Code]++
+	}
+	return quantities
+}
+
+// Levels lists quantities by code, in the order of their codes, leaving out
+// the codes of which there are none.
+func Levels(quantities map[string]int32) []*gen.StockLevel {
+	levels := []*gen.StockLevel{}
+	for code, quantity := range quantities {
+		if quantity > 0 {
+			levels = append(levels, &gen.StockLevel{Code: code, Quantity: quantity})
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Code < levels[j].Code
+	})
+	return levels
+}
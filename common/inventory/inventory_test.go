@@ -0,0 +1,31 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		quantities map[string]int32
+		levels     []*gen.StockLevel
+	}{
+		{"Nothing", map[string]int32{}, []*gen.StockLevel{}},
+		{"By code", map[string]int32{"b": 1, "a": 2}, []*gen.StockLevel{{Code: "a", Quantity: 2}, {Code: "b", Quantity: 1}}},
+		{"None left", map[string]int32{"a": 0, "b": -1, "c": 1}, []*gen.StockLevel{{Code: "c", Quantity: 1}}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, Levels(test.quantities), test.levels, test.name)
+	}
+}
+
+func TestCount(t *testing.T) {
+	items := []*gen.Item{{Code: "b"}, {Code: "a"}, {Code: "b"}}
+
+	assert.Equal(t, Count(items), map[string]int32{"a": 1, "b": 2}, "Items should be counted by code")
+	assert.Equal(t, Levels(Count(items)), []*gen.StockLevel{{Code: "a", Quantity: 1}, {Code: "b", Quantity: 2}}, "Counted items should be listed by code")
+}
@@ -0,0 +1,97 @@
+// Package tlsconfig builds the TLS settings the service listens with and
+// connects to the sorting robot with.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// Server builds the TLS settings of the service's server. Without a
+// certificate the server listens insecurely and nil is returned. With a
+// client CA, clients must present a certificate signed by it.
+func Server(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	certificates, err := loadCertificates(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if certificates == nil {
+		if clientCAFile != "" {
+			return nil, fmt.Errorf("a client CA requires a certificate and key")
+		}
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		Certificates: certificates,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		clientCAs, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// Client builds the TLS settings for connecting to a server whose
+// certificate is signed by the CA in caFile. Without a CA the connection is
+// insecure and nil is returned. A certificate and key, if given, are
+// presented to servers that require mutual TLS.
+func Client(caFile, certFile, keyFile string) (*tls.Config, error) {
+	certificates, err := loadCertificates(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if caFile == "" {
+		if certificates != nil {
+			return nil, fmt.Errorf("a client certificate requires a CA")
+		}
+		return nil, nil
+	}
+
+	rootCAs, err := loadCertPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: certificates,
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func loadCertificates(certFile, keyFile string) ([]tls.Certificate, error) {
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("a certificate and its key must be given together")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate %s: %v", certFile, err)
+	}
+	return []tls.Certificate{certificate}, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+	return pool, nil
+}
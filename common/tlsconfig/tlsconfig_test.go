@@ -0,0 +1,189 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Emoto13/sort-system/common/devcerts"
+	"github.com/stretchr/testify/assert"
+)
+
+// certFiles holds the files written by withCerts and the settings of a
+// client that trusts them.
+type certFiles struct {
+	caFile, certFile, keyFile string
+	client                    *tls.Config
+}
+
+// withCerts writes a CA and a server certificate for localhost, and builds
+// client settings that present a certificate from the same CA.
+func withCerts(t *testing.T) certFiles {
+	dir, err := ioutil.TempDir("", "certs")
+	assert.Equal(t, err, nil, "Creating a certificate directory should succeed")
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	authority, err := devcerts.NewAuthority("test CA")
+	assert.Equal(t, err, nil, "Creating a CA should succeed")
+	serverCert, serverKey, err := authority.Issue("sorting-service", "localhost")
+	assert.Equal(t, err, nil, "Issuing a server certificate should succeed")
+	clientCert, clientKey, err := authority.Issue("fulfillment-service")
+	assert.Equal(t, err, nil, "Issuing a client certificate should succeed")
+
+	files := certFiles{
+		caFile:   filepath.Join(dir, "ca.pem"),
+		certFile: filepath.Join(dir, "sorting-service.pem"),
+		keyFile:  filepath.Join(dir, "sorting-service-key.pem"),
+	}
+	assert.Equal(t, ioutil.WriteFile(files.caFile, authority.CertificatePEM, 0644), nil, "Writing the CA should succeed")
+	assert.Equal(t, ioutil.WriteFile(files.certFile, serverCert, 0644), nil, "Writing the certificate should succeed")
+	assert.Equal(t, ioutil.WriteFile(files.keyFile, serverKey, 0600), nil, "Writing the key should succeed")
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(authority.CertificatePEM)
+	clientCertificate, err := tls.X509KeyPair(clientCert, clientKey)
+	assert.Equal(t, err, nil, "The client certificate should load")
+	files.client = &tls.Config{ServerName: "localhost", RootCAs: roots, Certificates: []tls.Certificate{clientCertificate}}
+	return files
+}
+
+// handshake connects a client to a server over the loopback interface, so
+// that neither side blocks writing while the other one is writing too.
+func handshake(server, client *tls.Config) (serverErr error, clientErr error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err, err
+	}
+	defer lis.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- tls.Server(conn, server).Handshake()
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		return err, err
+	}
+	defer conn.Close()
+
+	clientErr = tls.Client(conn, client).Handshake()
+	if clientErr != nil {
+		conn.Close()
+	}
+	return <-done, clientErr
+}
+
+func TestServerWithoutCertificateIsInsecure(t *testing.T) {
+	config, err := Server("", "", "")
+	assert.Equal(t, err, nil, "No TLS settings should be valid")
+	assert.Nil(t, config, "Without a certificate the server should listen insecurely")
+
+	_, err = Server("", "", "ca.pem")
+	assert.NotNil(t, err, "A client CA without a certificate should be rejected")
+	_, err = Server("cert.pem", "", "")
+	assert.NotNil(t, err, "A certificate without a key should be rejected")
+}
+
+func TestServerTLS(t *testing.T) {
+	files := withCerts(t)
+
+	config, err := Server(files.certFile, files.keyFile, "")
+	assert.Equal(t, err, nil, "The server certificate should load")
+
+	clientWithoutCert := files.client.Clone()
+	clientWithoutCert.Certificates = nil
+	serverErr, clientErr := handshake(config, clientWithoutCert)
+	assert.Equal(t, serverErr, nil, "Without mutual TLS the server should not ask for a client certificate")
+	assert.Equal(t, clientErr, nil, "The client should trust a server certificate from the CA")
+
+	untrusting := files.client.Clone()
+	untrusting.RootCAs = x509.NewCertPool()
+	_, clientErr = handshake(config, untrusting)
+	assert.NotNil(t, clientErr, "A client that does not trust the CA should refuse the server")
+}
+
+func TestServerMutualTLS(t *testing.T) {
+	files := withCerts(t)
+
+	config, err := Server(files.certFile, files.keyFile, files.caFile)
+	assert.Equal(t, err, nil, "The server certificate and client CA should load")
+
+	serverErr, clientErr := handshake(config, files.client)
+	assert.Equal(t, serverErr, nil, "The server should accept a client certificate from the CA")
+	assert.Equal(t, clientErr, nil, "The client should trust a server certificate from the CA")
+
+	clientWithoutCert := files.client.Clone()
+	clientWithoutCert.Certificates = nil
+	serverErr, _ = handshake(config, clientWithoutCert)
+	assert.NotNil(t, serverErr, "The server should refuse a client without a certificate")
+
+	otherAuthority, err := devcerts.NewAuthority("other CA")
+	assert.Equal(t, err, nil, "Creating a CA should succeed")
+	otherCert, otherKey, err := otherAuthority.Issue("intruder")
+	assert.Equal(t, err, nil, "Issuing a certificate should succeed")
+	otherCertificate, err := tls.X509KeyPair(otherCert, otherKey)
+	assert.Equal(t, err, nil, "The certificate should load")
+
+	intruder := files.client.Clone()
+	intruder.Certificates = []tls.Certificate{otherCertificate}
+	serverErr, _ = handshake(config, intruder)
+	assert.NotNil(t, serverErr, "The server should refuse a client certificate from another CA")
+}
+
+func TestClientWithoutCAIsInsecure(t *testing.T) {
+	config, err := Client("", "", "")
+	assert.Equal(t, err, nil, "No TLS settings should be valid")
+	assert.Nil(t, config, "Without a CA the client should connect insecurely")
+
+	tests := []struct {
+		name                      string
+		caFile, certFile, keyFile string
+	}{
+		{"A certificate without a CA", "", "cert.pem", "key.pem"},
+		{"A certificate without a key", "ca.pem", "cert.pem", ""},
+		{"A missing CA file", filepath.Join(os.TempDir(), "missing", "ca.pem"), "", ""},
+	}
+	for _, test := range tests {
+		_, err := Client(test.caFile, test.certFile, test.keyFile)
+		assert.NotNil(t, err, test.name+" should be rejected")
+	}
+}
+
+func TestClientTLS(t *testing.T) {
+	files := withCerts(t)
+
+	server, err := Server(files.certFile, files.keyFile, files.caFile)
+	assert.Equal(t, err, nil, "The server certificate and client CA should load")
+
+	// The services use the same certificate as server and as client.
+	client, err := Client(files.caFile, files.certFile, files.keyFile)
+	assert.Equal(t, err, nil, "The CA and client certificate should load")
+	client.ServerName = "localhost"
+	serverErr, clientErr := handshake(server, client)
+	assert.Equal(t, serverErr, nil, "The server should accept the client's certificate")
+	assert.Equal(t, clientErr, nil, "The client should trust the server's certificate")
+
+	withoutCert, err := Client(files.caFile, "", "")
+	assert.Equal(t, err, nil, "A CA without a client certificate should load")
+	withoutCert.ServerName = "localhost"
+	serverErr, _ = handshake(server, withoutCert)
+	assert.NotNil(t, serverErr, "A server that requires mutual TLS should refuse a client without a certificate")
+
+	emptyCA := filepath.Join(filepath.Dir(files.caFile), "empty.pem")
+	assert.Equal(t, ioutil.WriteFile(emptyCA, []byte("no certificates"), 0644), nil, "Writing the CA should succeed")
+	_, err = Client(emptyCA, "", "")
+	assert.NotNil(t, err, "A CA file without certificates should be rejected")
+}
@@ -0,0 +1,17 @@
+// Package token makes the random tokens the robots hand out for picks and
+// leases.
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New returns a random token of 32 hex digits.
+func New() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,17 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	first, err := New()
+	assert.Equal(t, err, nil, "Making a token should succeed")
+	second, err := New()
+	assert.Equal(t, err, nil, "Making a token should succeed")
+
+	assert.Equal(t, len(first), 32, "A token should have 32 hex digits")
+	assert.NotEqual(t, first, second, "Tokens should differ")
+}
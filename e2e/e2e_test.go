@@ -0,0 +1,138 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEndToEnd_SortsOrders(t *testing.T) {
+	h := newHarness(t, 10)
+	orders := newOrders("order", 3, 2)
+
+	h.loadOrders(orders)
+	statuses := h.waitForOrders(orders)
+
+	for _, order := range orders {
+		assert.Equal(t, statuses[order.Id], gen.OrderStatus_READY, "Every order should be ready")
+	}
+	h.assertSorted(orders)
+}
+
+func TestEndToEnd_SortsBatchesOneAfterAnother(t *testing.T) {
+	h := newHarness(t, 10)
+	first := newOrders("first", 2, 2)
+	second := newOrders("second", 3, 1)
+
+	h.loadOrders(first)
+	h.waitForOrders(first)
+	h.loadOrders(second)
+	statuses := h.waitForOrders(second)
+
+	for _, order := range second {
+		assert.Equal(t, statuses[order.Id], gen.OrderStatus_READY, "Every order of the second batch should be ready")
+	}
+	h.assertSorted(append(first, second...))
+}
+
+func TestEndToEnd_ContinuesAfterEmergencyStop(t *testing.T) {
+	h := newHarness(t, 10)
+	orders := newOrders("order", 2, 2)
+	h.loadItems(orders[0].Items)
+	h.loadItems(orders[1].Items)
+
+	_, err := h.robotClient.EmergencyStop(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "Stopping the robot should succeed")
+
+	_, err = h.client.LoadOrders(context.Background(), &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Orders should be accepted while the robot is stopped")
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, h.orderStatus(orders[0].Id).Status, gen.OrderStatus_PENDING, "Orders should wait while the robot is stopped")
+
+	_, err = h.robotClient.Resume(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "Resuming the robot should succeed")
+
+	statuses := h.waitForOrders(orders)
+	for _, order := range orders {
+		assert.Equal(t, statuses[order.Id], gen.OrderStatus_READY, "Every order should be ready once the robot is resumed")
+	}
+	h.assertSorted(orders)
+}
+
+func TestEndToEnd_RetriesFailedScan(t *testing.T) {
+	h := newHarness(t, 10)
+	orders := newOrders("order", 1, 2)
+
+	h.injectFault(gen.FaultType_SCAN_FAILURE)
+
+	h.loadOrders(orders)
+	statuses := h.waitForOrders(orders)
+
+	assert.Equal(t, statuses[orders[0].Id], gen.OrderStatus_READY, "The order should be ready after the item is scanned again")
+	h.assertSorted(orders)
+}
+
+func TestEndToEnd_FailsOrderWithDroppedItem(t *testing.T) {
+	h := newHarness(t, 10)
+	orders := newOrders("order", 1, 2)
+
+	h.injectFault(gen.FaultType_DROP)
+
+	h.loadOrders(orders)
+	statuses := h.waitForOrders(orders)
+
+	assert.Equal(t, statuses[orders[0].Id], gen.OrderStatus_FAILED, "The order should fail when one of its items is dropped")
+
+	cubbyId := h.orderStatus(orders[0].Id).Cubby.GetId()
+	assert.Equal(t, len(h.auditCubbies()[cubbyId]), 1, "Only the item that was not dropped should be in the cubby")
+	h.assertSorted(orders)
+}
+
+func TestEndToEnd_AuditFindsMisSortedItem(t *testing.T) {
+	h := newHarness(t, 10)
+	orders := newOrders("order", 1, 1)
+
+	h.injectFault(gen.FaultType_MIS_SORT)
+
+	h.loadOrders(orders)
+	statuses := h.waitForOrders(orders)
+
+	assert.Equal(t, statuses[orders[0].Id], gen.OrderStatus_READY, "The fulfillment service should not notice the mis-sort")
+	assert.Equal(t, len(h.sortingErrors(orders)), 2, "The audit should find the item missing from its cubby and in another one")
+}
+
+func TestEndToEnd_ReturnsUnsortedOrdersOnShutdown(t *testing.T) {
+	h := newHarness(t, 10)
+	orders := newOrders("order", 2, 1)
+	h.loadItems(orders[0].Items)
+	h.loadItems(orders[1].Items)
+
+	_, err := h.robotClient.EmergencyStop(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "Stopping the robot should succeed")
+
+	_, err = h.client.LoadOrders(context.Background(), &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Orders should be accepted while the robot is stopped")
+
+	time.Sleep(100 * time.Millisecond)
+	unprocessed := h.shutdown()
+	assert.Equal(t, len(unprocessed), 2, "Both orders should be returned as unsorted")
+
+	_, err = h.client.LoadOrders(context.Background(), &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "Orders should be rejected after shutdown")
+}
+
+func TestEndToEnd_UnknownOrder(t *testing.T) {
+	h := newHarness(t, 10)
+
+	_, err := h.client.GetOrderFulfillmentStatusById(context.Background(), &gen.OrderIdRequest{OrderId: "missing"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "Looking up an unknown order should return NotFound")
+
+	_, err = h.client.MarkFulfilled(context.Background(), &gen.OrderIdRequest{OrderId: "missing"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "Fulfilling an unknown order should return NotFound")
+}
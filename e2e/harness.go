@@ -0,0 +1,272 @@
+// Package e2e runs the sorting robot and the fulfillment service together in
+// one process and checks how they sort orders. The harness loads items and
+// orders, waits for the orders to finish and audits the robot's cubbies
+// against them, for writing scenarios.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/lease"
+	"github.com/Emoto13/sort-system/fulfillment-service/robot"
+	"github.com/Emoto13/sort-system/fulfillment-service/service"
+	"github.com/Emoto13/sort-system/fulfillment-service/state"
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/Emoto13/sort-system/sorting-service/sortingrobot"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// harnessTimeout bounds how long a scenario waits for the system to finish.
+const harnessTimeout = 10 * time.Second
+
+// harness runs the sorting robot and the fulfillment service in process,
+// connected over in-memory listeners the same way the binaries connect over
+// the network. The fulfillment service holds the robot's lease.
+type harness struct {
+	t           *testing.T
+	robot       *sortingrobot.Robot
+	robotClient gen.SortingRobotClient
+	// leasedClient calls the robot with the fulfillment service's lease.
+	leasedClient gen.SortingRobotClient
+	fulfillment  service.FulfillmentService
+	client       gen.FulfillmentClient
+	shutDown     bool
+}
+
+func newHarness(t *testing.T, numberOfCubbies int) *harness {
+	h := &harness{t: t, robot: sortingrobot.New(&sortingrobot.Parameters{NumberOfCubbies: numberOfCubbies})}
+
+	robotServer := grpc.NewServer()
+	gen.RegisterSortingRobotServer(robotServer, h.robot)
+	robotLis := serveBufconn(t, robotServer)
+
+	robotLease := lease.NewKeeper("e2e", time.Minute, nil)
+	h.robotClient = gen.NewSortingRobotClient(dialBufconn(t, robotLis))
+	if err := robotLease.Acquire(context.Background(), h.robotClient); err != nil {
+		t.Fatalf("failed to acquire robot lease: %v", err)
+	}
+
+	leasedConn := dialBufconn(t, robotLis, grpc.WithUnaryInterceptor(robotLease.UnaryClientInterceptor()))
+	h.leasedClient = gen.NewSortingRobotClient(leasedConn)
+	h.fulfillment = service.New(&service.FulfillmentServiceParameters{
+		Robot:  robot.NewGRPC(h.leasedClient),
+		State:  state.New(numberOfCubbies),
+		Orders: make(chan []*gen.Order),
+	})
+	go h.fulfillment.ProcessOrders(context.Background())
+
+	fulfillmentServer := grpc.NewServer()
+	gen.RegisterFulfillmentServer(fulfillmentServer, h.fulfillment)
+	h.client = gen.NewFulfillmentClient(dialBufconn(t, serveBufconn(t, fulfillmentServer)))
+
+	t.Cleanup(func() {
+		h.shutdown()
+		h.robot.Shutdown()
+	})
+	return h
+}
+
+// serveBufconn serves server on a new in-memory listener.
+func serveBufconn(t *testing.T, server *grpc.Server) *bufconn.Listener {
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis
+}
+
+func dialBufconn(t *testing.T, lis *bufconn.Listener, opts ...grpc.DialOption) *grpc.ClientConn {
+	dialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+	opts = append([]grpc.DialOption{grpc.WithContextDialer(dialer), grpc.WithInsecure(), grpc.WithBlock()}, opts...)
+
+	conn, err := grpc.Dial("bufnet", opts...)
+	if err != nil {
+		t.Fatalf("failed to dial in-memory listener: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// shutdown stops the fulfillment service and returns the orders it did not
+// sort. It is safe to call more than once.
+func (h *harness) shutdown() []*gen.Order {
+	if h.shutDown {
+		return nil
+	}
+	h.shutDown = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), harnessTimeout)
+	defer cancel()
+	return h.fulfillment.Shutdown(ctx)
+}
+
+func (h *harness) loadItems(items []*gen.Item) {
+	h.t.Helper()
+
+	_, err := h.robotClient.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	assert.Equal(h.t, err, nil, "Loading items should succeed")
+}
+
+// injectFault makes the robot fail the fulfillment service's next matching
+// operation. Only the lease holder may inject faults.
+func (h *harness) injectFault(fault gen.FaultType) {
+	h.t.Helper()
+
+	_, err := h.leasedClient.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: fault})
+	assert.Equal(h.t, err, nil, "Injecting a fault should succeed")
+}
+
+// loadOrders stocks the robot with the orders' items and loads the orders.
+func (h *harness) loadOrders(orders []*gen.Order) {
+	h.t.Helper()
+
+	items := []*gen.Item{}
+	for _, order := range orders {
+		items = append(items, order.Items...)
+	}
+	h.loadItems(items)
+
+	_, err := h.client.LoadOrders(context.Background(), &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(h.t, err, nil, "Loading orders should succeed")
+}
+
+// waitForOrders waits until every one of the orders is ready or failed and
+// returns how each of them ended.
+func (h *harness) waitForOrders(orders []*gen.Order) map[string]gen.OrderStatus {
+	h.t.Helper()
+
+	deadline := time.Now().Add(harnessTimeout)
+	for {
+		statuses := map[string]gen.OrderStatus{}
+		for _, order := range orders {
+			resp, err := h.client.GetOrderFulfillmentStatusById(context.Background(), &gen.OrderIdRequest{OrderId: order.Id})
+			if err == nil && resp.FulfillmentStatus[0].Status != gen.OrderStatus_PENDING {
+				statuses[order.Id] = resp.FulfillmentStatus[0].Status
+			}
+		}
+		if len(statuses) == len(orders) {
+			return statuses
+		}
+
+		if time.Now().After(deadline) {
+			h.t.Fatalf("only %d of %d orders finished within %v", len(statuses), len(orders), harnessTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// orderStatus returns the order's status and the cubby it was assigned.
+func (h *harness) orderStatus(orderId string) *gen.FulfillmentStatus {
+	h.t.Helper()
+
+	resp, err := h.client.GetOrderFulfillmentStatusById(context.Background(), &gen.OrderIdRequest{OrderId: orderId})
+	if err != nil {
+		h.t.Fatalf("failed to get order %s: %v", orderId, err)
+	}
+	return resp.FulfillmentStatus[0]
+}
+
+// auditCubbies returns the sorted item codes in each cubby, as the robot
+// reports them.
+func (h *harness) auditCubbies() map[string][]string {
+	h.t.Helper()
+
+	resp, err := h.robotClient.AuditState(context.Background(), &gen.Empty{})
+	if err != nil {
+		h.t.Fatalf("failed to audit the robot: %v", err)
+	}
+
+	cubbies := map[string][]string{}
+	for _, cubbyToItems := range resp.CubbiesToItems {
+		cubbies[cubbyToItems.Cubby.Id] = itemCodes(cubbyToItems.Items)
+	}
+	return cubbies
+}
+
+// sortingErrors compares the robot's cubbies with where the fulfillment
+// service says the orders are. Every item of a ready order must be in the
+// order's cubby, and every item in a cubby must belong to an order that was
+// assigned that cubby.
+func (h *harness) sortingErrors(orders []*gen.Order) []string {
+	h.t.Helper()
+
+	audited := h.auditCubbies()
+	expected := map[string][]string{}
+	errors := []string{}
+	for _, order := range orders {
+		fulfillmentStatus := h.orderStatus(order.Id)
+		cubbyId := fulfillmentStatus.Cubby.GetId()
+		expected[cubbyId] = append(expected[cubbyId], itemCodes(order.Items)...)
+
+		if fulfillmentStatus.Status != gen.OrderStatus_READY {
+			continue
+		}
+		if missing := subtract(itemCodes(order.Items), audited[cubbyId]); len(missing) > 0 {
+			errors = append(errors, fmt.Sprintf("order %s is ready but cubby %s is missing %v", order.Id, cubbyId, missing))
+		}
+	}
+
+	for cubbyId, codes := range audited {
+		if stray := subtract(codes, expected[cubbyId]); len(stray) > 0 {
+			errors = append(errors, fmt.Sprintf("cubby %s holds %v of no order assigned to it", cubbyId, stray))
+		}
+	}
+	sort.Strings(errors)
+	return errors
+}
+
+// assertSorted checks that the robot's cubbies hold what the orders say.
+func (h *harness) assertSorted(orders []*gen.Order) {
+	h.t.Helper()
+
+	assert.Equal(h.t, h.sortingErrors(orders), []string{}, "The robot's cubbies should match the orders")
+}
+
+func itemCodes(items []*gen.Item) []string {
+	codes := []string{}
+	for _, item := range items {
+		codes = append(codes, item.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// subtract returns the codes in a that are not in b, counting duplicates.
+func subtract(a, b []string) []string {
+	left := map[string]int{}
+	for _, code := range b {
+		left[code]++
+	}
+
+	missing := []string{}
+	for _, code := range a {
+		if left[code] > 0 {
+			left[code]--
+			continue
+		}
+		missing = append(missing, code)
+	}
+	return missing
+}
+
+// newOrders makes n orders of itemsPerOrder items with unique codes.
+func newOrders(prefix string, n, itemsPerOrder int) []*gen.Order {
+	orders := []*gen.Order{}
+	for i := 1; i <= n; i++ {
+		order := &gen.Order{Id: fmt.Sprintf("%s-%d", prefix, i)}
+		for j := 1; j <= itemsPerOrder; j++ {
+			code := fmt.Sprintf("%s-%d-%d", prefix, i, j)
+			order.Items = append(order.Items, &gen.Item{Code: code, Label: code})
+		}
+		orders = append(orders, order)
+	}
+	return orders
+}
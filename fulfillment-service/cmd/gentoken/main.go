@@ -0,0 +1,72 @@
+// Command gentoken issues a signed bearer token for development and tests:
+//
+//	go run ./cmd/gentoken -signing-key-file=../certs/auth.key -subject=fulfillment-service -roles=fulfillment
+//
+// If the signing key file does not exist, a new random key is written to it.
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/auth"
+)
+
+var (
+	signingKeyFile = flag.String("signing-key-file", "auth.key", "key the token is signed with")
+	subject        = flag.String("subject", "", "who the token is issued to")
+	roles          = flag.String("roles", "", "comma separated roles: intake, operator or fulfillment")
+	ttl            = flag.Duration("ttl", 0, "how long the token is valid, 0 never expires")
+)
+
+func main() {
+	flag.Parse()
+
+	if *subject == "" {
+		log.Fatalf("subject is required")
+	}
+
+	if _, err := os.Stat(*signingKeyFile); os.IsNotExist(err) {
+		writeSigningKey(*signingKeyFile)
+	}
+
+	signingKey, err := auth.LoadSigningKey(*signingKeyFile)
+	if err != nil {
+		log.Fatalf("failed to load signing key: %v", err)
+	}
+
+	claims := auth.Claims{Subject: *subject}
+	for _, role := range strings.Split(*roles, ",") {
+		if role != "" {
+			claims.Roles = append(claims.Roles, auth.Role(role))
+		}
+	}
+	if *ttl > 0 {
+		claims.ExpiresAt = time.Now().Add(*ttl).Unix()
+	}
+
+	token, err := auth.Sign(signingKey, claims)
+	if err != nil {
+		log.Fatalf("failed to sign token: %v", err)
+	}
+	fmt.Println(token)
+}
+
+func writeSigningKey(file string) {
+	signingKey := make([]byte, 32)
+	if _, err := rand.Read(signingKey); err != nil {
+		log.Fatalf("failed to generate signing key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(base64.StdEncoding.EncodeToString(signingKey)+"\n"), 0600); err != nil {
+		log.Fatalf("failed to write signing key: %v", err)
+	}
+	fmt.Fprintln(os.Stderr, "Wrote new signing key to", file)
+}
@@ -0,0 +1,44 @@
+// Package connect dials the services from the command line tools, with the
+// TLS settings and bearer token given in the tools' flags.
+package connect
+
+import (
+	"flag"
+
+	"github.com/Emoto13/sort-system/common/auth"
+	"github.com/Emoto13/sort-system/common/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	tokenFile = flag.String("token-file", "", "file with the bearer token sent with every call")
+	caFile    = flag.String("tls-ca-file", "", "CA the services' certificates are signed by, enables TLS")
+	certFile  = flag.String("tls-cert-file", "", "certificate presented to services that require mutual TLS")
+	keyFile   = flag.String("tls-key-file", "", "private key of tls-cert-file")
+)
+
+// Dial connects to a service. It must be called after the flags are parsed.
+func Dial(address string) (*grpc.ClientConn, error) {
+	tlsConfig, err := tlsconfig.Client(*caFile, *certFile, *keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if *tokenFile != "" {
+		token, err := auth.LoadToken(*tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(token))
+	}
+
+	return grpc.Dial(address, opts...)
+}
@@ -0,0 +1,221 @@
+// Command loadgen measures how many orders the system sustains. It generates
+// random orders and the items that make them up, and at the given rate loads
+// each batch's items into the sorting robot and the batch into the fulfillment
+// service. It then polls until every order is ready or failed and reports
+// throughput, latency and failures. Orders the service rejects for lack of
+// stock are reported on their own and not waited for:
+//
+//	go run ./cmd/loadgen -orders=200 -batch-size=10 -rate=120
+//
+// Every batch's items go into the robot's input bin as it is submitted. When
+// batches are submitted faster than the robot sorts them, items of later
+// batches are picked for earlier ones and orders fail, so the failure count
+// shows when the rate is above what the system sustains.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/cmd/internal/connect"
+	"github.com/Emoto13/sort-system/gen"
+)
+
+var (
+	robotAddress       = flag.String("robot-address", "localhost:10000", "address of the sorting robot")
+	fulfillmentAddress = flag.String("fulfillment-address", "localhost:10001", "address of the fulfillment service")
+	numberOfOrders     = flag.Int("orders", 100, "number of orders to submit")
+	batchSize          = flag.Int("batch-size", 5, "number of orders submitted in one call")
+	rate               = flag.Float64("rate", 60, "orders submitted per minute, 0 submits them all at once")
+	minItems           = flag.Int("min-items", 1, "fewest items in an order")
+	maxItems           = flag.Int("max-items", 4, "most items in an order")
+	seed               = flag.Int64("seed", 1, "seed for generating orders, the same seed generates the same orders")
+	idPrefix           = flag.String("id-prefix", "", "prefix of generated order ids and item codes, defaults to a new one every run")
+	pollInterval       = flag.Duration("poll-interval", 500*time.Millisecond, "how often order status is polled")
+	timeout            = flag.Duration("timeout", 10*time.Minute, "how long to wait for every order to finish")
+	output             = flag.String("output", "table", "report format, table or json")
+)
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	if err := validateFlags(); err != nil {
+		log.Fatalf("invalid flags: %v", err)
+	}
+	if *idPrefix == "" {
+		*idPrefix = fmt.Sprintf("lg%d", time.Now().Unix())
+	}
+
+	robotConn, err := connect.Dial(*robotAddress)
+	if err != nil {
+		log.Fatalf("failed to connect to sorting robot: %v", err)
+	}
+	defer robotConn.Close()
+
+	fulfillmentConn, err := connect.Dial(*fulfillmentAddress)
+	if err != nil {
+		log.Fatalf("failed to connect to fulfillment service: %v", err)
+	}
+	defer fulfillmentConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	orders := generateOrders(*idPrefix, *seed, *numberOfOrders, *minItems, *maxItems)
+	run := newRun(orders)
+	log.Printf("Submitting %d orders with %d items as %s.", len(orders), run.items, *idPrefix)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		submit(ctx, run, gen.NewSortingRobotClient(robotConn), gen.NewFulfillmentClient(fulfillmentConn), orders)
+	}()
+
+	poll(ctx, run, gen.NewFulfillmentClient(fulfillmentConn))
+	wg.Wait()
+
+	if err := run.report().print(os.Stdout, *output); err != nil {
+		log.Fatalf("failed to print report: %v", err)
+	}
+}
+
+func validateFlags() error {
+	if *numberOfOrders <= 0 {
+		return fmt.Errorf("orders must be positive, got %d", *numberOfOrders)
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch-size must be positive, got %d", *batchSize)
+	}
+	if *rate < 0 {
+		return fmt.Errorf("rate must not be negative, got %v", *rate)
+	}
+	if *minItems <= 0 || *maxItems < *minItems {
+		return fmt.Errorf("items per order must be between a positive min-items and max-items, got %d and %d", *minItems, *maxItems)
+	}
+	if *pollInterval <= 0 {
+		return fmt.Errorf("poll-interval must be positive, got %v", *pollInterval)
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("output must be table or json, got %q", *output)
+	}
+	return nil
+}
+
+// submit loads the orders batch by batch at the configured rate.
+func submit(ctx context.Context, run *run, robot gen.SortingRobotClient, fulfillment gen.FulfillmentClient, orders []*gen.Order) {
+	interval := time.Duration(0)
+	if *rate > 0 {
+		interval = time.Duration(float64(*batchSize) / *rate * float64(time.Minute))
+	}
+
+	next := time.Now()
+	for start := 0; start < len(orders); start += *batchSize {
+		end := start + *batchSize
+		if end > len(orders) {
+			end = len(orders)
+		}
+		batch := orders[start:end]
+
+		select {
+		case <-ctx.Done():
+			run.notSubmitted(orders[start:])
+			return
+		case <-time.After(time.Until(next)):
+		}
+		next = next.Add(interval)
+
+		items := []*gen.Item{}
+		for _, order := range batch {
+			items = append(items, order.Items...)
+		}
+
+		if _, err := robot.LoadItems(ctx, &gen.LoadItemsRequest{Items: items}); err != nil {
+			log.Println("Error while loading items occured: ", err.Error())
+			run.rejected(batch, "load-items")
+			continue
+		}
+
+		resp, err := fulfillment.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: batch})
+		if err != nil {
+			log.Println("Error while loading orders occured: ", err.Error())
+			run.rejected(batch, "load-orders")
+			continue
+		}
+
+		loaded, outOfStock := splitOrders(batch, resp.RejectedOrderIds)
+		run.outOfStock(outOfStock)
+		run.submitted(loaded, time.Now())
+	}
+	run.doneSubmitting()
+}
+
+// splitOrders separates the orders the fulfillment service rejected for
+// lack of stock, which it will never report, from the ones it loaded.
+func splitOrders(orders []*gen.Order, rejectedOrderIds []string) (loaded, rejected []*gen.Order) {
+	isRejected := map[string]bool{}
+	for _, orderId := range rejectedOrderIds {
+		isRejected[orderId] = true
+	}
+
+	for _, order := range orders {
+		if isRejected[order.Id] {
+			rejected = append(rejected, order)
+		} else {
+			loaded = append(loaded, order)
+		}
+	}
+	return loaded, rejected
+}
+
+// isFinished tells whether an order will not get any more items: it is
+// ready, failed, cancelled or released without its missing items.
+func isFinished(status *gen.FulfillmentStatus) bool {
+	switch status.Status {
+	case gen.OrderStatus_PENDING, gen.OrderStatus_BACKORDERED:
+		return false
+	case gen.OrderStatus_PARTIALLY_READY:
+		return status.PartialDecision != gen.PartialPolicy_HOLD_PARTIAL
+	default:
+		return true
+	}
+}
+
+// poll records how submitted orders end until all of them have or ctx is
+// done.
+func poll(ctx context.Context, run *run, fulfillment gen.FulfillmentClient) {
+	ticker := time.NewTicker(*pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		resp, err := fulfillment.GetAllOrdersFulfillmentStatus(ctx, &gen.Empty{})
+		if err != nil {
+			log.Println("Error while polling order status occured: ", err.Error())
+			run.pollFailed()
+			continue
+		}
+
+		now := time.Now()
+		for _, status := range resp.FulfillmentStatus {
+			if isFinished(status) {
+				run.finished(status.Order.GetId(), status.Status, now)
+			}
+		}
+
+		if run.complete() {
+			return
+		}
+	}
+}
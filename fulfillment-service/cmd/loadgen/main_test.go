@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/fakerobot"
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeFulfillment takes the orders it is loaded with, rejecting the call
+// numbered rejectCall and the orders in outOfStock, and reports them with
+// the status of statuses or READY.
+type fakeFulfillment struct {
+	gen.FulfillmentClient
+	rejectCall int
+	outOfStock map[string]bool
+	statuses   map[string]gen.OrderStatus
+	calls      int
+	orders     []*gen.Order
+	mu         sync.Mutex
+}
+
+func (f *fakeFulfillment) LoadOrders(ctx context.Context, in *gen.LoadOrdersRequest, opts ...grpc.CallOption) (*gen.CompleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls == f.rejectCall {
+		return nil, status.Error(codes.Unavailable, "fulfillment service is shutting down")
+	}
+	resp := &gen.CompleteResponse{Status: "loaded"}
+	for _, order := range in.Orders {
+		if f.outOfStock[order.Id] {
+			resp.RejectedOrderIds = append(resp.RejectedOrderIds, order.Id)
+			continue
+		}
+		f.orders = append(f.orders, order)
+	}
+	return resp, nil
+}
+
+func (f *fakeFulfillment) GetAllOrdersFulfillmentStatus(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.OrdersStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resp := &gen.OrdersStatusResponse{}
+	for _, order := range f.orders {
+		orderStatus, ok := f.statuses[order.Id]
+		if !ok {
+			orderStatus = gen.OrderStatus_READY
+		}
+		resp.FulfillmentStatus = append(resp.FulfillmentStatus, &gen.FulfillmentStatus{Order: order, Status: orderStatus})
+	}
+	return resp, nil
+}
+
+// withFlags sets flags for the duration of a test.
+func withFlags(t *testing.T, values map[string]string) {
+	for name, value := range values {
+		name, previous := name, flag.Lookup(name).Value.String()
+		assert.Equal(t, flag.Set(name, value), nil, "Setting "+name+" should succeed")
+		t.Cleanup(func() {
+			flag.Set(name, previous)
+		})
+	}
+}
+
+func TestValidateFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		valid bool
+	}{
+		{"Defaults", map[string]string{}, true},
+		{"All at once", map[string]string{"rate": "0"}, true},
+		{"No orders", map[string]string{"orders": "0"}, false},
+		{"Empty batches", map[string]string{"batch-size": "0"}, false},
+		{"Negative rate", map[string]string{"rate": "-1"}, false},
+		{"Orders without items", map[string]string{"min-items": "0"}, false},
+		{"Fewer most than fewest items", map[string]string{"min-items": "3", "max-items": "2"}, false},
+		{"Zero poll interval", map[string]string{"poll-interval": "0s"}, false},
+		{"Unknown output", map[string]string{"output": "yaml"}, false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			withFlags(t, test.flags)
+
+			err := validateFlags()
+			if test.valid {
+				assert.Equal(t, err, nil, test.name+" should be valid")
+			} else {
+				assert.NotNil(t, err, test.name+" should be rejected")
+			}
+		})
+	}
+}
+
+func TestIsFinished(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *gen.FulfillmentStatus
+		finished bool
+	}{
+		{"Pending", &gen.FulfillmentStatus{Status: gen.OrderStatus_PENDING}, false},
+		{"Backordered", &gen.FulfillmentStatus{Status: gen.OrderStatus_BACKORDERED}, false},
+		{"Ready", &gen.FulfillmentStatus{Status: gen.OrderStatus_READY}, true},
+		{"Failed", &gen.FulfillmentStatus{Status: gen.OrderStatus_FAILED}, true},
+		{"Cancelled", &gen.FulfillmentStatus{Status: gen.OrderStatus_CANCELLED}, true},
+		{"Released partially", &gen.FulfillmentStatus{Status: gen.OrderStatus_PARTIALLY_READY, PartialDecision: gen.PartialPolicy_RELEASE_PARTIAL}, true},
+		{"Held partially", &gen.FulfillmentStatus{Status: gen.OrderStatus_PARTIALLY_READY, PartialDecision: gen.PartialPolicy_HOLD_PARTIAL}, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, isFinished(test.status), test.finished, test.name+" should be told apart")
+	}
+}
+
+func TestSubmitAndPoll(t *testing.T) {
+	withFlags(t, map[string]string{"batch-size": "2", "rate": "0", "poll-interval": "1ms"})
+	orders := generateOrders("lg1", 1, 5, 1, 2)
+	robot := fakerobot.New(fakerobot.Scenario{})
+	fulfillment := &fakeFulfillment{statuses: map[string]gen.OrderStatus{orders[4].Id: gen.OrderStatus_FAILED}}
+	r := newRun(orders)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	submit(ctx, r, robot, fulfillment, orders)
+	poll(ctx, r, fulfillment)
+
+	assert.Equal(t, ctx.Err(), nil, "Polling should end once every order finished")
+	assert.Equal(t, len(robot.CallsTo("LoadItems")), 3, "The items of each batch should be loaded")
+	assert.Equal(t, fulfillment.calls, 3, "The orders should be loaded batch by batch")
+
+	rep := r.report()
+	assert.Equal(t, rep.Submitted, 5, "Every order should be submitted")
+	assert.Equal(t, rep.Ready, 4, "Ready orders should be counted")
+	assert.Equal(t, rep.Failed, 1, "Failed orders should be counted")
+	assert.Equal(t, rep.Items, r.items, "Every generated item should be counted")
+}
+
+func TestSubmitCountsRejectedBatches(t *testing.T) {
+	withFlags(t, map[string]string{"batch-size": "2", "rate": "0"})
+	orders := generateOrders("lg1", 1, 4, 1, 1)
+	robot := fakerobot.New(fakerobot.Scenario{Faults: []fakerobot.Fault{
+		{Method: "LoadItems", Call: 1, Err: status.Error(codes.Unavailable, "robot is restarting")},
+	}})
+	fulfillment := &fakeFulfillment{}
+	r := newRun(orders)
+
+	submit(context.Background(), r, robot, fulfillment, orders)
+
+	rep := r.report()
+	assert.Equal(t, rep.Submitted, 2, "Only the batch whose items were loaded should be submitted")
+	assert.Equal(t, rep.Rejected, 2, "The batch whose items were not loaded should be rejected")
+	assert.Equal(t, rep.Errors, map[string]int{"load-items": 1}, "The failed call should be counted")
+	assert.Equal(t, fulfillment.calls, 1, "Orders whose items were not loaded should not be loaded")
+}
+
+func TestSubmitLeavesOutOfStockOrders(t *testing.T) {
+	withFlags(t, map[string]string{"batch-size": "3", "rate": "0", "poll-interval": "1ms"})
+	orders := generateOrders("lg1", 1, 3, 1, 1)
+	fulfillment := &fakeFulfillment{outOfStock: map[string]bool{orders[1].Id: true}}
+	r := newRun(orders)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	submit(ctx, r, fakerobot.New(fakerobot.Scenario{}), fulfillment, orders)
+	poll(ctx, r, fulfillment)
+
+	assert.Equal(t, ctx.Err(), nil, "Polling should not wait for the order out of stock")
+	rep := r.report()
+	assert.Equal(t, rep.Submitted, 2, "The orders loaded should be submitted")
+	assert.Equal(t, rep.OutOfStock, 1, "The order out of stock should be counted on its own")
+	assert.Equal(t, rep.Rejected, 0, "No call should be rejected")
+	assert.Equal(t, rep.Ready, 2, "The orders loaded should finish")
+}
+
+func TestSubmitStopsWithContext(t *testing.T) {
+	withFlags(t, map[string]string{"batch-size": "1", "rate": "1"})
+	orders := generateOrders("lg1", 1, 3, 1, 1)
+	r := newRun(orders)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	submit(ctx, r, fakerobot.New(fakerobot.Scenario{}), &fakeFulfillment{}, orders)
+
+	rep := r.report()
+	assert.Equal(t, rep.Submitted, 1, "Only the first batch should be submitted before the context is done")
+	assert.Equal(t, rep.NotSubmitted, 2, "The batches left should be counted as not submitted")
+	assert.Equal(t, r.complete(), false, "The submitted order should still be waited for")
+}
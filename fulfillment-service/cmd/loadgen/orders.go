@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// catalog is what generated items are labelled as.
+var catalog = []string{
+	"tomato", "cucumber", "potato", "cheese", "glass", "fork", "english breakfast",
+	"beans in a can", "peaches", "oranges", "headphones", "keyboard", "book",
+	"water bottle", "juice", "toy", "teddy bear", "mug", "laptop", "mouse",
+}
+
+// generateOrders makes n orders of between minItems and maxItems items. Every
+// item has its own code, so the robot's input bin holds exactly the items the
+// orders need. The same seed and prefix give the same orders.
+func generateOrders(prefix string, seed int64, n, minItems, maxItems int) []*gen.Order {
+	random := rand.New(rand.NewSource(seed))
+
+	orders := []*gen.Order{}
+	itemNumber := 0
+	for i := 1; i <= n; i++ {
+		order := &gen.Order{Id: fmt.Sprintf("%s-%d", prefix, i)}
+		for j := minItems + random.Intn(maxItems-minItems+1); j > 0; j-- {
+			itemNumber++
+			order.Items = append(order.Items, &gen.Item{
+				Code:  fmt.Sprintf("%s-%d", prefix, itemNumber),
+				Label: catalog[random.Intn(len(catalog))],
+			})
+		}
+		orders = append(orders, order)
+	}
+	return orders
+}
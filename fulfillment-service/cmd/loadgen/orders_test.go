@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateOrders(t *testing.T) {
+	orders := generateOrders("lg1", 7, 50, 2, 4)
+	assert.Equal(t, len(orders), 50, "The requested number of orders should be generated")
+
+	codes := map[string]bool{}
+	for _, order := range orders {
+		assert.Equal(t, strings.HasPrefix(order.Id, "lg1-"), true, "Order ids should start with the prefix")
+		assert.Equal(t, len(order.Items) >= 2 && len(order.Items) <= 4, true, "Order "+order.Id+" should have between 2 and 4 items")
+		for _, item := range order.Items {
+			assert.Equal(t, codes[item.Code], false, "Item code "+item.Code+" should be used once")
+			assert.Equal(t, strings.HasPrefix(item.Code, "lg1-"), true, "Item codes should start with the prefix")
+			codes[item.Code] = true
+		}
+	}
+
+	assert.Equal(t, generateOrders("lg1", 7, 50, 2, 4), orders, "The same seed should generate the same orders")
+	assert.NotEqual(t, generateOrders("lg1", 8, 50, 2, 4), orders, "Another seed should generate other orders")
+}
+
+func TestGenerateOrdersOfOneSize(t *testing.T) {
+	for _, order := range generateOrders("lg1", 1, 10, 3, 3) {
+		assert.Equal(t, len(order.Items), 3, "Every order should have as many items as asked for")
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+type orderResult struct {
+	items       int
+	submittedAt time.Time
+	finishedAt  time.Time
+	status      gen.OrderStatus
+	finished    bool
+}
+
+// run tracks what happened to every generated order.
+type run struct {
+	orders             int
+	items              int
+	results            map[string]*orderResult
+	rejectedOrders     int
+	outOfStockOrders   int
+	notSubmittedOrders int
+	unfinished         int
+	submitting         bool
+	errors             map[string]int
+	firstSubmit        time.Time
+	lastFinish         time.Time
+	mu                 sync.Mutex
+}
+
+func newRun(orders []*gen.Order) *run {
+	r := &run{
+		orders:     len(orders),
+		results:    map[string]*orderResult{},
+		submitting: true,
+		errors:     map[string]int{},
+		mu:         sync.Mutex{},
+	}
+	for _, order := range orders {
+		r.items += len(order.Items)
+	}
+	return r
+}
+
+func (r *run) submitted(orders []*gen.Order, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.firstSubmit.IsZero() {
+		r.firstSubmit = at
+	}
+	for _, order := range orders {
+		r.results[order.Id] = &orderResult{items: len(order.Items), submittedAt: at}
+		r.unfinished++
+	}
+}
+
+func (r *run) rejected(orders []*gen.Order, call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rejectedOrders += len(orders)
+	r.errors[call]++
+}
+
+// outOfStock records orders the fulfillment service rejected as the robots
+// do not hold their items.
+func (r *run) outOfStock(orders []*gen.Order) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outOfStockOrders += len(orders)
+}
+
+func (r *run) notSubmitted(orders []*gen.Order) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notSubmittedOrders += len(orders)
+	r.submitting = false
+}
+
+func (r *run) doneSubmitting() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.submitting = false
+}
+
+func (r *run) pollFailed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors["poll"]++
+}
+
+// finished records how an order ended. Orders that were not submitted by
+// this run, or that already ended, are ignored.
+func (r *run) finished(orderId string, status gen.OrderStatus, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.results[orderId]
+	if !ok || result.finished {
+		return
+	}
+
+	result.finished = true
+	result.status = status
+	result.finishedAt = at
+	if at.After(r.lastFinish) {
+		r.lastFinish = at
+	}
+	r.unfinished--
+}
+
+func (r *run) complete() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !r.submitting && r.unfinished == 0
+}
+
+type latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type report struct {
+	Orders          int            `json:"orders"`
+	Items           int            `json:"items"`
+	Submitted       int            `json:"submitted"`
+	Rejected        int            `json:"rejected"`
+	OutOfStock      int            `json:"outOfStock"`
+	NotSubmitted    int            `json:"notSubmitted"`
+	Ready           int            `json:"ready"`
+	Failed          int            `json:"failed"`
+	Unfinished      int            `json:"unfinished"`
+	DurationSeconds float64        `json:"durationSeconds"`
+	OrdersPerMinute float64        `json:"ordersPerMinute"`
+	ItemsPerMinute  float64        `json:"itemsPerMinute"`
+	LatencySeconds  latency        `json:"latencySeconds"`
+	Errors          map[string]int `json:"errors"`
+}
+
+// report sums up the run. Throughput counts finished orders, ready or failed,
+// from the first submission to the last order finishing. Latency is from an
+// order's submission until it was seen finished, so it is only as precise as
+// the poll interval.
+func (r *run) report() report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rep := report{
+		Orders:       r.orders,
+		Items:        r.items,
+		Submitted:    len(r.results),
+		Rejected:     r.rejectedOrders,
+		OutOfStock:   r.outOfStockOrders,
+		NotSubmitted: r.notSubmittedOrders,
+		Unfinished:   r.unfinished,
+		Errors:       r.errors,
+	}
+
+	latencies := []time.Duration{}
+	finishedItems := 0
+	for _, result := range r.results {
+		if !result.finished {
+			continue
+		}
+
+		if result.status != gen.OrderStatus_READY {
+			rep.Failed++
+		} else {
+			rep.Ready++
+		}
+		finishedItems += result.items
+		latencies = append(latencies, result.finishedAt.Sub(result.submittedAt))
+	}
+
+	if len(latencies) > 0 {
+		elapsed := r.lastFinish.Sub(r.firstSubmit)
+		rep.DurationSeconds = elapsed.Seconds()
+		if elapsed > 0 {
+			rep.OrdersPerMinute = float64(len(latencies)) / elapsed.Minutes()
+			rep.ItemsPerMinute = float64(finishedItems) / elapsed.Minutes()
+		}
+
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		rep.LatencySeconds = latency{
+			P50: percentile(latencies, 0.50).Seconds(),
+			P90: percentile(latencies, 0.90).Seconds(),
+			P99: percentile(latencies, 0.99).Seconds(),
+			Max: latencies[len(latencies)-1].Seconds(),
+		}
+	}
+	return rep
+}
+
+// percentile picks the nearest rank from sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func (rep report) print(w io.Writer, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rep)
+	}
+
+	fmt.Fprintf(w, "Orders:      %d with %d items\n", rep.Orders, rep.Items)
+	fmt.Fprintf(w, "Submitted:   %d (rejected %d, out of stock %d, not submitted %d)\n", rep.Submitted, rep.Rejected, rep.OutOfStock, rep.NotSubmitted)
+	fmt.Fprintf(w, "Finished:    %d ready, %d failed, %d unfinished\n", rep.Ready, rep.Failed, rep.Unfinished)
+	fmt.Fprintf(w, "Duration:    %.1fs\n", rep.DurationSeconds)
+	fmt.Fprintf(w, "Throughput:  %.1f orders/min, %.1f items/min\n", rep.OrdersPerMinute, rep.ItemsPerMinute)
+	fmt.Fprintf(w, "Latency:     p50 %.2fs, p90 %.2fs, p99 %.2fs, max %.2fs\n", rep.LatencySeconds.P50, rep.LatencySeconds.P90, rep.LatencySeconds.P99, rep.LatencySeconds.Max)
+	fmt.Fprintf(w, "Errors:      %d load-items, %d load-orders, %d poll\n", rep.Errors["load-items"], rep.Errors["load-orders"], rep.Errors["poll"])
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{}
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Second)
+	}
+
+	tests := []struct {
+		name       string
+		durations  []time.Duration
+		percentile float64
+		expected   time.Duration
+	}{
+		{"Median", sorted, 0.50, 5 * time.Second},
+		{"90th percentile", sorted, 0.90, 9 * time.Second},
+		{"99th percentile", sorted, 0.99, 10 * time.Second},
+		{"Lowest rank", sorted, 0, 1 * time.Second},
+		{"Single duration", sorted[:1], 0.99, 1 * time.Second},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, percentile(test.durations, test.percentile), test.expected, test.name+" should pick the nearest rank")
+	}
+}
+
+func TestReport(t *testing.T) {
+	orders := generateOrders("lg1", 1, 4, 1, 1)
+	r := newRun(orders)
+	start := time.Now()
+
+	r.submitted(orders[:3], start)
+	r.rejected(orders[3:], "load-orders")
+	r.doneSubmitting()
+	r.finished(orders[0].Id, gen.OrderStatus_READY, start.Add(10*time.Second))
+	r.finished(orders[1].Id, gen.OrderStatus_FAILED, start.Add(20*time.Second))
+	r.finished(orders[1].Id, gen.OrderStatus_READY, start.Add(50*time.Second))
+	r.finished("another-run-1", gen.OrderStatus_READY, start.Add(50*time.Second))
+	assert.Equal(t, r.complete(), false, "The run should not be complete while an order has not finished")
+
+	r.finished(orders[2].Id, gen.OrderStatus_CANCELLED, start.Add(30*time.Second))
+	assert.Equal(t, r.complete(), true, "The run should be complete once every submitted order finished")
+
+	rep := r.report()
+	assert.Equal(t, rep.Orders, 4, "Every generated order should be counted")
+	assert.Equal(t, rep.Submitted, 3, "Submitted orders should be counted")
+	assert.Equal(t, rep.Rejected, 1, "Rejected orders should be counted")
+	assert.Equal(t, rep.Ready, 1, "Ready orders should be counted")
+	assert.Equal(t, rep.Failed, 2, "Orders that ended other than ready should count as failed")
+	assert.Equal(t, rep.Unfinished, 0, "No order should be left unfinished")
+	assert.Equal(t, rep.DurationSeconds, 30.0, "The run should last from the first submission to the last order finishing")
+	assert.Equal(t, rep.OrdersPerMinute, 6.0, "Throughput should count finished orders over the run")
+	assert.Equal(t, rep.LatencySeconds, latency{P50: 20, P90: 30, P99: 30, Max: 30}, "Latency should be taken from submission to finishing")
+	assert.Equal(t, rep.Errors, map[string]int{"load-orders": 1}, "Failed calls should be counted by call")
+}
+
+func TestReportWithoutFinishedOrders(t *testing.T) {
+	orders := generateOrders("lg1", 1, 2, 1, 1)
+	r := newRun(orders)
+	r.notSubmitted(orders)
+
+	rep := r.report()
+	assert.Equal(t, r.complete(), true, "A run that submitted nothing should be complete")
+	assert.Equal(t, rep.NotSubmitted, 2, "Orders not submitted should be counted")
+	assert.Equal(t, rep.OrdersPerMinute, 0.0, "There should be no throughput")
+	assert.Equal(t, rep.LatencySeconds, latency{}, "There should be no latency")
+}
+
+func TestPrint(t *testing.T) {
+	rep := report{Orders: 3, Items: 4, Submitted: 2, OutOfStock: 1, Ready: 1, Failed: 1, Errors: map[string]int{"poll": 2}}
+
+	out := &bytes.Buffer{}
+	assert.Equal(t, rep.print(out, "json"), nil, "The report should print as JSON")
+	printed := report{}
+	assert.Equal(t, json.Unmarshal(out.Bytes(), &printed), nil, "The printed report should be JSON")
+	assert.Equal(t, printed, rep, "The JSON report should hold every figure")
+
+	out.Reset()
+	assert.Equal(t, rep.print(out, "table"), nil, "The report should print as a table")
+	assert.Equal(t, strings.Contains(out.String(), "Submitted:   2 (rejected 0, out of stock 1, not submitted 0)"), true, "The table should show the orders that were not loaded")
+	assert.Equal(t, strings.Contains(out.String(), "Finished:    1 ready, 1 failed, 0 unfinished"), true, "The table should show how orders finished")
+	assert.Equal(t, strings.Contains(out.String(), "0 load-orders, 2 poll"), true, "The table should show failed calls")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// readItems reads a LoadItemsRequest, a JSON list of items or a code,label
+// CSV file.
+func readItems(file string) (*gen.LoadItemsRequest, error) {
+	in := &gen.LoadItemsRequest{}
+	if isCSV(file) {
+		rows, err := readCSV(file, "code", 2)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			in.Items = append(in.Items, &gen.Item{Code: row[0], Label: row[1]})
+		}
+		return in, nil
+	}
+
+	return in, readJSON(file, "items", in)
+}
+
+// readOrders reads a LoadOrdersRequest, a JSON list of orders or an
+// order,code,label CSV file with a row per item.
+func readOrders(file string) (*gen.LoadOrdersRequest, error) {
+	in := &gen.LoadOrdersRequest{}
+	if isCSV(file) {
+		rows, err := readCSV(file, "order", 3)
+		if err != nil {
+			return nil, err
+		}
+
+		orders := map[string]*gen.Order{}
+		for _, row := range rows {
+			order, ok := orders[row[0]]
+			if !ok {
+				order = &gen.Order{Id: row[0]}
+				orders[row[0]] = order
+				in.Orders = append(in.Orders, order)
+			}
+			order.Items = append(order.Items, &gen.Item{Code: row[1], Label: row[2]})
+		}
+		return in, nil
+	}
+
+	return in, readJSON(file, "orders", in)
+}
+
+func isCSV(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".csv")
+}
+
+// readJSON decodes a request, accepting a bare list as the value of field.
+func readJSON(file string, field string, in proto.Message) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("[")) {
+		content = []byte(fmt.Sprintf(`{"%s": %s}`, field, content))
+	}
+
+	if err := protojson.Unmarshal(content, in); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", file, err)
+	}
+	return nil
+}
+
+// readCSV reads rows of the given number of columns, skipping a header row
+// that starts with header. Only the last column, the label, may be empty.
+func readCSV(file string, header string, columns int) ([][]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = columns
+	reader.TrimLeadingSpace = true
+
+	rows := [][]string{}
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		if line == 1 && strings.EqualFold(row[0], header) {
+			continue
+		}
+		for column, value := range row[:columns-1] {
+			if strings.TrimSpace(value) == "" {
+				return nil, fmt.Errorf("failed to parse %s: record on line %d: column %d is empty", file, line, column+1)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withInput writes content to a file of the given name.
+func withInput(t *testing.T, name string, content string) string {
+	dir, err := ioutil.TempDir("", "sortctl")
+	assert.Equal(t, err, nil, "Creating an input directory should succeed")
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	file := filepath.Join(dir, name)
+	assert.Equal(t, ioutil.WriteFile(file, []byte(content), 0644), nil, "Writing the input should succeed")
+	return file
+}
+
+func TestReadItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		codes   []string
+	}{
+		{"CSV with a header", "items.csv", "code,label\n123,tomato\n456,cucumber\n", []string{"123", "456"}},
+		{"CSV without a header", "items.CSV", "123,tomato\n", []string{"123"}},
+		{"CSV without a label", "items.csv", "123,\n", []string{"123"}},
+		{"Request", "items.json", `{"items": [{"code": "123", "label": "tomato"}]}`, []string{"123"}},
+		{"Bare list", "items.json", ` [{"code": "123"}, {"code": "456"}]`, []string{"123", "456"}},
+		{"Empty CSV", "items.csv", "", nil},
+	}
+
+	for _, test := range tests {
+		in, err := readItems(withInput(t, test.file, test.content))
+		assert.Equal(t, err, nil, test.name+" should be read")
+
+		var codes []string
+		for _, item := range in.Items {
+			codes = append(codes, item.Code)
+		}
+		assert.Equal(t, codes, test.codes, test.name+" should give the items in order")
+	}
+}
+
+func TestReadMalformedItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"Missing label column", "items.csv", "123\n"},
+		{"Extra column", "items.csv", "123,tomato,red\n"},
+		{"Rows of different lengths", "items.csv", "123,tomato\n456\n"},
+		{"Missing code", "items.csv", "code,label\n,tomato\n"},
+		{"Blank code", "items.csv", "123,tomato\n  ,cucumber\n"},
+		{"Unterminated quote", "items.csv", "\"123,tomato\n"},
+		{"Malformed JSON", "items.json", `{"items": [`},
+		{"Unknown field", "items.json", `{"item": []}`},
+		{"Item that is not an object", "items.json", `["123"]`},
+		{"Code that is not a string", "items.json", `[{"code": 123}]`},
+		{"Orders instead of items", "items.json", `{"orders": []}`},
+	}
+
+	for _, test := range tests {
+		_, err := readItems(withInput(t, test.file, test.content))
+		assert.NotNil(t, err, test.name+" should be rejected")
+	}
+}
+
+func TestReadOrders(t *testing.T) {
+	in, err := readOrders(withInput(t, "orders.csv", "order,code,label\n1,123,tomato\n2,456,cucumber\n1,789,potato\n"))
+	assert.Equal(t, err, nil, "Orders should be read")
+	assert.Equal(t, len(in.Orders), 2, "The rows of an order should be grouped")
+	assert.Equal(t, in.Orders[0].Id, "1", "Orders should be given in the order they first appear")
+	assert.Equal(t, len(in.Orders[0].Items), 2, "Every row of an order should be an item")
+	assert.Equal(t, in.Orders[0].Items[1].Label, "potato", "Items should keep their labels")
+	assert.Equal(t, in.Orders[1].Id, "2", "Orders should be given in the order they first appear")
+
+	in, err = readOrders(withInput(t, "orders.json", `[{"id": "1", "items": [{"code": "123"}]}]`))
+	assert.Equal(t, err, nil, "A bare list of orders should be read")
+	assert.Equal(t, in.Orders[0].Items[0].Code, "123", "The items of an order should be read")
+}
+
+func TestReadMalformedOrders(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"Missing label column", "orders.csv", "1,123\n"},
+		{"Extra column", "orders.csv", "1,123,tomato,red\n"},
+		{"Missing order", "orders.csv", "order,code,label\n,123,tomato\n"},
+		{"Missing code", "orders.csv", "1,,tomato\n"},
+		{"Items header", "orders.csv", "code,label\n123,tomato\n"},
+		{"Malformed JSON", "orders.json", `[{"id": "1"`},
+		{"Unknown field", "orders.json", `[{"id": "1", "products": []}]`},
+		{"Items that are not a list", "orders.json", `[{"id": "1", "items": {"code": "123"}}]`},
+		{"Items instead of orders", "orders.json", `{"items": []}`},
+	}
+
+	for _, test := range tests {
+		_, err := readOrders(withInput(t, test.file, test.content))
+		assert.NotNil(t, err, test.name+" should be rejected")
+	}
+}
+
+func TestReadMissingInput(t *testing.T) {
+	missing := filepath.Join(os.TempDir(), "missing", "orders.csv")
+
+	_, err := readOrders(missing)
+	assert.NotNil(t, err, "A missing orders file should be rejected")
+	_, err = readItems(filepath.Join(os.TempDir(), "missing", "items.json"))
+	assert.NotNil(t, err, "A missing items file should be rejected")
+}
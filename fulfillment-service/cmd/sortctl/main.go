@@ -0,0 +1,332 @@
+// Command sortctl operates the sorting robot and the fulfillment service from
+// the command line:
+//
+//	sortctl load-items items.json     load items into the robot's input bin
+//	sortctl load-orders orders.csv    load a batch of orders
+//	sortctl orders [ID]               show order status
+//	sortctl cubbies                   show which order each cubby holds
+//	sortctl fulfill ID...             mark orders as picked up
+//	sortctl at-risk [WITHIN]          show orders due within WITHIN, e.g. 10m
+//	sortctl reservations              show the stock reserved for orders
+//	sortctl scan CODE                 show which cubby a scanned item goes into
+//	sortctl put CODE CUBBY            confirm putting an item into its cubby
+//	sortctl robot                     show the robot's status
+//	sortctl audit                     show the items in each cubby
+//	sortctl watch [SEQUENCE]          follow what the robot does
+//
+// Items and orders are read from JSON, in the form of the gRPC requests or as
+// a bare list, or from CSV with the columns code,label for items and
+// order,code,label for orders. Output is a table unless -output=json.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/cmd/internal/connect"
+	"github.com/Emoto13/sort-system/gen"
+)
+
+var (
+	robotAddress       = flag.String("robot-address", "localhost:10000", "address of the sorting robot")
+	fulfillmentAddress = flag.String("fulfillment-address", "localhost:10001", "address of the fulfillment service")
+	output             = flag.String("output", "table", "output format, table or json")
+	timeout            = flag.Duration("timeout", 10*time.Second, "how long a call may take, except for watch")
+)
+
+type command struct {
+	usage string
+	args  func(n int) bool
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"load-items":   {"FILE", exactly(1), loadItems},
+	"load-orders":  {"FILE", exactly(1), loadOrders},
+	"orders":       {"[ID]", atMost(1), showOrders},
+	"cubbies":      {"", exactly(0), showCubbies},
+	"fulfill":      {"ID...", atLeast(1), fulfill},
+	"at-risk":      {"[WITHIN]", atMost(1), showOrdersAtRisk},
+	"reservations": {"", exactly(0), showReservations},
+	"scan":         {"CODE", exactly(1), scan},
+	"put":          {"CODE CUBBY", exactly(2), put},
+	"robot":        {"", exactly(0), showRobot},
+	"audit":        {"", exactly(0), showAudit},
+	"watch":        {"[SEQUENCE]", atMost(1), watch},
+}
+
+var commandOrder = []string{"load-items", "load-orders", "orders", "cubbies", "fulfill", "at-risk", "reservations", "scan", "put", "robot", "audit", "watch"}
+
+func exactly(n int) func(int) bool { return func(got int) bool { return got == n } }
+func atMost(n int) func(int) bool  { return func(got int) bool { return got <= n } }
+func atLeast(n int) func(int) bool { return func(got int) bool { return got >= n } }
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	args := flag.Args()[1:]
+	if !ok || !cmd.args(len(args)) {
+		usage()
+		os.Exit(2)
+	}
+	if *output != "table" && *output != "json" {
+		log.Fatalf("output must be table or json, got %q", *output)
+	}
+
+	ctx := context.Background()
+	if flag.Arg(0) != "watch" {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	if err := cmd.run(ctx, args); err != nil {
+		log.Fatalf("%s: %v", flag.Arg(0), err)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: sortctl [flags] COMMAND [ARGS]\n\nCommands:\n")
+	for _, name := range commandOrder {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func sortingRobot() (gen.SortingRobotClient, func()) {
+	conn, err := connect.Dial(*robotAddress)
+	if err != nil {
+		log.Fatalf("failed to connect to sorting robot: %v", err)
+	}
+	return gen.NewSortingRobotClient(conn), func() { conn.Close() }
+}
+
+func fulfillment() (gen.FulfillmentClient, func()) {
+	conn, err := connect.Dial(*fulfillmentAddress)
+	if err != nil {
+		log.Fatalf("failed to connect to fulfillment service: %v", err)
+	}
+	return gen.NewFulfillmentClient(conn), func() { conn.Close() }
+}
+
+func loadItems(ctx context.Context, args []string) error {
+	in, err := readItems(args[0])
+	if err != nil {
+		return err
+	}
+
+	robot, closeConn := sortingRobot()
+	defer closeConn()
+
+	if _, err := robot.LoadItems(ctx, in); err != nil {
+		return err
+	}
+	return printMessage(in, func() {
+		fmt.Printf("Loaded %d items.\n", len(in.Items))
+	})
+}
+
+func loadOrders(ctx context.Context, args []string) error {
+	in, err := readOrders(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	resp, err := client.LoadOrders(ctx, in)
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		fmt.Printf("Loaded %d orders. %s\n", len(in.Orders), resp.Status)
+		if len(resp.RejectedOrderIds) > 0 {
+			fmt.Printf("Rejected for lack of stock: %s\n", strings.Join(resp.RejectedOrderIds, ", "))
+		}
+		if len(resp.BackorderedOrderIds) > 0 {
+			fmt.Printf("Backordered until stock arrives: %s\n", strings.Join(resp.BackorderedOrderIds, ", "))
+		}
+	})
+}
+
+func showOrders(ctx context.Context, args []string) error {
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	var resp *gen.OrdersStatusResponse
+	var err error
+	if len(args) == 1 {
+		resp, err = client.GetOrderFulfillmentStatusById(ctx, &gen.OrderIdRequest{OrderId: args[0]})
+	} else {
+		resp, err = client.GetAllOrdersFulfillmentStatus(ctx, &gen.Empty{})
+	}
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		printOrders(resp.FulfillmentStatus)
+	})
+}
+
+func showCubbies(ctx context.Context, args []string) error {
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	resp, err := client.GetAllOrdersFulfillmentStatus(ctx, &gen.Empty{})
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		printCubbies(resp.FulfillmentStatus)
+	})
+}
+
+func fulfill(ctx context.Context, args []string) error {
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	for _, orderId := range args {
+		if _, err := client.MarkFulfilled(ctx, &gen.OrderIdRequest{OrderId: orderId}); err != nil {
+			return fmt.Errorf("order %s: %v", orderId, err)
+		}
+		if *output == "table" {
+			fmt.Printf("Order %s was marked as fulfilled.\n", orderId)
+		}
+	}
+	return nil
+}
+
+// showOrdersAtRisk shows the orders that may miss their deadline, within the
+// service's default margin unless given.
+func showOrdersAtRisk(ctx context.Context, args []string) error {
+	in := &gen.OrdersAtRiskRequest{}
+	if len(args) == 1 {
+		within, err := time.ParseDuration(args[0])
+		if err != nil || within <= 0 {
+			return fmt.Errorf("invalid duration %q", args[0])
+		}
+		in.WithinMillis = within.Milliseconds()
+	}
+
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	resp, err := client.GetOrdersAtRisk(ctx, in)
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		printOrdersAtRisk(resp.Orders)
+	})
+}
+
+func showReservations(ctx context.Context, args []string) error {
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	resp, err := client.GetReservations(ctx, &gen.Empty{})
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		printReservations(resp)
+	})
+}
+
+func scan(ctx context.Context, args []string) error {
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	resp, err := client.ScanItem(ctx, &gen.ScanItemRequest{ItemCode: args[0]})
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		fmt.Printf("Put item %s into cubby %s.\n", args[0], resp.Cubby.Id)
+	})
+}
+
+func put(ctx context.Context, args []string) error {
+	client, closeConn := fulfillment()
+	defer closeConn()
+
+	if _, err := client.ConfirmPut(ctx, &gen.ConfirmPutRequest{ItemCode: args[0], CubbyId: args[1]}); err != nil {
+		return err
+	}
+	if *output == "table" {
+		fmt.Printf("Item %s was put into cubby %s.\n", args[0], args[1])
+	}
+	return nil
+}
+
+func showRobot(ctx context.Context, args []string) error {
+	robot, closeConn := sortingRobot()
+	defer closeConn()
+
+	resp, err := robot.GetRobotStatus(ctx, &gen.Empty{})
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		printRobotStatus(resp)
+	})
+}
+
+func showAudit(ctx context.Context, args []string) error {
+	robot, closeConn := sortingRobot()
+	defer closeConn()
+
+	resp, err := robot.AuditState(ctx, &gen.Empty{})
+	if err != nil {
+		return err
+	}
+	return printMessage(resp, func() {
+		printAudit(resp.CubbiesToItems)
+	})
+}
+
+// watch prints robot events until interrupted, starting after the given
+// sequence number so a watch can be resumed.
+func watch(ctx context.Context, args []string) error {
+	afterSequence := int64(0)
+	if len(args) == 1 {
+		var err error
+		if afterSequence, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			return fmt.Errorf("invalid sequence number %q", args[0])
+		}
+	}
+
+	robot, closeConn := sortingRobot()
+	defer closeConn()
+
+	stream, err := robot.WatchRobotEvents(ctx, &gen.WatchRobotEventsRequest{AfterSequence: afterSequence})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := printEvent(event); err != nil {
+			return err
+		}
+	}
+}
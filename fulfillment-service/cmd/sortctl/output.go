@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// printMessage prints m as JSON, or calls printTable for table output.
+func printMessage(m proto.Message, printTable func()) error {
+	if *output == "table" {
+		printTable()
+		return nil
+	}
+
+	content, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(content))
+	return nil
+}
+
+func newTable(header ...string) *tabwriter.Writer {
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+	return table
+}
+
+func printOrders(statuses []*gen.FulfillmentStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		return naturalLess(statuses[i].Order.GetId(), statuses[j].Order.GetId())
+	})
+
+	table := newTable("ORDER", "STATUS", "CUBBY", "WALL", "SLOT", "ITEMS", "MISSING")
+	for _, status := range statuses {
+		missing := "-"
+		if status.PartialDecision != gen.PartialPolicy_SYSTEM_POLICY {
+			missing = fmt.Sprintf("%s (%s)", itemLabels(status.MissingItems), status.PartialDecision)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", status.Order.GetId(), status.Status, status.Cubby.GetId(), status.Cubby.GetWallId(), status.Cubby.GetSlot(), itemLabels(status.Order.GetItems()), missing)
+	}
+	table.Flush()
+}
+
+func printOrdersAtRisk(orders []*gen.OrderAtRisk) {
+	table := newTable("ORDER", "PRIORITY", "READY BY", "LEFT", "STATUS", "CUBBY")
+	for _, order := range orders {
+		status := order.FulfillmentStatus
+		readyBy := time.Unix(0, status.Order.GetReadyByMillis()*int64(time.Millisecond)).Format("15:04:05")
+		left := (time.Duration(order.MillisLeft) * time.Millisecond).Round(time.Second)
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\t%s\t%s\n", status.Order.GetId(), status.Order.GetPriority(), readyBy, left, status.Status, status.Cubby.GetId())
+	}
+	table.Flush()
+}
+
+func printReservations(resp *gen.ReservationsResponse) {
+	table := newTable("ORDER", "STATE", "AGE", "ITEMS")
+	for _, reservation := range resp.Reservations {
+		fmt.Fprintf(table, "%s\treserved\t-\t%s\n", reservation.OrderId, stockLabels(reservation.Items))
+	}
+	for _, backorder := range resp.Backorders {
+		age := (time.Duration(backorder.AgeMillis) * time.Millisecond).Round(time.Second)
+		fmt.Fprintf(table, "%s\tbackordered\t%s\tmissing %s\n", backorder.OrderId, age, stockLabels(backorder.Missing))
+	}
+	fmt.Fprintf(table, "-\tavailable\t-\t%s\n", stockLabels(resp.Available))
+	table.Flush()
+}
+
+func stockLabels(levels []*gen.StockLevel) string {
+	labels := []string{}
+	for _, level := range levels {
+		labels = append(labels, fmt.Sprintf("%dx %s", level.Quantity, level.Code))
+	}
+	return strings.Join(labels, ", ")
+}
+
+func printCubbies(statuses []*gen.FulfillmentStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		return naturalLess(statuses[i].Cubby.GetId(), statuses[j].Cubby.GetId())
+	})
+
+	table := newTable("CUBBY", "WALL", "SLOT", "ORDER", "STATUS")
+	for _, status := range statuses {
+		if status.Cubby.GetId() == "" {
+			continue
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", status.Cubby.GetId(), status.Cubby.GetWallId(), status.Cubby.GetSlot(), status.Order.GetId(), status.Status)
+	}
+	table.Flush()
+}
+
+func printAudit(cubbies []*gen.CubbyToItems) {
+	sort.Slice(cubbies, func(i, j int) bool {
+		return naturalLess(cubbies[i].Cubby.GetId(), cubbies[j].Cubby.GetId())
+	})
+
+	table := newTable("CUBBY", "ITEMS")
+	for _, cubby := range cubbies {
+		fmt.Fprintf(table, "%s\t%s\n", cubby.Cubby.GetId(), itemLabels(cubby.Items))
+	}
+	table.Flush()
+}
+
+func printRobotStatus(status *gen.RobotStatus) {
+	selectedItem := "-"
+	if status.SelectedItem != nil {
+		selectedItem = itemLabels([]*gen.Item{status.SelectedItem})
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "State:\t%s\n", status.State)
+	fmt.Fprintf(table, "Selected item:\t%s\n", selectedItem)
+	fmt.Fprintf(table, "Items in bin:\t%d\n", status.ItemsInBin)
+	fmt.Fprintf(table, "Picked:\t%d\n", status.ItemsPicked)
+	fmt.Fprintf(table, "Placed:\t%d\n", status.ItemsPlaced)
+	fmt.Fprintf(table, "Returned:\t%d\n", status.ItemsReturned)
+	fmt.Fprintf(table, "Dropped:\t%d\n", status.ItemsDropped)
+	fmt.Fprintf(table, "Mis-sorted:\t%d\n", status.ItemsMisSorted)
+	fmt.Fprintf(table, "Scan failures:\t%d\n", status.ScanFailures)
+	fmt.Fprintf(table, "Selection timeouts:\t%d\n", status.SelectionTimeouts)
+	table.Flush()
+}
+
+// printEvent prints an event on one line, so watch output can be followed
+// and piped.
+func printEvent(event *gen.RobotEvent) error {
+	if *output == "json" {
+		content, err := protojson.Marshal(event)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(content))
+		return nil
+	}
+
+	timestamp := time.Unix(0, event.TimestampMillis*int64(time.Millisecond)).Format("15:04:05.000")
+	line := fmt.Sprintf("%d\t%s\t%s", event.Sequence, timestamp, event.Type)
+	if event.Item != nil {
+		line += "\titem=" + itemLabels([]*gen.Item{event.Item})
+	}
+	if event.Cubby != nil {
+		line += "\tcubby=" + event.Cubby.Id
+	}
+	if event.ItemCount != 0 {
+		line += fmt.Sprintf("\tcount=%d", event.ItemCount)
+	}
+	if event.Type == gen.RobotEventType_FAULT {
+		line += "\tfault=" + event.Fault.String()
+	}
+	if event.Message != "" {
+		line += "\t" + event.Message
+	}
+	fmt.Println(line)
+	return nil
+}
+
+func itemLabels(items []*gen.Item) string {
+	labels := []string{}
+	for _, item := range items {
+		labels = append(labels, fmt.Sprintf("%s (%s)", item.Label, item.Code))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// naturalLess orders numeric ids by value and the rest alphabetically.
+func naturalLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
@@ -0,0 +1,358 @@
+// Package fakerobot is a stand-in for the sorting robot's gRPC client, so the
+// fulfillment service can be tested without a running robot. A Scenario
+// scripts which items the robot picks, which calls fail and how long calls
+// take, and the Robot records every call it gets.
+package fakerobot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fault makes the Nth call to a method fail with Err. Calls are counted from
+// 1 for every method separately, e.g. {"MoveItem", 2, err} fails the second
+// MoveItem.
+type Fault struct {
+	Method string
+	Call   int
+	Err    error
+}
+
+// Scenario describes how the fake robot behaves.
+type Scenario struct {
+	// Picks are the items SelectItem returns, in order. Items loaded with
+	// LoadItems or returned with ReturnItem are picked after them. SelectItem
+	// fails once there is nothing left to pick.
+	Picks []*gen.Item
+	// Faults are the calls that fail.
+	Faults []Fault
+	// States are what GetRobotStatus reports, one per call. The last state
+	// is repeated once they run out, and the robot is IDLE if there are none.
+	States []gen.RobotState
+	// Latency is how long every call takes, unless its context is done first.
+	Latency time.Duration
+}
+
+// Call is a call the robot got: the method, its request and the error it
+// returned.
+type Call struct {
+	Method  string
+	Request interface{}
+	Err     error
+}
+
+// Move is an item the robot put into a cubby.
+type Move struct {
+	ItemCode string
+	CubbyId  string
+}
+
+// Robot implements gen.SortingRobotClient as scripted by its Scenario.
+type Robot struct {
+	scenario  Scenario
+	bin       []*gen.Item
+	held      *gen.Item
+	pickToken string
+	picks     int
+	calls     []Call
+	counts    map[string]int
+	moves     []Move
+	mu        sync.Mutex
+}
+
+var _ gen.SortingRobotClient = &Robot{}
+
+func New(scenario Scenario) *Robot {
+	return &Robot{
+		scenario: scenario,
+		bin:      append([]*gen.Item{}, scenario.Picks...),
+		counts:   make(map[string]int),
+		mu:       sync.Mutex{},
+	}
+}
+
+// Calls returns every call the robot got, in order.
+func (r *Robot) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call{}, r.calls...)
+}
+
+// CallsTo returns the calls to one method, in order.
+func (r *Robot) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := []Call{}
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Moves returns the items that were put into cubbies, in order.
+func (r *Robot) Moves() []Move {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Move{}, r.moves...)
+}
+
+// call waits out the latency, records the call and returns the fault
+// scripted for it, if any. Unless the call is scripted to fail, do runs with
+// r.mu held and returns the call's own error.
+func (r *Robot) call(ctx context.Context, method string, request interface{}, do func() error) error {
+	if r.scenario.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(r.scenario.Latency):
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counts[method]++
+	err := r.fault(method, r.counts[method])
+	if status.Code(err) == codes.DataLoss {
+		// The robot dropped the item it was holding.
+		r.held = nil
+	}
+	if err == nil && do != nil {
+		err = do()
+	}
+	r.calls = append(r.calls, Call{Method: method, Request: request, Err: err})
+	return err
+}
+
+func (r *Robot) fault(method string, call int) error {
+	for _, fault := range r.scenario.Faults {
+		if fault.Method == method && fault.Call == call {
+			return fault.Err
+		}
+	}
+	return nil
+}
+
+func (r *Robot) LoadItems(ctx context.Context, in *gen.LoadItemsRequest, opts ...grpc.CallOption) (*gen.Empty, error) {
+	err := r.call(ctx, "LoadItems", in, func() error {
+		r.bin = append(r.bin, in.Items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+func (r *Robot) SelectItem(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.SelectItemResponse, error) {
+	var resp *gen.SelectItemResponse
+	err := r.call(ctx, "SelectItem", in, func() error {
+		if r.held != nil {
+			return fmt.Errorf("item has already been selected")
+		}
+		if len(r.bin) == 0 {
+			return fmt.Errorf("no items in the cargo")
+		}
+
+		r.picks++
+		r.held = r.bin[0]
+		r.bin = r.bin[1:]
+		r.pickToken = fmt.Sprintf("pick-%d", r.picks)
+		resp = &gen.SelectItemResponse{Item: r.held, PickToken: r.pickToken}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkHeld fails unless pickToken is the token of the held item. Must be
+// called with r.mu held.
+func (r *Robot) checkHeld(pickToken string) error {
+	if r.held == nil {
+		return fmt.Errorf("item is not selected")
+	}
+	if pickToken != r.pickToken {
+		return fmt.Errorf("pick token does not match the selected item")
+	}
+	return nil
+}
+
+// MoveItem puts the held item into the cubby. A scripted DataLoss fault
+// drops the item, as on the real robot.
+func (r *Robot) MoveItem(ctx context.Context, in *gen.MoveItemRequest, opts ...grpc.CallOption) (*gen.Empty, error) {
+	err := r.call(ctx, "MoveItem", in, func() error {
+		if err := r.checkHeld(in.PickToken); err != nil {
+			return err
+		}
+
+		r.moves = append(r.moves, Move{ItemCode: r.held.Code, CubbyId: in.Cubby.GetId()})
+		r.held = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+func (r *Robot) ReturnItem(ctx context.Context, in *gen.ReturnItemRequest, opts ...grpc.CallOption) (*gen.Empty, error) {
+	err := r.call(ctx, "ReturnItem", in, func() error {
+		if err := r.checkHeld(in.PickToken); err != nil {
+			return err
+		}
+
+		r.bin = append(r.bin, r.held)
+		r.held = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+// AuditState reports the moved items by cubby, in the order the cubbies were
+// first used.
+func (r *Robot) AuditState(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.AuditStateResponse, error) {
+	resp := &gen.AuditStateResponse{}
+	err := r.call(ctx, "AuditState", in, func() error {
+		cubbies := map[string]*gen.CubbyToItems{}
+		for _, move := range r.moves {
+			cubby, ok := cubbies[move.CubbyId]
+			if !ok {
+				cubby = &gen.CubbyToItems{Cubby: &gen.Cubby{Id: move.CubbyId}}
+				cubbies[move.CubbyId] = cubby
+				resp.CubbiesToItems = append(resp.CubbiesToItems, cubby)
+			}
+			cubby.Items = append(cubby.Items, &gen.Item{Code: move.ItemCode})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Robot) AcquireLease(ctx context.Context, in *gen.AcquireLeaseRequest, opts ...grpc.CallOption) (*gen.Lease, error) {
+	if err := r.call(ctx, "AcquireLease", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Lease{LeaseId: "fake-lease", Holder: in.Holder}, nil
+}
+
+func (r *Robot) RenewLease(ctx context.Context, in *gen.LeaseRequest, opts ...grpc.CallOption) (*gen.Lease, error) {
+	if err := r.call(ctx, "RenewLease", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Lease{LeaseId: in.LeaseId}, nil
+}
+
+func (r *Robot) ReleaseLease(ctx context.Context, in *gen.LeaseRequest, opts ...grpc.CallOption) (*gen.Empty, error) {
+	if err := r.call(ctx, "ReleaseLease", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+func (r *Robot) InjectFault(ctx context.Context, in *gen.InjectFaultRequest, opts ...grpc.CallOption) (*gen.Empty, error) {
+	if err := r.call(ctx, "InjectFault", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+func (r *Robot) ClearFault(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.Empty, error) {
+	if err := r.call(ctx, "ClearFault", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+func (r *Robot) GetRobotStatus(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.RobotStatus, error) {
+	var resp *gen.RobotStatus
+	err := r.call(ctx, "GetRobotStatus", in, func() error {
+		state := gen.RobotState_IDLE
+		if states := r.scenario.States; len(states) > 0 {
+			call := r.counts["GetRobotStatus"]
+			if call > len(states) {
+				call = len(states)
+			}
+			state = states[call-1]
+		}
+
+		resp = &gen.RobotStatus{State: state, SelectedItem: r.held, ItemsInBin: int32(len(r.bin)), ItemsPicked: int64(r.picks), ItemsPlaced: int64(len(r.moves))}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Robot) EmergencyStop(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.Empty, error) {
+	if err := r.call(ctx, "EmergencyStop", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+func (r *Robot) Resume(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.Empty, error) {
+	if err := r.call(ctx, "Resume", in, nil); err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
+
+// ListInventory counts the items left to pick and the held item by code, in
+// the order of their codes.
+func (r *Robot) ListInventory(ctx context.Context, in *gen.Empty, opts ...grpc.CallOption) (*gen.InventoryResponse, error) {
+	resp := &gen.InventoryResponse{}
+	err := r.call(ctx, "ListInventory", in, func() error {
+		items := append([]*gen.Item{}, r.bin...)
+		if r.held != nil {
+			items = append(items, r.held)
+		}
+
+		quantities := map[string]int32{}
+		codes := []string{}
+		for _, item := range items {
+			if quantities[item.Code] == 0 {
+				codes = append(codes, item.Code)
+			}
+			quantities[item.Code]++
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			resp.Items = append(resp.Items, &gen.StockLevel{Code: code, Quantity: quantities[code]})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// WatchRobotEvents is not scripted; the fake robot has no event stream.
+func (r *Robot) WatchRobotEvents(ctx context.Context, in *gen.WatchRobotEventsRequest, opts ...grpc.CallOption) (gen.SortingRobot_WatchRobotEventsClient, error) {
+	err := r.call(ctx, "WatchRobotEvents", in, func() error {
+		return status.Error(codes.Unimplemented, "the fake robot does not stream events")
+	})
+	return nil, err
+}
@@ -0,0 +1,257 @@
+// Package gateway serves the Fulfillment API as JSON over HTTP for clients
+// that cannot speak gRPC. Requests and responses are the gRPC messages in
+// their protojson form:
+//
+//	POST /v1/orders                  LoadOrdersRequest -> CompleteResponse
+//	GET  /v1/orders                  -> OrdersStatusResponse of every order
+//	GET  /v1/orders/{id}             -> OrdersStatusResponse of one order
+//	POST /v1/orders/{id}/fulfilled   marks the order as picked up
+//	GET  /v1/orders-at-risk          -> OrdersAtRiskResponse, ?withinMillis=
+//	GET  /v1/reservations            -> ReservationsResponse
+//	POST /v1/items/{code}/scan       -> ScanItemResponse, the item's cubby
+//	POST /v1/items/{code}/put        ConfirmPutRequest, confirms the put
+//
+// Calls go through the same interceptor as gRPC calls, with the HTTP
+// Authorization header passed on as metadata.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxRequestSize bounds the size of a request body.
+const maxRequestSize = 1 << 20
+
+const (
+	ordersPath       = "/v1/orders"
+	ordersAtRiskPath = "/v1/orders-at-risk"
+	reservationsPath = "/v1/reservations"
+	itemsPath        = "/v1/items"
+)
+
+type gateway struct {
+	service     gen.FulfillmentServer
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// New returns the HTTP handler of the gateway. interceptor may be nil.
+func New(service gen.FulfillmentServer, interceptor grpc.UnaryServerInterceptor) http.Handler {
+	g := &gateway{service: service, interceptor: interceptor}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(ordersPath, g.orders)
+	mux.HandleFunc(ordersPath+"/", g.order)
+	mux.HandleFunc(ordersAtRiskPath, g.ordersAtRisk)
+	mux.HandleFunc(reservationsPath, g.reservations)
+	mux.HandleFunc(itemsPath+"/", g.item)
+	return mux
+}
+
+func (g *gateway) orders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		in := &gen.LoadOrdersRequest{}
+		if !readRequest(w, r, in) {
+			return
+		}
+		g.call(w, r, "LoadOrders", in, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.service.LoadOrders(ctx, req.(*gen.LoadOrdersRequest))
+		})
+	case http.MethodGet:
+		g.call(w, r, "GetAllOrdersFulfillmentStatus", &gen.Empty{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.service.GetAllOrdersFulfillmentStatus(ctx, req.(*gen.Empty))
+		})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (g *gateway) order(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, ordersPath+"/"), "/")
+	in := &gen.OrderIdRequest{OrderId: parts[0]}
+
+	switch {
+	case in.OrderId == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "fulfilled"):
+		writeError(w, status.Errorf(codes.NotFound, "no such path %s", r.URL.Path))
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		g.call(w, r, "GetOrderFulfillmentStatusById", in, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.service.GetOrderFulfillmentStatusById(ctx, req.(*gen.OrderIdRequest))
+		})
+	case len(parts) == 1:
+		methodNotAllowed(w, http.MethodGet)
+	case r.Method == http.MethodPost:
+		g.call(w, r, "MarkFulfilled", in, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.service.MarkFulfilled(ctx, req.(*gen.OrderIdRequest))
+		})
+	default:
+		methodNotAllowed(w, http.MethodPost)
+	}
+}
+
+func (g *gateway) ordersAtRisk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	in := &gen.OrdersAtRiskRequest{}
+	if within := r.URL.Query().Get("withinMillis"); within != "" {
+		withinMillis, err := strconv.ParseInt(within, 10, 64)
+		if err != nil || withinMillis < 0 {
+			writeError(w, status.Errorf(codes.InvalidArgument, "withinMillis must be a non-negative number of milliseconds, got %s", within))
+			return
+		}
+		in.WithinMillis = withinMillis
+	}
+	g.call(w, r, "GetOrdersAtRisk", in, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return g.service.GetOrdersAtRisk(ctx, req.(*gen.OrdersAtRiskRequest))
+	})
+}
+
+func (g *gateway) reservations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	g.call(w, r, "GetReservations", &gen.Empty{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return g.service.GetReservations(ctx, req.(*gen.Empty))
+	})
+}
+
+func (g *gateway) item(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, itemsPath+"/"), "/")
+
+	switch {
+	case parts[0] == "" || len(parts) != 2 || (parts[1] != "scan" && parts[1] != "put"):
+		writeError(w, status.Errorf(codes.NotFound, "no such path %s", r.URL.Path))
+	case r.Method != http.MethodPost:
+		methodNotAllowed(w, http.MethodPost)
+	case parts[1] == "scan":
+		in := &gen.ScanItemRequest{ItemCode: parts[0]}
+		g.call(w, r, "ScanItem", in, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.service.ScanItem(ctx, req.(*gen.ScanItemRequest))
+		})
+	default:
+		in := &gen.ConfirmPutRequest{}
+		if !readRequest(w, r, in) {
+			return
+		}
+		in.ItemCode = parts[0]
+		g.call(w, r, "ConfirmPut", in, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.service.ConfirmPut(ctx, req.(*gen.ConfirmPutRequest))
+		})
+	}
+}
+
+// call invokes a Fulfillment method through the interceptor and writes its
+// response.
+func (g *gateway) call(w http.ResponseWriter, r *http.Request, method string, in proto.Message, handler grpc.UnaryHandler) {
+	ctx := r.Context()
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+	}
+
+	var out interface{}
+	var err error
+	if g.interceptor != nil {
+		info := &grpc.UnaryServerInfo{Server: g.service, FullMethod: "/" + gen.Fulfillment_ServiceDesc.ServiceName + "/" + method}
+		out, err = g.interceptor(ctx, in, info, handler)
+	} else {
+		out, err = handler(ctx, in)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	body, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(out.(proto.Message))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		log.Println("Error while writing HTTP response occured: ", err.Error())
+	}
+}
+
+// readRequest decodes the request body into in, writing an error response if
+// it cannot.
+func readRequest(w http.ResponseWriter, r *http.Request, in proto.Message) bool {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "failed to read request: %v", err))
+		return false
+	}
+
+	if err := protojson.Unmarshal(body, in); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "invalid request: %v", err))
+		return false
+	}
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"code": "MethodNotAllowed", "message": "allowed methods are " + strings.Join(allowed, ", ")})
+}
+
+// writeError writes the gRPC status of err with the matching HTTP status.
+func writeError(w http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	writeJSON(w, httpStatus(s.Code()), map[string]string{"code": s.Code().String(), "message": s.Message()})
+}
+
+func writeJSON(w http.ResponseWriter, httpStatus int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Println("Error while writing HTTP response occured: ", err.Error())
+	}
+}
+
+// httpStatus maps gRPC codes the way gRPC's own HTTP gateways do.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,250 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// fakeService records the calls it gets and fails them with err.
+type fakeService struct {
+	gen.UnimplementedFulfillmentServer
+	method string
+	in     interface{}
+	err    error
+}
+
+func (s *fakeService) record(method string, in interface{}) error {
+	s.method, s.in = method, in
+	return s.err
+}
+
+func (s *fakeService) LoadOrders(ctx context.Context, in *gen.LoadOrdersRequest) (*gen.CompleteResponse, error) {
+	return &gen.CompleteResponse{Status: "loaded"}, s.record("LoadOrders", in)
+}
+
+func (s *fakeService) GetOrderFulfillmentStatusById(ctx context.Context, in *gen.OrderIdRequest) (*gen.OrdersStatusResponse, error) {
+	return &gen.OrdersStatusResponse{}, s.record("GetOrderFulfillmentStatusById", in)
+}
+
+func (s *fakeService) GetAllOrdersFulfillmentStatus(ctx context.Context, in *gen.Empty) (*gen.OrdersStatusResponse, error) {
+	return &gen.OrdersStatusResponse{}, s.record("GetAllOrdersFulfillmentStatus", in)
+}
+
+func (s *fakeService) MarkFulfilled(ctx context.Context, in *gen.OrderIdRequest) (*gen.Empty, error) {
+	return &gen.Empty{}, s.record("MarkFulfilled", in)
+}
+
+func (s *fakeService) ScanItem(ctx context.Context, in *gen.ScanItemRequest) (*gen.ScanItemResponse, error) {
+	return &gen.ScanItemResponse{Cubby: &gen.Cubby{Id: "7"}}, s.record("ScanItem", in)
+}
+
+func (s *fakeService) ConfirmPut(ctx context.Context, in *gen.ConfirmPutRequest) (*gen.Empty, error) {
+	return &gen.Empty{}, s.record("ConfirmPut", in)
+}
+
+func (s *fakeService) GetOrdersAtRisk(ctx context.Context, in *gen.OrdersAtRiskRequest) (*gen.OrdersAtRiskResponse, error) {
+	return &gen.OrdersAtRiskResponse{}, s.record("GetOrdersAtRisk", in)
+}
+
+func (s *fakeService) GetReservations(ctx context.Context, in *gen.Empty) (*gen.ReservationsResponse, error) {
+	return &gen.ReservationsResponse{}, s.record("GetReservations", in)
+}
+
+func serve(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+// errorBody decodes the body of an error response.
+func errorBody(t *testing.T, w *httptest.ResponseRecorder) map[string]string {
+	body := map[string]string{}
+	assert.Equal(t, json.Unmarshal(w.Body.Bytes(), &body), nil, "The error should be JSON")
+	return body
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		httpMethod string
+		path       string
+		body       string
+		method     string
+	}{
+		{"Load orders", http.MethodPost, "/v1/orders", `{"orders": [{"id": "1"}]}`, "LoadOrders"},
+		{"All orders", http.MethodGet, "/v1/orders", "", "GetAllOrdersFulfillmentStatus"},
+		{"One order", http.MethodGet, "/v1/orders/1", "", "GetOrderFulfillmentStatusById"},
+		{"Mark fulfilled", http.MethodPost, "/v1/orders/1/fulfilled", "", "MarkFulfilled"},
+		{"Orders at risk", http.MethodGet, "/v1/orders-at-risk", "", "GetOrdersAtRisk"},
+		{"Reservations", http.MethodGet, "/v1/reservations", "", "GetReservations"},
+		{"Scan item", http.MethodPost, "/v1/items/tomatoes/scan", "", "ScanItem"},
+		{"Confirm put", http.MethodPost, "/v1/items/tomatoes/put", `{"cubbyId": "7"}`, "ConfirmPut"},
+	}
+
+	for _, test := range tests {
+		service := &fakeService{}
+		w := serve(New(service, nil), test.httpMethod, test.path, test.body)
+
+		assert.Equal(t, w.Code, http.StatusOK, test.name+" should succeed")
+		assert.Equal(t, w.Header().Get("Content-Type"), "application/json", test.name+" should answer with JSON")
+		assert.Equal(t, service.method, test.method, test.name+" should call "+test.method)
+	}
+}
+
+func TestRequestMapping(t *testing.T) {
+	service := &fakeService{}
+	handler := New(service, nil)
+
+	serve(handler, http.MethodPost, "/v1/orders", `{"orders": [{"id": "1", "items": [{"code": "123", "label": "tomato"}]}]}`)
+	orders := service.in.(*gen.LoadOrdersRequest).Orders
+	assert.Equal(t, len(orders), 1, "The orders in the body should be loaded")
+	assert.Equal(t, orders[0].Id, "1", "The order id should be taken from the body")
+	assert.Equal(t, orders[0].Items[0].Code, "123", "The items should be taken from the body")
+
+	serve(handler, http.MethodGet, "/v1/orders/42", "")
+	assert.Equal(t, service.in.(*gen.OrderIdRequest).OrderId, "42", "The order id should be taken from the path")
+
+	serve(handler, http.MethodPost, "/v1/orders/42/fulfilled", "")
+	assert.Equal(t, service.in.(*gen.OrderIdRequest).OrderId, "42", "The fulfilled order id should be taken from the path")
+
+	serve(handler, http.MethodGet, "/v1/orders-at-risk?withinMillis=60000", "")
+	assert.Equal(t, service.in.(*gen.OrdersAtRiskRequest).WithinMillis, int64(60000), "The window should be taken from the query")
+
+	serve(handler, http.MethodPost, "/v1/items/tomatoes/scan", "")
+	assert.Equal(t, service.in.(*gen.ScanItemRequest).ItemCode, "tomatoes", "The scanned item should be taken from the path")
+
+	serve(handler, http.MethodPost, "/v1/items/tomatoes/put", `{"itemCode": "potatoes", "cubbyId": "7"}`)
+	put := service.in.(*gen.ConfirmPutRequest)
+	assert.Equal(t, put.ItemCode, "tomatoes", "The put item should be taken from the path, not the body")
+	assert.Equal(t, put.CubbyId, "7", "The cubby should be taken from the body")
+}
+
+func TestResponseBody(t *testing.T) {
+	w := serve(New(&fakeService{}, nil), http.MethodPost, "/v1/items/tomatoes/scan", "")
+
+	response := &gen.ScanItemResponse{}
+	assert.Equal(t, protojson.Unmarshal(w.Body.Bytes(), response), nil, "The response should be the message in its JSON form")
+	assert.Equal(t, response.Cubby.Id, "7", "The response should carry the service's answer")
+}
+
+func TestInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		httpMethod string
+		path       string
+		body       string
+		httpStatus int
+		code       string
+	}{
+		{"Malformed orders", http.MethodPost, "/v1/orders", `{"orders": [`, http.StatusBadRequest, "InvalidArgument"},
+		{"Unknown field", http.MethodPost, "/v1/orders", `{"order": []}`, http.StatusBadRequest, "InvalidArgument"},
+		{"Malformed put", http.MethodPost, "/v1/items/tomatoes/put", `cubby 7`, http.StatusBadRequest, "InvalidArgument"},
+		{"Window that is not a number", http.MethodGet, "/v1/orders-at-risk?withinMillis=soon", "", http.StatusBadRequest, "InvalidArgument"},
+		{"Negative window", http.MethodGet, "/v1/orders-at-risk?withinMillis=-1", "", http.StatusBadRequest, "InvalidArgument"},
+		{"Missing order id", http.MethodGet, "/v1/orders/", "", http.StatusNotFound, "NotFound"},
+		{"Unknown order action", http.MethodPost, "/v1/orders/1/cancelled", "", http.StatusNotFound, "NotFound"},
+		{"Unknown item action", http.MethodPost, "/v1/items/tomatoes/drop", "", http.StatusNotFound, "NotFound"},
+		{"Deleting orders", http.MethodDelete, "/v1/orders", "", http.StatusMethodNotAllowed, "MethodNotAllowed"},
+		{"Posting an order", http.MethodPost, "/v1/orders/1", "", http.StatusMethodNotAllowed, "MethodNotAllowed"},
+		{"Getting a fulfillment", http.MethodGet, "/v1/orders/1/fulfilled", "", http.StatusMethodNotAllowed, "MethodNotAllowed"},
+		{"Posting reservations", http.MethodPost, "/v1/reservations", "", http.StatusMethodNotAllowed, "MethodNotAllowed"},
+		{"Getting a scan", http.MethodGet, "/v1/items/tomatoes/scan", "", http.StatusMethodNotAllowed, "MethodNotAllowed"},
+	}
+
+	for _, test := range tests {
+		service := &fakeService{}
+		w := serve(New(service, nil), test.httpMethod, test.path, test.body)
+
+		assert.Equal(t, w.Code, test.httpStatus, test.name+" should be answered with "+http.StatusText(test.httpStatus))
+		assert.Equal(t, errorBody(t, w)["code"], test.code, test.name+" should report "+test.code)
+		assert.Equal(t, service.method, "", test.name+" should not reach the service")
+		if test.httpStatus == http.StatusMethodNotAllowed {
+			assert.NotEqual(t, w.Header().Get("Allow"), "", test.name+" should list the allowed methods")
+		}
+	}
+}
+
+func TestErrorTranslation(t *testing.T) {
+	tests := []struct {
+		code       codes.Code
+		httpStatus int
+	}{
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.OutOfRange, http.StatusBadRequest},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.Aborted, http.StatusConflict},
+		{codes.FailedPrecondition, http.StatusBadRequest},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.Canceled, 499},
+		{codes.Unimplemented, http.StatusNotImplemented},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{codes.Internal, http.StatusInternalServerError},
+		{codes.Unknown, http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		service := &fakeService{err: status.Error(test.code, "order 1 failed")}
+		w := serve(New(service, nil), http.MethodGet, "/v1/orders/1", "")
+
+		body := errorBody(t, w)
+		assert.Equal(t, w.Code, test.httpStatus, test.code.String()+" should be answered with "+http.StatusText(test.httpStatus))
+		assert.Equal(t, body["code"], test.code.String(), test.code.String()+" should be reported by name")
+		assert.Equal(t, body["message"], "order 1 failed", test.code.String()+" should carry the service's message")
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		httpStatus    int
+	}{
+		{"Allowed", "Bearer operator", http.StatusOK},
+		{"Denied", "Bearer intake", http.StatusForbidden},
+		{"Missing token", "", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		var fullMethod string
+		interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			fullMethod = info.FullMethod
+			md, _ := metadata.FromIncomingContext(ctx)
+			switch values := md.Get("authorization"); {
+			case len(values) == 0:
+				return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+			case values[0] != "Bearer operator":
+				return nil, status.Error(codes.PermissionDenied, "only operators may mark orders fulfilled")
+			}
+			return handler(ctx, req)
+		}
+
+		service := &fakeService{}
+		r := httptest.NewRequest(http.MethodPost, "/v1/orders/1/fulfilled", nil)
+		if test.authorization != "" {
+			r.Header.Set("Authorization", test.authorization)
+		}
+		w := httptest.NewRecorder()
+		New(service, interceptor).ServeHTTP(w, r)
+
+		assert.Equal(t, fullMethod, "/fulfillment.Fulfillment/MarkFulfilled", test.name+": the interceptor should see the gRPC method")
+		assert.Equal(t, w.Code, test.httpStatus, test.name+" should be answered with "+http.StatusText(test.httpStatus))
+		assert.Equal(t, service.method == "MarkFulfilled", test.httpStatus == http.StatusOK, test.name+": only allowed calls should reach the service")
+	}
+}
@@ -2,10 +2,16 @@ module github.com/Emoto13/sort-system/fulfillment-service
 
 go 1.16
 
-replace github.com/Emoto13/sort-system/gen => ../gen
+replace (
+	github.com/Emoto13/sort-system/common => ../common
+	github.com/Emoto13/sort-system/gen => ../gen
+)
 
 require (
+	github.com/Emoto13/sort-system/common v0.0.0-00010101000000-000000000000
 	github.com/Emoto13/sort-system/gen v0.0.0-20210623104657-36fa702e85f3
 	github.com/preslavmihaylov/ordertocubby v0.0.0-20210617074346-1704d311e402
+	github.com/stretchr/testify v1.7.0
 	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,165 @@
+package lease
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataKey is the request header the sorting robot reads the lease id from.
+const metadataKey = "lease-id"
+
+// Keeper holds the sorting robot's exclusive controller lease on behalf of
+// this service and attaches it to every robot call.
+type Keeper struct {
+	holder  string
+	ttl     time.Duration
+	leaseId string
+	// changed is closed and replaced every time the lease is taken or lost.
+	changed  chan struct{}
+	onChange func(held bool)
+	mu       sync.RWMutex
+}
+
+// NewKeeper creates a keeper that does not hold the lease yet. onChange, if
+// set, is called every time the lease is taken or lost.
+func NewKeeper(holder string, ttl time.Duration, onChange func(held bool)) *Keeper {
+	return &Keeper{
+		holder:   holder,
+		ttl:      ttl,
+		changed:  make(chan struct{}),
+		onChange: onChange,
+		mu:       sync.RWMutex{},
+	}
+}
+
+func (k *Keeper) LeaseId() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.leaseId
+}
+
+func (k *Keeper) Held() bool {
+	return k.LeaseId() != ""
+}
+
+func (k *Keeper) setLeaseId(leaseId string) {
+	k.mu.Lock()
+	held := k.leaseId != ""
+	k.leaseId = leaseId
+	if held == (leaseId != "") {
+		k.mu.Unlock()
+		return
+	}
+
+	close(k.changed)
+	k.changed = make(chan struct{})
+	k.mu.Unlock()
+
+	if k.onChange != nil {
+		k.onChange(!held)
+	}
+}
+
+// WaitHeld blocks until the lease is held or ctx is done.
+func (k *Keeper) WaitHeld(ctx context.Context) error {
+	for {
+		k.mu.RLock()
+		held, changed := k.leaseId != "", k.changed
+		k.mu.RUnlock()
+
+		if held {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Hold acquires the lease and keeps it until ctx is done. It is meant to run
+// in the background, so that the service starts while another controller
+// holds the lease or the robot is down; work waits for the lease with
+// WaitHeld.
+func (k *Keeper) Hold(ctx context.Context, robot gen.SortingRobotClient) {
+	if err := k.Acquire(ctx, robot); err != nil {
+		return
+	}
+	k.KeepAlive(ctx, robot)
+}
+
+// Acquire blocks until the lease is granted or ctx is done. The robot refuses
+// the lease while another controller holds it, so Acquire keeps retrying.
+func (k *Keeper) Acquire(ctx context.Context, robot gen.SortingRobotClient) error {
+	for {
+		lease, err := robot.AcquireLease(ctx, &gen.AcquireLeaseRequest{Holder: k.holder, TtlMillis: k.ttl.Milliseconds()})
+		if err == nil {
+			k.setLeaseId(lease.LeaseId)
+			log.Println("Acquired sorting robot lease as", k.holder)
+			return nil
+		}
+
+		log.Println("Error while acquiring sorting robot lease occured: ", err.Error(), "\nTrying again.")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(k.ttl / 3):
+		}
+	}
+}
+
+// KeepAlive renews the lease until ctx is done. If a heartbeat is missed and
+// the lease is lost, it is acquired again.
+func (k *Keeper) KeepAlive(ctx context.Context, robot gen.SortingRobotClient) {
+	ticker := time.NewTicker(k.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := robot.RenewLease(ctx, &gen.LeaseRequest{LeaseId: k.LeaseId()})
+		if err == nil {
+			continue
+		}
+
+		log.Println("Sorting robot lease was lost: ", err.Error())
+		k.setLeaseId("")
+		if err := k.Acquire(ctx, robot); err != nil {
+			return
+		}
+	}
+}
+
+func (k *Keeper) Release(ctx context.Context, robot gen.SortingRobotClient) error {
+	leaseId := k.LeaseId()
+	if leaseId == "" {
+		return nil
+	}
+
+	_, err := robot.ReleaseLease(ctx, &gen.LeaseRequest{LeaseId: leaseId})
+	k.setLeaseId("")
+	return err
+}
+
+// UnaryClientInterceptor attaches the current lease id to outgoing robot calls.
+func (k *Keeper) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if leaseId := k.LeaseId(); leaseId != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, metadataKey, leaseId)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,55 @@
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/fakerobot"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHoldRetriesInTheBackground(t *testing.T) {
+	robot := fakerobot.New(fakerobot.Scenario{Faults: []fakerobot.Fault{
+		{Method: "AcquireLease", Call: 1, Err: status.Error(codes.FailedPrecondition, "robot is already leased")},
+	}})
+	changes := make(chan bool, 1)
+	keeper := NewKeeper("fulfillment-1", 30*time.Millisecond, func(held bool) {
+		changes <- held
+	})
+	assert.Equal(t, keeper.Held(), false, "The lease should not be held before it is acquired")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go keeper.Hold(ctx, robot)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, time.Second)
+	defer waitCancel()
+	assert.Equal(t, keeper.WaitHeld(waitCtx), nil, "The lease should be held once the robot grants it")
+	assert.Equal(t, <-changes, true, "Taking the lease should be reported")
+	assert.Equal(t, keeper.LeaseId(), "fake-lease", "The lease id should be the one the robot granted")
+	assert.Equal(t, len(robot.CallsTo("AcquireLease")) >= 2, true, "A refused lease should be asked for again")
+}
+
+func TestWaitHeldEndsWithContext(t *testing.T) {
+	keeper := NewKeeper("fulfillment-1", time.Second, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, keeper.WaitHeld(ctx), context.DeadlineExceeded, "Waiting should end when the context is done")
+}
+
+func TestReleaseReportsLoss(t *testing.T) {
+	robot := fakerobot.New(fakerobot.Scenario{})
+	changes := []bool{}
+	keeper := NewKeeper("fulfillment-1", time.Second, func(held bool) {
+		changes = append(changes, held)
+	})
+
+	assert.Equal(t, keeper.Acquire(context.Background(), robot), nil, "Acquiring a free lease should succeed")
+	assert.Equal(t, keeper.Release(context.Background(), robot), nil, "Releasing the lease should succeed")
+	assert.Equal(t, keeper.Held(), false, "The lease should not be held once released")
+	assert.Equal(t, changes, []bool{true, false}, "Taking and giving back the lease should both be reported")
+}
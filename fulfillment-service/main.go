@@ -2,57 +2,569 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/Emoto13/sort-system/common/auth"
+	"github.com/Emoto13/sort-system/common/config"
+	"github.com/Emoto13/sort-system/common/tlsconfig"
+	"github.com/Emoto13/sort-system/fulfillment-service/gateway"
+	"github.com/Emoto13/sort-system/fulfillment-service/lease"
+	"github.com/Emoto13/sort-system/fulfillment-service/robot"
+	"github.com/Emoto13/sort-system/fulfillment-service/robothealth"
 	"github.com/Emoto13/sort-system/fulfillment-service/service"
 	"github.com/Emoto13/sort-system/fulfillment-service/state"
 
 	"github.com/Emoto13/sort-system/gen"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
-const (
-	sortingRobotAddress = "localhost:10000"
-	serverPort          = "localhost:10001"
+// envPrefix starts the names of the environment variables that configure the
+// service.
+const envPrefix = "FULFILLMENT_SERVICE_"
+
+var (
+	serverAddress       = flag.String("server-address", "localhost:10001", "address the fulfillment service listens on")
+	httpAddress         = flag.String("http-address", "localhost:10002", "address the HTTP/JSON gateway listens on, empty disables it")
+	robotDriver         = flag.String("robot-driver", "grpc", "what sorts the items: grpc drives the sorting robot at robot-address, simulator simulates a robot in process, operator has operators pick for the service by hand one item at a time and manual has operators sort by hand at a put wall")
+	sortingRobotAddress = flag.String("robot-address", "localhost:10000", "addresses of the sorting robots, separated by commas; each robot sorts from its own input bin, into the cubbies of the wall given as WALL=ADDRESS or of any wall")
+	numberOfCubbies     = flag.Int("number-of-cubbies", 10, "number of cubbies orders are distributed to, unless walls are set")
+	cubbyWalls          = flag.String("walls", "", "walls of cubbies as WALL:CUBBIES, separated by commas, e.g. A:10,B:10")
+	continuous          = flag.Bool("continuous", false, "sort orders as they are loaded, each on its own, instead of one batch at a time, freeing cubbies as orders are picked up")
+	wallRouting         = flag.String("wall-routing", "balance", "how orders are routed to walls: balance puts each order on the wall with the most free cubbies, customer keeps a customer's orders on one wall")
+	robotLeaseTTL       = flag.Duration("robot-lease-ttl", 10*time.Second, "how long the sorting robot lease lasts without a heartbeat")
+	shutdownTimeout     = flag.Duration("shutdown-timeout", 30*time.Second, "how long the item in the robot's gripper may take to be sorted on shutdown")
+	simulatorPickTime   = flag.Duration("simulator-pick-time", 500*time.Millisecond, "how long the simulated robot takes to pick an item")
+	simulatorPlaceTime  = flag.Duration("simulator-place-time", time.Second, "how long the simulated robot takes to put an item into its cubby")
+	admission           = flag.String("admission", "all", "what happens to orders the robots do not hold the items of: all admits them anyway, reject refuses them and backorder holds them back until the items are loaded")
+	partialTimeout      = flag.Duration("partial-timeout", 0, "how long an order may miss items before partial-policy is applied to it, unless the order sets its own; 0 waits for the items for as long as it takes")
+	partialPolicy       = flag.String("partial-policy", "hold", "what happens to orders still missing items at their partial timeout, unless they set their own: release has them picked up as they are, hold keeps them waiting for the items, which needs continuous, and cancel frees their cubbies")
+	atRiskMargin        = flag.Duration("at-risk-margin", 5*time.Minute, "how close to its deadline an order that is not ready yet counts as at risk")
+	orderRetention      = flag.Duration("order-retention", time.Hour, "how long the status of an order is kept after its cubby is freed; its id cannot be loaded again until then")
+	manualPutTimeout    = flag.Duration("manual-put-timeout", 2*time.Minute, "how long an operator has to confirm putting a scanned item into its cubby before it counts as lost")
+
+	tlsCertFile      = flag.String("tls-cert-file", "", "certificate the server presents, enables TLS")
+	tlsKeyFile       = flag.String("tls-key-file", "", "private key of tls-cert-file")
+	tlsClientCAFile  = flag.String("tls-client-ca-file", "", "CA that client certificates must be signed by, enables mutual TLS")
+	robotTLSCAFile   = flag.String("robot-tls-ca-file", "", "CA the sorting robot's certificate is signed by, enables TLS to the robot")
+	robotTLSCertFile = flag.String("robot-tls-cert-file", "", "certificate presented to the sorting robot for mutual TLS")
+	robotTLSKeyFile  = flag.String("robot-tls-key-file", "", "private key of robot-tls-cert-file")
+
+	authSigningKeyFile = flag.String("auth-signing-key-file", "", "key that bearer tokens are signed with, enables authentication")
+	authKeysFile       = flag.String("auth-keys-file", "", "JSON file of static bearer tokens and their claims, enables authentication")
+	robotTokenFile     = flag.String("robot-token-file", "", "file with the bearer token presented to the sorting robot")
 )
 
+// fulfillmentPolicy lets only order intake load orders and only operators
+// confirm pickups and sort by hand. Anyone authenticated may look up order
+// status. Items are loaded into the simulated robot as into the real one.
+var fulfillmentPolicy = auth.Policy{
+	"/fulfillment.Fulfillment/LoadOrders":    {auth.RoleIntake},
+	"/fulfillment.Fulfillment/MarkFulfilled": {auth.RoleOperator},
+	"/fulfillment.Fulfillment/ScanItem":      {auth.RoleOperator},
+	"/fulfillment.Fulfillment/ConfirmPut":    {auth.RoleOperator},
+	"/SortingRobot/LoadItems":                {auth.RoleIntake, auth.RoleOperator},
+}
+
 func main() {
-	sortingRobot, conn := newSortingRobotClient()
-	defer conn.Close()
+	if err := config.Load(envPrefix); err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+	if err := validateConfig(); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	config.Print(os.Stdout)
+
+	serverTLS, err := tlsconfig.Server(*tlsCertFile, *tlsKeyFile, *tlsClientCAFile)
+	if err != nil {
+		log.Fatalf("invalid TLS configuration: %v", err)
+	}
+	robotTLS, err := tlsconfig.Client(*robotTLSCAFile, *robotTLSCertFile, *robotTLSKeyFile)
+	if err != nil {
+		log.Fatalf("invalid sorting robot TLS configuration: %v", err)
+	}
+	authenticator, err := auth.Load(*authSigningKeyFile, *authKeysFile)
+	if err != nil {
+		log.Fatalf("invalid authentication configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The service reports NOT_SERVING from the start, until it can sort.
+	healthServer := health.NewServer()
+	setServingStatus(healthServer, healthpb.HealthCheckResponse_NOT_SERVING)
+	grpcServer, lis := newGRPCServer(serverTLS, authenticator, healthServer)
+
+	var robots []service.PooledRobot
+	var robotServer gen.SortingRobotServer
+	releaseRobot := func() {}
+	switch *robotDriver {
+	case "grpc":
+		robots, releaseRobot = connectSortingRobots(ctx, robotTLS, healthServer)
+	case "simulator":
+		simulator := robot.NewSimulator(*simulatorPickTime, *simulatorPlaceTime)
+		robots, robotServer = []service.PooledRobot{{Name: "simulator", Robot: simulator}}, simulator.Server()
+		setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+	case "operator":
+		robots = []service.PooledRobot{{Name: "operator", Robot: robot.NewManual(*manualPutTimeout)}}
+		setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+	case "manual":
+		// Without a robot, the fulfillment service runs a put wall.
+		setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+	}
+	log.Printf("Sorting with the %s robot driver.", *robotDriver)
 
-	grpcServer, lis := newFulfillmentServer(sortingRobot)
+	fulfillmentService := newFulfillmentService(grpcServer, robots, robotServer)
 
-	fmt.Printf("gRPC server started. Listening on %s\n", serverPort)
-	grpcServer.Serve(lis)
+	go func() {
+		fmt.Printf("gRPC server started. Listening on %s\n", *serverAddress)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	var httpServer *http.Server
+	if *httpAddress != "" {
+		httpServer = newGatewayServer(serverTLS, authenticator, fulfillmentService)
+	}
+
+	log.Println("Received", waitForSignal(), "shutting down.")
+	healthServer.Shutdown()
+	shutdown(grpcServer, httpServer, fulfillmentService)
+
+	cancel()
+	releaseRobot()
 }
 
-func newFulfillmentServer(sortingRobot gen.SortingRobotClient) (*grpc.Server, net.Listener) {
-	lis, err := net.Listen("tcp", serverPort)
+func waitForSignal() os.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	return <-signals
+}
+
+// shutdown stops taking orders, lets the robot finish the item in hand,
+// reports the orders that were not sorted and then stops the server.
+func shutdown(grpcServer *grpc.Server, httpServer *http.Server, fulfillmentService service.FulfillmentService) {
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	unprocessed := fulfillmentService.Shutdown(ctx)
+	if len(unprocessed) == 0 {
+		log.Println("All loaded orders were processed.")
+	}
+	for _, order := range unprocessed {
+		log.Printf("Order %s with %d items was not processed.", order.Id, len(order.Items))
+	}
+
+	if httpServer != nil {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			log.Println("Error while stopping HTTP gateway occured: ", err.Error())
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Println("Timed out waiting for in-flight calls, stopping the server.")
+		grpcServer.Stop()
+	}
+}
+
+// connectSortingRobots connects to every sorting robot of the pool, reporting
+// the fulfillment service as serving while any of them is healthy and leased.
+// Each robot is connected on its own: a robot that cannot be connected to
+// starts out failed and sits out, while the others sort. The returned
+// function gives their leases back.
+func connectSortingRobots(ctx context.Context, tlsConfig *tls.Config, healthServer *health.Server) ([]service.PooledRobot, func()) {
+	poolHealth := &robotPoolHealth{healthy: map[string]bool{}, leased: map[string]bool{}, healthServer: healthServer, mu: sync.Mutex{}}
+
+	robots := []service.PooledRobot{}
+	releases := []func(){}
+	for _, address := range robotAddresses() {
+		wallId, address := splitRobotAddress(address)
+		sortingRobot, robotHealth, release, err := connectSortingRobot(ctx, tlsConfig, address, poolHealth)
+		if err != nil {
+			log.Println("Error while connecting to sorting robot ", address, " occured: ", err.Error())
+			robots = append(robots, service.PooledRobot{Name: address, Robot: unreachableRobot{err: err}, WallId: wallId, Failed: true})
+			continue
+		}
+		robots = append(robots, service.PooledRobot{Name: address, Robot: sortingRobot, Health: robotHealth, WallId: wallId})
+		releases = append(releases, release)
+	}
+
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	return robots, release
+}
+
+// connectSortingRobot connects to the sorting robot at address, then takes
+// its lease and watches its health in the background, so that the service
+// starts even while the robot is down or leased to another controller. The
+// robot takes no work until it is both healthy and leased. The returned
+// function gives the lease back.
+func connectSortingRobot(ctx context.Context, tlsConfig *tls.Config, address string, poolHealth *robotPoolHealth) (robot.Robot, service.RobotHealth, func(), error) {
+	robotLease := lease.NewKeeper(leaseHolder(), *robotLeaseTTL, func(held bool) {
+		poolHealth.setLeased(address, held)
+	})
+	robotOpts := []grpc.DialOption{grpc.WithUnaryInterceptor(robotLease.UnaryClientInterceptor())}
+	if *robotTokenFile != "" {
+		robotToken, err := auth.LoadToken(*robotTokenFile)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load sorting robot token: %v", err)
+		}
+		robotOpts = append(robotOpts, grpc.WithPerRPCCredentials(robotToken))
+	}
+
+	sortingRobot, conn, err := newSortingRobotClient(tlsConfig, address, robotOpts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	go robotLease.Hold(ctx, sortingRobot)
+
+	robotHealth := robothealth.NewMonitor(conn, func(healthy bool) {
+		poolHealth.setHealthy(address, healthy)
+	})
+	go robotHealth.Watch(ctx)
+
+	release := func() {
+		if err := robotLease.Release(context.Background(), sortingRobot); err != nil {
+			log.Println("Error while releasing sorting robot lease occured: ", err.Error())
+		}
+		conn.Close()
+	}
+	return robot.NewGRPC(sortingRobot), &robotReadiness{lease: robotLease, health: robotHealth}, release, nil
+}
+
+// unreachableRobot stands in for a sorting robot that could not be connected
+// to. Every call fails with the reason, so that the robot never rejoins the
+// pool.
+type unreachableRobot struct {
+	err error
+}
+
+func (r unreachableRobot) SelectItem(ctx context.Context) (*gen.SelectItemResponse, error) {
+	return nil, r.unavailable()
+}
+
+func (r unreachableRobot) MoveItem(ctx context.Context, cubby *gen.Cubby, pickToken string) error {
+	return r.unavailable()
+}
+
+func (r unreachableRobot) ReturnItem(ctx context.Context, pickToken string) error {
+	return r.unavailable()
+}
+
+func (r unreachableRobot) Status(ctx context.Context) (*gen.RobotStatus, error) {
+	return nil, r.unavailable()
+}
+
+func (r unreachableRobot) Inventory(ctx context.Context) ([]*gen.StockLevel, error) {
+	return nil, r.unavailable()
+}
+
+func (r unreachableRobot) unavailable() error {
+	return status.Errorf(codes.Unavailable, "sorting robot is not connected: %v", r.err)
+}
+
+// robotReadiness holds back a robot's picks until the robot is healthy and
+// this service holds its lease.
+type robotReadiness struct {
+	lease  *lease.Keeper
+	health *robothealth.Monitor
+}
+
+func (r *robotReadiness) WaitHealthy(ctx context.Context) error {
+	for {
+		if err := r.lease.WaitHeld(ctx); err != nil {
+			return err
+		}
+		if err := r.health.WaitHealthy(ctx); err != nil {
+			return err
+		}
+		if r.lease.Held() {
+			return nil
+		}
+	}
+}
+
+// robotPoolHealth reports the fulfillment service as serving while any robot
+// of the pool is healthy and leased.
+type robotPoolHealth struct {
+	healthy      map[string]bool
+	leased       map[string]bool
+	healthServer *health.Server
+	mu           sync.Mutex
+}
+
+func (h *robotPoolHealth) setHealthy(address string, healthy bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.healthy[address] = healthy
+	h.update()
+}
+
+func (h *robotPoolHealth) setLeased(address string, leased bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leased[address] = leased
+	h.update()
+}
+
+// update reports the service's health. Must be called with h.mu held.
+func (h *robotPoolHealth) update() {
+	for address, healthy := range h.healthy {
+		if healthy && h.leased[address] {
+			setServingStatus(h.healthServer, healthpb.HealthCheckResponse_SERVING)
+			return
+		}
+	}
+	setServingStatus(h.healthServer, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// newState returns the state of the configured walls, or of a single wall of
+// number-of-cubbies cubbies.
+func newState() state.State {
+	if *cubbyWalls == "" {
+		return state.New(*numberOfCubbies)
+	}
+
+	walls, _ := parseWalls(*cubbyWalls)
+	return state.NewWalls(walls, state.Routing(*wallRouting))
+}
+
+// parseWalls parses walls given as WALL:CUBBIES, separated by commas.
+func parseWalls(value string) ([]state.Wall, error) {
+	walls := []state.Wall{}
+	seen := map[string]bool{}
+	for _, wall := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(wall), ":")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("wall must be given as WALL:CUBBIES, got %q", wall)
+		}
+		cubbies, err := strconv.Atoi(parts[1])
+		if err != nil || cubbies <= 0 {
+			return nil, fmt.Errorf("wall %s must have a positive number of cubbies, got %q", parts[0], parts[1])
+		}
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("wall %s is given more than once", parts[0])
+		}
+		seen[parts[0]] = true
+		walls = append(walls, state.Wall{Id: parts[0], Cubbies: cubbies})
+	}
+	return walls, nil
+}
+
+// splitRobotAddress splits a robot address given as WALL=ADDRESS into the
+// wall and the address. The wall is empty if none is given.
+func splitRobotAddress(address string) (string, string) {
+	if i := strings.Index(address, "="); i >= 0 {
+		return address[:i], address[i+1:]
+	}
+	return "", address
+}
+
+// robotAddresses returns the addresses of the pool's sorting robots.
+func robotAddresses() []string {
+	addresses := []string{}
+	for _, address := range strings.Split(*sortingRobotAddress, ",") {
+		addresses = append(addresses, strings.TrimSpace(address))
+	}
+	return addresses
+}
+
+// newGRPCServer listens on server-address and serves health checks and
+// reflection, before the robots are connected.
+func newGRPCServer(tlsConfig *tls.Config, authenticator *auth.Authenticator, healthServer *health.Server) (*grpc.Server, net.Listener) {
+	lis, err := net.Listen("tcp", *serverAddress)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
-	fulfillmentParameters := &service.FulfillmentServiceParameters{SortingRobot: sortingRobot, State: state.New(), Orders: make(chan []*gen.Order)}
+	serverOptions := []grpc.ServerOption{
+		grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor(fulfillmentPolicy)),
+		grpc.StreamInterceptor(authenticator.StreamServerInterceptor(fulfillmentPolicy)),
+	}
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcServer := grpc.NewServer(serverOptions...)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+	return grpcServer, lis
+}
+
+// newFulfillmentService starts processing orders with robots and registers
+// the service with grpcServer. The simulated robot's robotServer, if any, is
+// served alongside the fulfillment service.
+func newFulfillmentService(grpcServer *grpc.Server, robots []service.PooledRobot, robotServer gen.SortingRobotServer) service.FulfillmentService {
+	fulfillmentParameters := &service.FulfillmentServiceParameters{Robots: robots, Continuous: *continuous, PutTimeout: *manualPutTimeout, Admission: service.Admission(*admission), AtRiskMargin: *atRiskMargin, OrderRetention: *orderRetention, PartialTimeout: *partialTimeout, PartialPolicy: service.PartialPolicies[*partialPolicy], State: newState(), Orders: make(chan []*gen.Order)}
 	service := service.New(fulfillmentParameters)
 	go service.ProcessOrders(context.Background())
 
 	gen.RegisterFulfillmentServer(grpcServer, service)
-	reflection.Register(grpcServer)
+	if robotServer != nil {
+		gen.RegisterSortingRobotServer(grpcServer, robotServer)
+	}
+	return service
+}
 
-	return grpcServer, lis
+// newGatewayServer starts serving the HTTP/JSON gateway, with the same TLS
+// and authorization as the gRPC server.
+func newGatewayServer(tlsConfig *tls.Config, authenticator *auth.Authenticator, fulfillmentService service.FulfillmentService) *http.Server {
+	lis, err := net.Listen("tcp", *httpAddress)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	httpServer := &http.Server{
+		Handler:   gateway.New(fulfillmentService, authenticator.UnaryServerInterceptor(fulfillmentPolicy)),
+		TLSConfig: tlsConfig,
+	}
+
+	go func() {
+		fmt.Printf("HTTP gateway started. Listening on %s\n", *httpAddress)
+		if tlsConfig != nil {
+			err = httpServer.ServeTLS(lis, "", "")
+		} else {
+			err = httpServer.Serve(lis)
+		}
+		if err != http.ErrServerClosed {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	return httpServer
 }
 
-func newSortingRobotClient() (gen.SortingRobotClient, *grpc.ClientConn) {
-	conn, err := grpc.Dial(sortingRobotAddress, grpc.WithInsecure())
-	for err != nil {
-		log.Println("Error while connecting to sorting robot occured: ", err.Error(), "\nTrying again to connect.")
-		conn, err = grpc.Dial(sortingRobotAddress, grpc.WithInsecure())
+// newSortingRobotClient connects to the sorting robot at address, over TLS
+// unless tlsConfig is nil.
+func newSortingRobotClient(tlsConfig *tls.Config, address string, opts ...grpc.DialOption) (gen.SortingRobotClient, *grpc.ClientConn, error) {
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(address, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to sorting robot %s: %v", address, err)
 	}
 
 	client := gen.NewSortingRobotClient(conn)
-	return client, conn
+	return client, conn, nil
+}
+
+// setServingStatus reports the fulfillment service as ready only while the
+// sorting robot is healthy, as it cannot sort anything otherwise.
+func setServingStatus(healthServer *health.Server, servingStatus healthpb.HealthCheckResponse_ServingStatus) {
+	healthServer.SetServingStatus("", servingStatus)
+	healthServer.SetServingStatus(gen.Fulfillment_ServiceDesc.ServiceName, servingStatus)
+}
+
+func validateConfig() error {
+	if *serverAddress == "" {
+		return fmt.Errorf("server-address is required")
+	}
+	if err := robot.ValidateDriver(*robotDriver); err != nil {
+		return err
+	}
+	walls := map[string]bool{}
+	if *cubbyWalls != "" {
+		parsed, err := parseWalls(*cubbyWalls)
+		if err != nil {
+			return err
+		}
+		for _, wall := range parsed {
+			walls[wall.Id] = true
+		}
+	}
+	if *robotDriver == "grpc" {
+		for _, address := range robotAddresses() {
+			wallId, address := splitRobotAddress(address)
+			if address == "" {
+				return fmt.Errorf("robot-address is required, got %q", *sortingRobotAddress)
+			}
+			if wallId != "" && !walls[wallId] {
+				return fmt.Errorf("robot %s serves wall %s, which is not one of walls", address, wallId)
+			}
+		}
+	}
+	if *simulatorPickTime < 0 || *simulatorPlaceTime < 0 {
+		return fmt.Errorf("simulator-pick-time and simulator-place-time must not be negative, got %v and %v", *simulatorPickTime, *simulatorPlaceTime)
+	}
+	if *manualPutTimeout <= 0 {
+		return fmt.Errorf("manual-put-timeout must be positive, got %v", *manualPutTimeout)
+	}
+	if err := service.ValidateAdmission(*admission); err != nil {
+		return err
+	}
+	if *admission != string(service.AdmitAll) && (*robotDriver == "manual" || *robotDriver == "operator") {
+		return fmt.Errorf("admission %s needs a robot inventory, which the %s driver has not", *admission, *robotDriver)
+	}
+	if _, err := service.ParsePartialPolicy(*partialPolicy); err != nil {
+		return err
+	}
+	if *partialTimeout < 0 {
+		return fmt.Errorf("partial-timeout must not be negative, got %v", *partialTimeout)
+	}
+	if *partialTimeout > 0 && *partialPolicy == "hold" && !*continuous {
+		return fmt.Errorf("partial-policy hold needs continuous, as each batch frees its cubbies for the next one")
+	}
+	if *atRiskMargin <= 0 {
+		return fmt.Errorf("at-risk-margin must be positive, got %v", *atRiskMargin)
+	}
+	if *orderRetention <= 0 {
+		return fmt.Errorf("order-retention must be positive, got %v", *orderRetention)
+	}
+	if err := state.ValidateRouting(*wallRouting); err != nil {
+		return err
+	}
+	if *numberOfCubbies <= 0 {
+		return fmt.Errorf("number-of-cubbies must be positive, got %d", *numberOfCubbies)
+	}
+	if *robotLeaseTTL <= 0 {
+		return fmt.Errorf("robot-lease-ttl must be positive, got %v", *robotLeaseTTL)
+	}
+	if *shutdownTimeout <= 0 {
+		return fmt.Errorf("shutdown-timeout must be positive, got %v", *shutdownTimeout)
+	}
+	return nil
+}
+
+// leaseHolder identifies this instance to the sorting robot.
+func leaseHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("fulfillment-service@%s:%d", hostname, os.Getpid())
 }
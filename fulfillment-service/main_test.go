@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withFlags sets flags for the duration of a test.
+func withFlags(t *testing.T, values map[string]string) {
+	for name, value := range values {
+		name, previous := name, flag.Lookup(name).Value.String()
+		assert.Equal(t, flag.Set(name, value), nil, "Setting "+name+" should succeed")
+		t.Cleanup(func() {
+			flag.Set(name, previous)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		valid bool
+	}{
+		{"Defaults", map[string]string{}, true},
+		{"Missing server address", map[string]string{"server-address": ""}, false},
+		{"Unknown robot driver", map[string]string{"robot-driver": "telepathy"}, false},
+		{"Missing robot address", map[string]string{"robot-address": ""}, false},
+		{"Missing robot address in the pool", map[string]string{"robot-address": "localhost:10000,"}, false},
+		{"Walls", map[string]string{"walls": "A:10,B:5", "robot-address": "A=localhost:10000,B=localhost:10003"}, true},
+		{"Robot of an unknown wall", map[string]string{"walls": "A:10", "robot-address": "B=localhost:10000"}, false},
+		{"Wall without cubbies", map[string]string{"walls": "A:0"}, false},
+		{"Malformed wall", map[string]string{"walls": "A"}, false},
+		{"Wall given twice", map[string]string{"walls": "A:1,A:2"}, false},
+		{"Unknown wall routing", map[string]string{"wall-routing": "random"}, false},
+		{"Negative simulator time", map[string]string{"robot-driver": "simulator", "simulator-pick-time": "-1s"}, false},
+		{"Manual driver ignores robot address", map[string]string{"robot-driver": "manual", "robot-address": ""}, true},
+		{"Zero manual put timeout", map[string]string{"manual-put-timeout": "0s"}, false},
+		{"Unknown admission", map[string]string{"admission": "some"}, false},
+		{"Admission without robot inventory", map[string]string{"robot-driver": "manual", "admission": "reject"}, false},
+		{"Operator driver ignores robot address", map[string]string{"robot-driver": "operator", "robot-address": ""}, true},
+		{"Admission without operator inventory", map[string]string{"robot-driver": "operator", "admission": "backorder"}, false},
+		{"Unknown partial policy", map[string]string{"partial-policy": "drop"}, false},
+		{"Negative partial timeout", map[string]string{"partial-timeout": "-1s"}, false},
+		{"Holding partial orders in batches", map[string]string{"partial-timeout": "1m"}, false},
+		{"Holding partial orders continuously", map[string]string{"partial-timeout": "1m", "continuous": "true"}, true},
+		{"Releasing partial orders in batches", map[string]string{"partial-timeout": "1m", "partial-policy": "release"}, true},
+		{"Zero at-risk margin", map[string]string{"at-risk-margin": "0s"}, false},
+		{"Zero order retention", map[string]string{"order-retention": "0s"}, false},
+		{"Zero cubbies", map[string]string{"number-of-cubbies": "0"}, false},
+		{"Zero lease TTL", map[string]string{"robot-lease-ttl": "0s"}, false},
+		{"Zero shutdown timeout", map[string]string{"shutdown-timeout": "0s"}, false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			withFlags(t, test.flags)
+
+			err := validateConfig()
+			if test.valid {
+				assert.Equal(t, err, nil, test.name+" should be valid")
+			} else {
+				assert.NotNil(t, err, test.name+" should be rejected")
+			}
+		})
+	}
+}
+
+func TestConnectSortingRobotsAddsUnreachableRobotsAsFailed(t *testing.T) {
+	withFlags(t, map[string]string{
+		"robot-address":    "A=localhost:10000,B=localhost:10003",
+		"robot-token-file": filepath.Join(os.TempDir(), "no-such-token"),
+	})
+
+	robots, release := connectSortingRobots(context.Background(), nil, health.NewServer())
+	defer release()
+
+	assert.Equal(t, len(robots), 2, "Every robot should be in the pool")
+	for _, pooled := range robots {
+		assert.Equal(t, pooled.Failed, true, "A robot that could not be connected to should start out failed")
+		_, err := pooled.Robot.Status(context.Background())
+		assert.Equal(t, status.Code(err), codes.Unavailable, "A robot that could not be connected to should be unavailable")
+	}
+	assert.Equal(t, []string{robots[0].WallId, robots[1].WallId}, []string{"A", "B"}, "The robots should keep their walls")
+}
+
+// callAs calls method through the service's authorization as the holder of a
+// token with claims.
+func callAs(t *testing.T, claims auth.Claims, method string) error {
+	dir, err := ioutil.TempDir("", "auth")
+	assert.Equal(t, err, nil, "Creating an auth directory should succeed")
+	defer os.RemoveAll(dir)
+
+	signingKey := []byte("test signing key")
+	signingKeyFile := filepath.Join(dir, "auth.key")
+	assert.Equal(t, ioutil.WriteFile(signingKeyFile, signingKey, 0600), nil, "Writing the signing key should succeed")
+	authenticator, err := auth.Load(signingKeyFile, "")
+	assert.Equal(t, err, nil, "The signing key should load")
+
+	token, err := auth.Sign(signingKey, claims)
+	assert.Equal(t, err, nil, "The token should be signed")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	_, err = authenticator.UnaryServerInterceptor(fulfillmentPolicy)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return err
+}
+
+func TestFulfillmentPolicy(t *testing.T) {
+	expired := time.Now().Add(-time.Minute).Unix()
+	valid := time.Now().Add(time.Minute).Unix()
+	tests := []struct {
+		name   string
+		claims auth.Claims
+		method string
+		code   codes.Code
+	}{
+		{"Intake loads orders", auth.Claims{Subject: "intake", Roles: []auth.Role{auth.RoleIntake}}, "/fulfillment.Fulfillment/LoadOrders", codes.OK},
+		{"Intake with a token yet to expire loads orders", auth.Claims{Subject: "intake", Roles: []auth.Role{auth.RoleIntake}, ExpiresAt: valid}, "/fulfillment.Fulfillment/LoadOrders", codes.OK},
+		{"Intake with an expired token loads orders", auth.Claims{Subject: "intake", Roles: []auth.Role{auth.RoleIntake}, ExpiresAt: expired}, "/fulfillment.Fulfillment/LoadOrders", codes.Unauthenticated},
+		{"Operator loads orders", auth.Claims{Subject: "floor-1", Roles: []auth.Role{auth.RoleOperator}}, "/fulfillment.Fulfillment/LoadOrders", codes.PermissionDenied},
+		{"Operator marks an order fulfilled", auth.Claims{Subject: "floor-1", Roles: []auth.Role{auth.RoleOperator}}, "/fulfillment.Fulfillment/MarkFulfilled", codes.OK},
+		{"Operator with an expired token marks an order fulfilled", auth.Claims{Subject: "floor-1", Roles: []auth.Role{auth.RoleOperator}, ExpiresAt: expired}, "/fulfillment.Fulfillment/MarkFulfilled", codes.Unauthenticated},
+		{"Intake marks an order fulfilled", auth.Claims{Subject: "intake", Roles: []auth.Role{auth.RoleIntake}}, "/fulfillment.Fulfillment/MarkFulfilled", codes.PermissionDenied},
+		{"Fulfillment scans an item", auth.Claims{Subject: "fulfillment-service", Roles: []auth.Role{auth.RoleFulfillment}}, "/fulfillment.Fulfillment/ScanItem", codes.PermissionDenied},
+		{"Operator confirms a put", auth.Claims{Subject: "floor-1", Roles: []auth.Role{auth.RoleOperator}}, "/fulfillment.Fulfillment/ConfirmPut", codes.OK},
+		{"Intake loads items into the simulator", auth.Claims{Subject: "intake", Roles: []auth.Role{auth.RoleIntake}}, "/SortingRobot/LoadItems", codes.OK},
+		{"Fulfillment loads items into the simulator", auth.Claims{Subject: "fulfillment-service", Roles: []auth.Role{auth.RoleFulfillment}}, "/SortingRobot/LoadItems", codes.PermissionDenied},
+		{"Anyone reads order status", auth.Claims{Subject: "reader"}, "/fulfillment.Fulfillment/GetOrderFulfillmentStatusById", codes.OK},
+		{"Anyone with an expired token reads order status", auth.Claims{Subject: "reader", ExpiresAt: expired}, "/fulfillment.Fulfillment/GetOrderFulfillmentStatusById", codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, status.Code(callAs(t, test.claims, test.method)), test.code, test.name+" should be answered with "+test.code.String())
+	}
+}
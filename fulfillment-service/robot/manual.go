@@ -0,0 +1,220 @@
+package robot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/token"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Manual stands in for the robot where people pick for it by hand, one item
+// at a time. An operator picks any item from the input bin and scans it,
+// which hands it to the fulfillment service as its next pick and tells the
+// operator the item's cubby. The operator then confirms putting the item
+// there. An item that is not confirmed within putTimeout counts as lost.
+type Manual struct {
+	scans      chan *manualPick
+	held       *manualPick
+	putTimeout time.Duration
+	picked     int64
+	placed     int64
+	returned   int64
+	mu         sync.Mutex
+}
+
+// manualPick is an item an operator scanned, from the scan until it is put
+// into its cubby or aside.
+type manualPick struct {
+	item      *gen.Item
+	pickToken string
+	// cubby tells the operator where the item goes, or to put it aside if
+	// the cubby is nil.
+	cubby   chan *gen.Cubby
+	cubbyId string
+	told    bool
+	// abandoned is closed when the operator stops waiting for the cubby.
+	abandoned chan struct{}
+	put       chan struct{}
+	confirmed bool
+}
+
+func NewManual(putTimeout time.Duration) *Manual {
+	return &Manual{
+		scans:      make(chan *manualPick),
+		putTimeout: putTimeout,
+		mu:         sync.Mutex{},
+	}
+}
+
+// Scan hands a scanned item to the fulfillment service and returns the cubby
+// it belongs in. It waits until the service is ready for its next item, and
+// fails with NotFound if the item belongs to no order waiting for items.
+func (m *Manual) Scan(ctx context.Context, itemCode string) (*gen.Cubby, error) {
+	pick := &manualPick{
+		item:      &gen.Item{Code: itemCode},
+		cubby:     make(chan *gen.Cubby, 1),
+		abandoned: make(chan struct{}),
+		put:       make(chan struct{}),
+	}
+
+	select {
+	case m.scans <- pick:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	select {
+	case cubby := <-pick.cubby:
+		if cubby == nil {
+			return nil, status.Errorf(codes.NotFound, "item %s belongs to no order waiting for items, put it aside", itemCode)
+		}
+		return cubby, nil
+	case <-ctx.Done():
+		close(pick.abandoned)
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// ConfirmPut records that the scanned item was put into its cubby.
+func (m *Manual) ConfirmPut(ctx context.Context, itemCode, cubbyId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pick := m.held
+	if pick == nil || !pick.told || pick.confirmed || pick.item.Code != itemCode {
+		return status.Errorf(codes.FailedPrecondition, "item %s is not waiting to be put into a cubby", itemCode)
+	}
+	if cubbyId != pick.cubbyId {
+		return status.Errorf(codes.InvalidArgument, "item %s belongs in cubby %s, not %s", itemCode, pick.cubbyId, cubbyId)
+	}
+
+	pick.confirmed = true
+	close(pick.put)
+	return nil
+}
+
+// SelectItem waits for an operator to scan an item.
+func (m *Manual) SelectItem(ctx context.Context) (*gen.SelectItemResponse, error) {
+	pickToken, err := token.New()
+	if err != nil {
+		return nil, err
+	}
+
+	var pick *manualPick
+	select {
+	case pick = <-m.scans:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pick.pickToken = pickToken
+	m.held = pick
+	m.picked++
+	return &gen.SelectItemResponse{Item: pick.item, PickToken: pickToken}, nil
+}
+
+// checkHeld returns the held item if pickToken is its token. Must be called
+// with m.mu held.
+func (m *Manual) checkHeld(pickToken string) (*manualPick, error) {
+	if m.held == nil {
+		return nil, status.Error(codes.FailedPrecondition, "item is not selected")
+	}
+	if pickToken != m.held.pickToken {
+		return nil, status.Error(codes.FailedPrecondition, "pick token does not match the selected item")
+	}
+	return m.held, nil
+}
+
+// MoveItem tells the operator the item's cubby and waits for the put to be
+// confirmed.
+func (m *Manual) MoveItem(ctx context.Context, cubby *gen.Cubby, pickToken string) error {
+	m.mu.Lock()
+	pick, err := m.checkHeld(pickToken)
+	if err == nil && !pick.told {
+		pick.told = true
+		pick.cubbyId = cubby.Id
+		pick.cubby <- cubby
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(m.putTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-pick.put:
+		m.release(pick, &m.placed)
+		return nil
+	case <-pick.abandoned:
+		m.release(pick, nil)
+		return status.Errorf(codes.DataLoss, "operator stopped waiting before item %s was put", pick.item.Code)
+	case <-timer.C:
+		m.release(pick, nil)
+		return status.Errorf(codes.DataLoss, "item %s was not put into cubby %s within %v", pick.item.Code, cubby.Id, m.putTimeout)
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// ReturnItem tells the operator to put the item aside, unless they were
+// already told its cubby.
+func (m *Manual) ReturnItem(ctx context.Context, pickToken string) error {
+	m.mu.Lock()
+	pick, err := m.checkHeld(pickToken)
+	if err == nil && !pick.told {
+		pick.told = true
+		pick.cubby <- nil
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	m.release(pick, &m.returned)
+	return nil
+}
+
+// release lets go of pick once it is done with, counting it in counter.
+func (m *Manual) release(pick *manualPick, counter *int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.held != pick {
+		return
+	}
+	m.held = nil
+	if counter != nil {
+		*counter++
+	}
+}
+
+func (m *Manual) Status(ctx context.Context) (*gen.RobotStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	robotStatus := &gen.RobotStatus{
+		State:         gen.RobotState_IDLE,
+		ItemsPicked:   m.picked,
+		ItemsPlaced:   m.placed,
+		ItemsReturned: m.returned,
+	}
+	if m.held != nil {
+		robotStatus.State = gen.RobotState_HOLDING_ITEM
+		robotStatus.SelectedItem = m.held.item
+	}
+	return robotStatus, nil
+}
+
+// Inventory fails, as the items operators pick from are not counted.
+func (m *Manual) Inventory(ctx context.Context) ([]*gen.StockLevel, error) {
+	return nil, status.Error(codes.FailedPrecondition, "operators pick from stock that is not counted")
+}
@@ -0,0 +1,93 @@
+package robot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scan scans itemCode at manual in the background, as an operator would.
+func scan(manual *Manual, itemCode string) chan error {
+	scanned := make(chan error, 1)
+	go func() {
+		_, err := manual.Scan(context.Background(), itemCode)
+		scanned <- err
+	}()
+	return scanned
+}
+
+func TestManualSortsScannedItem(t *testing.T) {
+	manual := NewManual(time.Minute)
+	ctx := context.Background()
+
+	cubbies := make(chan *gen.Cubby, 1)
+	go func() {
+		cubby, _ := manual.Scan(ctx, "a")
+		cubbies <- cubby
+	}()
+
+	resp, err := manual.SelectItem(ctx)
+	assert.Equal(t, err, nil, "Selecting should succeed once an operator scans an item")
+	assert.Equal(t, resp.Item.Code, "a", "The scanned item should be picked")
+
+	moved := make(chan error, 1)
+	go func() {
+		moved <- manual.MoveItem(ctx, &gen.Cubby{Id: "7"}, resp.PickToken)
+	}()
+	assert.Equal(t, (<-cubbies).Id, "7", "The operator should be told the item's cubby")
+
+	assert.Equal(t, status.Code(manual.ConfirmPut(ctx, "a", "8")), codes.InvalidArgument, "Confirming the wrong cubby should fail")
+	assert.Equal(t, status.Code(manual.ConfirmPut(ctx, "b", "7")), codes.FailedPrecondition, "Confirming another item should fail")
+	assert.Equal(t, manual.ConfirmPut(ctx, "a", "7"), nil, "Confirming the put should succeed")
+	assert.Equal(t, <-moved, nil, "The move should end with the confirmed put")
+
+	robotStatus, _ := manual.Status(ctx)
+	assert.Equal(t, robotStatus.State, gen.RobotState_IDLE, "The operator should be free for the next item")
+	assert.Equal(t, []int64{robotStatus.ItemsPicked, robotStatus.ItemsPlaced, robotStatus.ItemsReturned}, []int64{1, 1, 0}, "The pick and the put should be counted")
+}
+
+func TestManualPutsReturnedItemAside(t *testing.T) {
+	manual := NewManual(time.Minute)
+	ctx := context.Background()
+
+	scanned := scan(manual, "a")
+	resp, _ := manual.SelectItem(ctx)
+	robotStatus, _ := manual.Status(ctx)
+	assert.Equal(t, robotStatus.State, gen.RobotState_HOLDING_ITEM, "The scanned item should be held")
+
+	assert.Equal(t, manual.ReturnItem(ctx, resp.PickToken), nil, "Returning the item should succeed")
+	assert.Equal(t, status.Code(<-scanned), codes.NotFound, "The operator should be told to put the item aside")
+	assert.NotNil(t, manual.ReturnItem(ctx, resp.PickToken), "An item should be returned once")
+}
+
+func TestManualLosesItemNotPutInTime(t *testing.T) {
+	manual := NewManual(10 * time.Millisecond)
+	ctx := context.Background()
+
+	scanned := scan(manual, "a")
+	resp, _ := manual.SelectItem(ctx)
+	err := manual.MoveItem(ctx, &gen.Cubby{Id: "7"}, resp.PickToken)
+	assert.Equal(t, status.Code(err), codes.DataLoss, "An item not put in time should count as lost")
+	assert.Equal(t, <-scanned, nil, "The operator should have been told the cubby")
+	assert.Equal(t, status.Code(manual.ConfirmPut(ctx, "a", "7")), codes.FailedPrecondition, "A lost item should not be confirmed")
+}
+
+func TestManualWaitsForAScan(t *testing.T) {
+	manual := NewManual(time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := manual.SelectItem(ctx)
+	assert.Equal(t, status.Code(err), codes.DeadlineExceeded, "Selecting should wait for a scan until the context is done")
+
+	_, err = manual.Scan(ctx, "a")
+	assert.Equal(t, status.Code(err), codes.DeadlineExceeded, "Scanning should wait for the service until the context is done")
+
+	_, err = manual.Inventory(context.Background())
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "Operators should have no inventory")
+}
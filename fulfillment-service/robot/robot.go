@@ -0,0 +1,78 @@
+// Package robot drives what sorts items into cubbies: the sorting robot over
+// gRPC, a robot simulated in process or operators picking by hand.
+package robot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// Robot picks items from the input bin and puts them into cubbies, one item
+// at a time. Drivers fail the way the sorting robot does: Aborted when the
+// picked item could not be identified, Unavailable while stopped or jammed,
+// and DataLoss when the held item was lost on the way to its cubby.
+type Robot interface {
+	// SelectItem picks the next item and returns it with the token that
+	// moving or returning it takes.
+	SelectItem(ctx context.Context) (*gen.SelectItemResponse, error)
+	// MoveItem puts the picked item into cubby.
+	MoveItem(ctx context.Context, cubby *gen.Cubby, pickToken string) error
+	// ReturnItem puts the picked item back into the input bin.
+	ReturnItem(ctx context.Context, pickToken string) error
+	// Status reports what the robot is doing.
+	Status(ctx context.Context) (*gen.RobotStatus, error)
+	// Inventory counts the items the robot has yet to sort, by code.
+	Inventory(ctx context.Context) ([]*gen.StockLevel, error)
+}
+
+// Drivers lists the drivers the fulfillment service can be configured with.
+// The operator driver is Manual. The manual driver has no Robot; operators
+// sort at a put wall instead.
+var Drivers = []string{"grpc", "simulator", "operator", "manual"}
+
+type grpcRobot struct {
+	client gen.SortingRobotClient
+}
+
+// NewGRPC drives the sorting robot service through client.
+func NewGRPC(client gen.SortingRobotClient) Robot {
+	return &grpcRobot{client: client}
+}
+
+func (r *grpcRobot) SelectItem(ctx context.Context) (*gen.SelectItemResponse, error) {
+	return r.client.SelectItem(ctx, &gen.Empty{})
+}
+
+func (r *grpcRobot) MoveItem(ctx context.Context, cubby *gen.Cubby, pickToken string) error {
+	_, err := r.client.MoveItem(ctx, &gen.MoveItemRequest{Cubby: cubby, PickToken: pickToken})
+	return err
+}
+
+func (r *grpcRobot) ReturnItem(ctx context.Context, pickToken string) error {
+	_, err := r.client.ReturnItem(ctx, &gen.ReturnItemRequest{PickToken: pickToken})
+	return err
+}
+
+func (r *grpcRobot) Status(ctx context.Context) (*gen.RobotStatus, error) {
+	return r.client.GetRobotStatus(ctx, &gen.Empty{})
+}
+
+func (r *grpcRobot) Inventory(ctx context.Context) ([]*gen.StockLevel, error) {
+	resp, err := r.client.ListInventory(ctx, &gen.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// ValidateDriver fails unless driver is one of Drivers.
+func ValidateDriver(driver string) error {
+	for _, name := range Drivers {
+		if driver == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown robot driver %q, must be one of %v", driver, Drivers)
+}
@@ -0,0 +1,225 @@
+package robot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/inventory"
+	"github.com/Emoto13/sort-system/common/token"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/status"
+)
+
+// Simulator is a robot that runs inside the fulfillment service, for trying
+// the system out without the sorting robot. It picks items at random from
+// its input bin, takes pickTime to pick and placeTime to put an item down,
+// and never fails.
+type Simulator struct {
+	bin       []*gen.Item
+	held      *gen.Item
+	pickToken string
+	cubbies   map[string][]*gen.Item
+	pickTime  time.Duration
+	placeTime time.Duration
+	picked    int64
+	placed    int64
+	returned  int64
+	random    *rand.Rand
+	mu        sync.Mutex
+}
+
+func NewSimulator(pickTime, placeTime time.Duration) *Simulator {
+	return &Simulator{
+		cubbies:   make(map[string][]*gen.Item),
+		pickTime:  pickTime,
+		placeTime: placeTime,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		mu:        sync.Mutex{},
+	}
+}
+
+// LoadItems puts items into the input bin.
+func (s *Simulator) LoadItems(items []*gen.Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bin = append(s.bin, items...)
+}
+
+// wait sleeps for the duration of an operation.
+func wait(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (s *Simulator) SelectItem(ctx context.Context) (*gen.SelectItemResponse, error) {
+	if err := wait(ctx, s.pickTime); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.held != nil {
+		return nil, fmt.Errorf("item has already been selected")
+	}
+	if len(s.bin) == 0 {
+		return nil, fmt.Errorf("no items in the cargo")
+	}
+
+	pickToken, err := token.New()
+	if err != nil {
+		return nil, err
+	}
+
+	i := s.random.Intn(len(s.bin))
+	s.held = s.bin[i]
+	s.bin = append(s.bin[:i], s.bin[i+1:]...)
+	s.pickToken = pickToken
+	s.picked++
+	return &gen.SelectItemResponse{Item: s.held, PickToken: pickToken}, nil
+}
+
+// checkHeld fails unless pickToken is the token of the held item. Must be
+// called with s.mu held.
+func (s *Simulator) checkHeld(pickToken string) error {
+	if s.held == nil {
+		return fmt.Errorf("item is not selected")
+	}
+	if pickToken != s.pickToken {
+		return fmt.Errorf("pick token does not match the selected item")
+	}
+	return nil
+}
+
+func (s *Simulator) MoveItem(ctx context.Context, cubby *gen.Cubby, pickToken string) error {
+	if err := wait(ctx, s.placeTime); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkHeld(pickToken); err != nil {
+		return err
+	}
+
+	s.cubbies[cubby.Id] = append(s.cubbies[cubby.Id], s.held)
+	s.held = nil
+	s.placed++
+	return nil
+}
+
+func (s *Simulator) ReturnItem(ctx context.Context, pickToken string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkHeld(pickToken); err != nil {
+		return err
+	}
+
+	s.bin = append(s.bin, s.held)
+	s.held = nil
+	s.returned++
+	return nil
+}
+
+func (s *Simulator) Status(ctx context.Context) (*gen.RobotStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := gen.RobotState_IDLE
+	if s.held != nil {
+		state = gen.RobotState_HOLDING_ITEM
+	}
+
+	return &gen.RobotStatus{
+		State:         state,
+		SelectedItem:  s.held,
+		ItemsInBin:    int32(len(s.bin)),
+		ItemsPicked:   s.picked,
+		ItemsPlaced:   s.placed,
+		ItemsReturned: s.returned,
+	}, nil
+}
+
+// Inventory counts the items in the input bin and the held item by code.
+func (s *Simulator) Inventory(ctx context.Context) ([]*gen.StockLevel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := append([]*gen.Item{}, s.bin...)
+	if s.held != nil {
+		items = append(items, s.held)
+	}
+	return inventory.Levels(inventory.Count(items)), nil
+}
+
+// Audit returns the items in each cubby, by cubby id.
+func (s *Simulator) Audit() []*gen.CubbyToItems {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cubbyIds := []string{}
+	for cubbyId := range s.cubbies {
+		cubbyIds = append(cubbyIds, cubbyId)
+	}
+	sort.Strings(cubbyIds)
+
+	cubbiesToItems := []*gen.CubbyToItems{}
+	for _, cubbyId := range cubbyIds {
+		cubbiesToItems = append(cubbiesToItems, &gen.CubbyToItems{Cubby: &gen.Cubby{Id: cubbyId}, Items: s.cubbies[cubbyId]})
+	}
+	return cubbiesToItems
+}
+
+// Server serves the simulator as the sorting robot's gRPC API, so items can
+// be loaded and the robot inspected with the same tools. Only LoadItems,
+// AuditState, GetRobotStatus and ListInventory are served; the fulfillment
+// service drives the simulator itself.
+func (s *Simulator) Server() gen.SortingRobotServer {
+	return &simulatorServer{simulator: s}
+}
+
+type simulatorServer struct {
+	gen.UnimplementedSortingRobotServer
+	simulator *Simulator
+}
+
+func (s *simulatorServer) LoadItems(ctx context.Context, in *gen.LoadItemsRequest) (*gen.Empty, error) {
+	s.simulator.LoadItems(in.Items)
+	log.Printf("Loaded %d items into the simulated robot.", len(in.Items))
+	return &gen.Empty{}, nil
+}
+
+func (s *simulatorServer) AuditState(ctx context.Context, in *gen.Empty) (*gen.AuditStateResponse, error) {
+	return &gen.AuditStateResponse{CubbiesToItems: s.simulator.Audit()}, nil
+}
+
+func (s *simulatorServer) GetRobotStatus(ctx context.Context, in *gen.Empty) (*gen.RobotStatus, error) {
+	return s.simulator.Status(ctx)
+}
+
+func (s *simulatorServer) ListInventory(ctx context.Context, in *gen.Empty) (*gen.InventoryResponse, error) {
+	items, err := s.simulator.Inventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &gen.InventoryResponse{Items: items}, nil
+}
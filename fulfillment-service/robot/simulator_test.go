@@ -0,0 +1,174 @@
+package robot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func itemsByCubby(cubbiesToItems []*gen.CubbyToItems) map[string][]string {
+	cubbies := map[string][]string{}
+	for _, cubbyToItems := range cubbiesToItems {
+		for _, item := range cubbyToItems.Items {
+			cubbies[cubbyToItems.Cubby.Id] = append(cubbies[cubbyToItems.Cubby.Id], item.Code)
+		}
+	}
+	return cubbies
+}
+
+func TestSimulatorSortsEveryItem(t *testing.T) {
+	simulator := NewSimulator(0, 0)
+	simulator.LoadItems([]*gen.Item{{Code: "a"}, {Code: "b"}, {Code: "c"}})
+	ctx := context.Background()
+
+	picked := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		resp, err := simulator.SelectItem(ctx)
+		assert.Equal(t, err, nil, "Selecting an item should succeed while the bin holds items")
+		assert.Equal(t, picked[resp.Item.Code], false, "An item should be picked once")
+		picked[resp.Item.Code] = true
+
+		assert.Equal(t, simulator.MoveItem(ctx, &gen.Cubby{Id: resp.Item.Code + "-cubby"}, resp.PickToken), nil, "Moving the picked item should succeed")
+	}
+
+	_, err := simulator.SelectItem(ctx)
+	assert.NotNil(t, err, "Selecting from an empty bin should fail")
+	assert.Equal(t, itemsByCubby(simulator.Audit()), map[string][]string{"a-cubby": {"a"}, "b-cubby": {"b"}, "c-cubby": {"c"}}, "Every item should be in the cubby it was moved to")
+}
+
+func TestSimulatorChecksThePick(t *testing.T) {
+	simulator := NewSimulator(0, 0)
+	simulator.LoadItems([]*gen.Item{{Code: "a"}, {Code: "b"}})
+	ctx := context.Background()
+
+	assert.NotNil(t, simulator.MoveItem(ctx, &gen.Cubby{Id: "1"}, "token"), "Moving without a selected item should fail")
+	assert.NotNil(t, simulator.ReturnItem(ctx, "token"), "Returning without a selected item should fail")
+
+	resp, err := simulator.SelectItem(ctx)
+	assert.Equal(t, err, nil, "Selecting an item should succeed")
+	_, err = simulator.SelectItem(ctx)
+	assert.NotNil(t, err, "Selecting while holding an item should fail")
+	assert.NotNil(t, simulator.MoveItem(ctx, &gen.Cubby{Id: "1"}, "another-token"), "Moving with another pick's token should fail")
+	assert.NotNil(t, simulator.ReturnItem(ctx, "another-token"), "Returning with another pick's token should fail")
+
+	assert.Equal(t, simulator.MoveItem(ctx, &gen.Cubby{Id: "1"}, resp.PickToken), nil, "Moving with the pick's token should succeed")
+	assert.NotNil(t, simulator.MoveItem(ctx, &gen.Cubby{Id: "1"}, resp.PickToken), "A pick should be moved once")
+}
+
+func TestSimulatorReturnsItemToTheBin(t *testing.T) {
+	simulator := NewSimulator(0, 0)
+	simulator.LoadItems([]*gen.Item{{Code: "a"}, {Code: "a"}, {Code: "b"}})
+	ctx := context.Background()
+
+	resp, err := simulator.SelectItem(ctx)
+	assert.Equal(t, err, nil, "Selecting an item should succeed")
+	inventory, _ := simulator.Inventory(ctx)
+	assert.Equal(t, inventory, []*gen.StockLevel{{Code: "a", Quantity: 2}, {Code: "b", Quantity: 1}}, "The held item should be counted with the bin")
+
+	robotStatus, _ := simulator.Status(ctx)
+	assert.Equal(t, robotStatus.State, gen.RobotState_HOLDING_ITEM, "The simulator should be holding the item")
+	assert.Equal(t, robotStatus.ItemsInBin, int32(2), "The held item should no longer be in the bin")
+
+	assert.Equal(t, simulator.ReturnItem(ctx, resp.PickToken), nil, "Returning the picked item should succeed")
+	robotStatus, _ = simulator.Status(ctx)
+	assert.Equal(t, robotStatus.State, gen.RobotState_IDLE, "The simulator should be idle once the item is returned")
+	assert.Equal(t, robotStatus.ItemsInBin, int32(3), "The returned item should be back in the bin")
+	assert.Equal(t, []int64{robotStatus.ItemsPicked, robotStatus.ItemsPlaced, robotStatus.ItemsReturned}, []int64{1, 0, 1}, "The pick and the return should be counted")
+	assert.Equal(t, len(simulator.Audit()), 0, "No item should be in a cubby")
+}
+
+func TestSimulatorTakesItsTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		pickTime  time.Duration
+		placeTime time.Duration
+		timeout   time.Duration
+		picks     bool
+		places    bool
+	}{
+		{"Instant", 0, 0, time.Second, true, true},
+		{"Picks in time", 10 * time.Millisecond, 0, time.Second, true, true},
+		{"Picks too slowly", time.Minute, 0, 20 * time.Millisecond, false, false},
+		{"Places too slowly", 0, time.Minute, 20 * time.Millisecond, true, false},
+	}
+
+	for _, test := range tests {
+		simulator := NewSimulator(test.pickTime, test.placeTime)
+		simulator.LoadItems([]*gen.Item{{Code: "a"}})
+		ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+
+		resp, err := simulator.SelectItem(ctx)
+		if !test.picks {
+			assert.Equal(t, status.Code(err), codes.DeadlineExceeded, test.name+": the pick should end with the context")
+			cancel()
+			continue
+		}
+		assert.Equal(t, err, nil, test.name+": the pick should succeed")
+
+		err = simulator.MoveItem(ctx, &gen.Cubby{Id: "1"}, resp.PickToken)
+		if test.places {
+			assert.Equal(t, err, nil, test.name+": the move should succeed")
+		} else {
+			assert.Equal(t, status.Code(err), codes.DeadlineExceeded, test.name+": the move should end with the context")
+			robotStatus, _ := simulator.Status(context.Background())
+			assert.Equal(t, robotStatus.State, gen.RobotState_HOLDING_ITEM, test.name+": the item should still be held")
+		}
+		cancel()
+	}
+}
+
+func TestSimulatorServer(t *testing.T) {
+	simulator := NewSimulator(0, 0)
+	server := simulator.Server()
+	ctx := context.Background()
+
+	_, err := server.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "a"}, {Code: "b"}, {Code: "b"}}})
+	assert.Equal(t, err, nil, "Loading items should succeed")
+
+	inventory, err := server.ListInventory(ctx, &gen.Empty{})
+	assert.Equal(t, err, nil, "Listing the inventory should succeed")
+	assert.Equal(t, inventory.Items, []*gen.StockLevel{{Code: "a", Quantity: 1}, {Code: "b", Quantity: 2}}, "The loaded items should be in stock")
+
+	resp, _ := simulator.SelectItem(ctx)
+	simulator.MoveItem(ctx, &gen.Cubby{Id: "7"}, resp.PickToken)
+
+	robotStatus, err := server.GetRobotStatus(ctx, &gen.Empty{})
+	assert.Equal(t, err, nil, "Getting the status should succeed")
+	assert.Equal(t, []int64{int64(robotStatus.ItemsInBin), robotStatus.ItemsPicked, robotStatus.ItemsPlaced}, []int64{2, 1, 1}, "The status should count the sorted item")
+
+	audit, err := server.AuditState(ctx, &gen.Empty{})
+	assert.Equal(t, err, nil, "Auditing should succeed")
+	assert.Equal(t, itemsByCubby(audit.CubbiesToItems), map[string][]string{"7": {resp.Item.Code}}, "The audit should show the sorted item")
+
+	_, err = server.SelectItem(ctx, &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.Unimplemented, "Only the fulfillment service should pick with the simulator")
+}
+
+func TestValidateDriver(t *testing.T) {
+	tests := []struct {
+		driver string
+		valid  bool
+	}{
+		{"grpc", true},
+		{"simulator", true},
+		{"operator", true},
+		{"manual", true},
+		{"", false},
+		{"GRPC", false},
+		{"telepathy", false},
+	}
+
+	for _, test := range tests {
+		err := ValidateDriver(test.driver)
+		if test.valid {
+			assert.Equal(t, err, nil, test.driver+" should be a driver")
+		} else {
+			assert.NotNil(t, err, "\""+test.driver+"\" should not be a driver")
+		}
+	}
+}
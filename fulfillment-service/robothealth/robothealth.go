@@ -0,0 +1,133 @@
+package robothealth
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	minRetryDelay = 100 * time.Millisecond
+	maxRetryDelay = 5 * time.Second
+)
+
+// Monitor follows the sorting robot's grpc.health.v1 status so work can be
+// held back while the robot is down, stopped or jammed.
+type Monitor struct {
+	client  healthpb.HealthClient
+	healthy bool
+	// changed is closed and replaced every time the robot's health changes.
+	changed  chan struct{}
+	onChange func(healthy bool)
+	mu       sync.Mutex
+}
+
+// NewMonitor creates a monitor that starts out treating the robot as
+// unhealthy. onChange is called every time that changes.
+func NewMonitor(conn grpc.ClientConnInterface, onChange func(healthy bool)) *Monitor {
+	return &Monitor{
+		client:   healthpb.NewHealthClient(conn),
+		changed:  make(chan struct{}),
+		onChange: onChange,
+		mu:       sync.Mutex{},
+	}
+}
+
+func (m *Monitor) Healthy() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.healthy
+}
+
+// setHealthy records the robot's health. It is only called from Watch, so
+// onChange sees the changes in order.
+func (m *Monitor) setHealthy(healthy bool) {
+	m.mu.Lock()
+	if m.healthy == healthy {
+		m.mu.Unlock()
+		return
+	}
+
+	m.healthy = healthy
+	close(m.changed)
+	m.changed = make(chan struct{})
+	m.mu.Unlock()
+
+	if healthy {
+		log.Println("Sorting robot is healthy.")
+	} else {
+		log.Println("Sorting robot is not healthy, holding back batches.")
+	}
+	m.onChange(healthy)
+}
+
+// WaitHealthy blocks until the robot is healthy or ctx is done.
+func (m *Monitor) WaitHealthy(ctx context.Context) error {
+	for {
+		m.mu.Lock()
+		healthy, changed := m.healthy, m.changed
+		m.mu.Unlock()
+
+		if healthy {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Watch follows the robot's health until ctx is done. While the robot cannot
+// be reached it counts as unhealthy and the watch is retried with backoff.
+func (m *Monitor) Watch(ctx context.Context) {
+	retryDelay := minRetryDelay
+	for {
+		err := m.watch(ctx, func() {
+			retryDelay = minRetryDelay
+		})
+		m.setHealthy(false)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Println("Error while watching sorting robot health occured: ", err.Error(), "\nTrying again in", retryDelay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+
+		retryDelay *= 2
+		if retryDelay > maxRetryDelay {
+			retryDelay = maxRetryDelay
+		}
+	}
+}
+
+// watch streams health updates until the stream breaks. received is called
+// for every update.
+func (m *Monitor) watch(ctx context.Context, received func()) error {
+	stream, err := m.client.Watch(ctx, &healthpb.HealthCheckRequest{Service: gen.SortingRobot_ServiceDesc.ServiceName})
+	if err != nil {
+		return err
+	}
+
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		received()
+		m.setHealthy(response.Status == healthpb.HealthCheckResponse_SERVING)
+	}
+}
@@ -0,0 +1,114 @@
+package robothealth
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testTimeout = time.Second
+
+// robotHealth serves the health of a stand-in sorting robot in memory.
+func robotHealth(t *testing.T) (*health.Server, *grpc.Server, *grpc.ClientConn) {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(gen.SortingRobot_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	dialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(dialer), grpc.WithInsecure())
+	assert.Equal(t, err, nil, "Dialing the robot should succeed")
+	t.Cleanup(func() { conn.Close() })
+	return healthServer, server, conn
+}
+
+// waitForChange returns the next health change the monitor reports.
+func waitForChange(t *testing.T, changes chan bool) bool {
+	select {
+	case healthy := <-changes:
+		return healthy
+	case <-time.After(testTimeout):
+		t.Fatalf("robot health did not change within %v", testTimeout)
+		return false
+	}
+}
+
+func TestMonitorFollowsRobotStatus(t *testing.T) {
+	healthServer, _, conn := robotHealth(t)
+	changes := make(chan bool, 10)
+	monitor := NewMonitor(conn, func(healthy bool) {
+		changes <- healthy
+	})
+	assert.Equal(t, monitor.Healthy(), false, "The robot should count as unhealthy until it reports otherwise")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go monitor.Watch(ctx)
+
+	tests := []struct {
+		name    string
+		status  healthpb.HealthCheckResponse_ServingStatus
+		healthy bool
+	}{
+		{"Serving", healthpb.HealthCheckResponse_SERVING, true},
+		{"Not serving", healthpb.HealthCheckResponse_NOT_SERVING, false},
+		{"Serving again", healthpb.HealthCheckResponse_SERVING, true},
+		{"Unknown service", healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false},
+	}
+
+	for _, test := range tests {
+		healthServer.SetServingStatus(gen.SortingRobot_ServiceDesc.ServiceName, test.status)
+		assert.Equal(t, waitForChange(t, changes), test.healthy, test.name+" should be reported")
+		assert.Equal(t, monitor.Healthy(), test.healthy, test.name+" should be the robot's health")
+	}
+}
+
+func TestMonitorTreatsUnreachableRobotAsUnhealthy(t *testing.T) {
+	healthServer, server, conn := robotHealth(t)
+	changes := make(chan bool, 10)
+	monitor := NewMonitor(conn, func(healthy bool) {
+		changes <- healthy
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go monitor.Watch(ctx)
+
+	healthServer.SetServingStatus(gen.SortingRobot_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	assert.Equal(t, waitForChange(t, changes), true, "The robot should become healthy")
+
+	server.Stop()
+	assert.Equal(t, waitForChange(t, changes), false, "A robot that cannot be reached should become unhealthy")
+}
+
+func TestWaitHealthy(t *testing.T) {
+	healthServer, _, conn := robotHealth(t)
+	monitor := NewMonitor(conn, func(healthy bool) {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, monitor.WaitHealthy(ctx), context.DeadlineExceeded, "Waiting should end when the context is done")
+
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+	defer watchCancel()
+	go monitor.Watch(watchCtx)
+	healthServer.SetServingStatus(gen.SortingRobot_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), testTimeout)
+	defer waitCancel()
+	assert.Equal(t, monitor.WaitHealthy(waitCtx), nil, "Waiting should end once the robot is healthy")
+}
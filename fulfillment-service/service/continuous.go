@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// flow keeps track of the orders being sorted continuously. Orders join as
+// soon as a cubby is free for them, every order is done on its own, and a
+// cubby is freed when its order is picked up.
+type flow struct {
+	// picks holds the picks of the orders waiting for items, by the wall the
+	// robots making them serve.
+	picks map[string]*batchPicks
+	// sorting tells which robots are sorting.
+	sorting  map[*poolRobot]bool
+	admitted []*gen.Order
+	workers  sync.WaitGroup
+	// cubbyFreed is signalled whenever an order is picked up.
+	cubbyFreed chan struct{}
+	mu         sync.Mutex
+}
+
+func newFlow() *flow {
+	return &flow{
+		picks:      make(map[string]*batchPicks),
+		sorting:    make(map[*poolRobot]bool),
+		cubbyFreed: make(chan struct{}, 1),
+		mu:         sync.Mutex{},
+	}
+}
+
+// picksFor returns the picks made by the robots of wallId. Must be called
+// with f.mu held.
+func (f *flow) picksFor(wallId string) *batchPicks {
+	picks, ok := f.picks[wallId]
+	if !ok {
+		picks = openPicks()
+		f.picks[wallId] = picks
+	}
+	return picks
+}
+
+// processContinuously sorts orders as they are loaded until the service is
+// shut down.
+func (fs *fulfillmentService) processContinuously(ctx context.Context) error {
+	if fs.putWall != nil {
+		go fs.expirePuts()
+	}
+
+	for {
+		var orders []*gen.Order
+		select {
+		case orders = <-fs.orders:
+		case <-fs.stopping:
+			fs.stopFlow(nil)
+			return nil
+		}
+
+		for i, order := range orders {
+			if !fs.waitForFreeCubby() {
+				fs.stopFlow(orders[i:])
+				return nil
+			}
+			fs.admit(ctx, order)
+		}
+	}
+}
+
+// waitForFreeCubby waits until an order is picked up if every cubby is
+// taken. It reports false if the service shuts down first.
+func (fs *fulfillmentService) waitForFreeCubby() bool {
+	for fs.state.FreeCubbies() == 0 {
+		select {
+		case <-fs.flow.cubbyFreed:
+		case <-fs.stopping:
+			return false
+		}
+	}
+	return true
+}
+
+// admit adds order to the orders being sorted and has every available robot
+// that is not sorting yet start.
+func (fs *fulfillmentService) admit(ctx context.Context, order *gen.Order) {
+	fs.state.AddOrders([]*gen.Order{order})
+
+	fs.flow.mu.Lock()
+	defer fs.flow.mu.Unlock()
+
+	fs.flow.admitted = append(fs.flow.admitted, order)
+	if fs.putWall != nil {
+		fs.putWall.add(len(order.Items))
+		return
+	}
+
+	served := map[string]bool{}
+	for _, r := range fs.pool.available(ctx) {
+		served[r.wallId] = true
+		if fs.flow.sorting[r] {
+			continue
+		}
+
+		fs.flow.sorting[r] = true
+		fs.flow.workers.Add(1)
+		go fs.sortContinuously(ctx, r, fs.flow.picksFor(r.wallId))
+	}
+
+	wallId := ""
+	if orderData, err := fs.state.GetOrderDataById(order.Id); err == nil {
+		wallId = orderData.Cubby.WallId
+	}
+	if !served[wallId] && served[""] {
+		wallId = ""
+	}
+	fs.flow.picksFor(wallId).add(order)
+}
+
+// sortContinuously makes picks with r until the service shuts down. When r
+// cannot pick, e.g. as its input bin is empty, it tries again a while later.
+// A robot that failed stops until it is back in the pool when the next order
+// is admitted.
+func (fs *fulfillmentService) sortContinuously(ctx context.Context, r *poolRobot, picks *batchPicks) {
+	defer fs.flow.workers.Done()
+
+	for {
+		err := fs.sortWith(ctx, r, picks)
+		if err == nil || err == errShuttingDown || ctx.Err() != nil {
+			break
+		}
+		log.Println("Error while sorting with robot ", r.name, " occured: ", err.Error())
+		if fs.pool.isFailed(r) {
+			break
+		}
+
+		timer := time.NewTimer(robotStatusPollInterval)
+		select {
+		case <-timer.C:
+		case <-fs.stopping:
+		}
+		timer.Stop()
+	}
+
+	fs.flow.mu.Lock()
+	defer fs.flow.mu.Unlock()
+
+	fs.flow.sorting[r] = false
+}
+
+// stopFlow lets the robots finish the items they hold and records the orders
+// that did not get all their items, followed by the loaded orders that were
+// not admitted.
+func (fs *fulfillmentService) stopFlow(notAdmitted []*gen.Order) {
+	fs.flow.mu.Lock()
+	for _, picks := range fs.flow.picks {
+		picks.close()
+	}
+	admitted := fs.flow.admitted
+	fs.flow.mu.Unlock()
+
+	fs.flow.workers.Wait()
+	fs.interrupted = append(fs.unfinishedOrders(admitted), notAdmitted...)
+}
+
+// releaseOrder frees the cubby of an order that was picked up and stops
+// sorting items for it.
+func (fs *fulfillmentService) releaseOrder(orderId string) error {
+	if err := fs.state.ReleaseCubby(orderId); err != nil {
+		return err
+	}
+	fs.reservations.release(orderId)
+
+	fs.flow.mu.Lock()
+	for _, picks := range fs.flow.picks {
+		picks.removeOrder(orderId)
+	}
+	admitted := []*gen.Order{}
+	for _, order := range fs.flow.admitted {
+		if order.Id != orderId {
+			admitted = append(admitted, order)
+		}
+	}
+	fs.flow.admitted = admitted
+	fs.flow.mu.Unlock()
+
+	select {
+	case fs.flow.cubbyFreed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// expirePuts counts the items at the put wall that were not put in time as
+// lost until the service shuts down.
+func (fs *fulfillmentService) expirePuts() {
+	for {
+		timer := time.NewTimer(fs.putWall.expire(time.Now()))
+		select {
+		case <-timer.C:
+		case <-fs.stopping:
+			timer.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/state"
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// defaultAtRiskMargin is how close to its deadline an order that is not
+// ready yet counts as at risk when no margin is configured.
+const defaultAtRiskMargin = 5 * time.Minute
+
+// fulfillmentStatus reports the progress of the order orderData describes.
+func fulfillmentStatus(orderData state.OrderData) *gen.FulfillmentStatus {
+	order := &gen.Order{
+		Id:                   orderData.Id,
+		Items:                orderData.Items,
+		CustomerId:           orderData.CustomerId,
+		Priority:             orderData.Priority,
+		ReadyByMillis:        orderData.ReadyByMillis,
+		PartialPolicy:        orderData.PartialPolicy,
+		PartialTimeoutMillis: orderData.PartialTimeoutMillis,
+	}
+	return &gen.FulfillmentStatus{
+		Order:           order,
+		Cubby:           orderData.Cubby,
+		Status:          orderData.Status,
+		MissingItems:    orderData.MissingItems,
+		PartialDecision: orderData.PartialDecision,
+	}
+}
+
+// waitsForItems tells whether an order may still get items, unlike orders
+// that are ready, failed, released as they are or cancelled.
+func waitsForItems(status *gen.FulfillmentStatus) bool {
+	switch status.Status {
+	case gen.OrderStatus_PENDING, gen.OrderStatus_BACKORDERED:
+		return true
+	case gen.OrderStatus_PARTIALLY_READY:
+		return status.PartialDecision == gen.PartialPolicy_HOLD_PARTIAL
+	default:
+		return false
+	}
+}
+
+// GetOrdersAtRisk lists the orders that are not ready yet, backorders
+// included, and are due within the requested window, or are already overdue,
+// the most urgent first.
+func (fs *fulfillmentService) GetOrdersAtRisk(ctx context.Context, in *gen.OrdersAtRiskRequest) (*gen.OrdersAtRiskResponse, error) {
+	within := fs.atRiskMargin
+	if in.WithinMillis > 0 {
+		within = time.Duration(in.WithinMillis) * time.Millisecond
+	}
+
+	statuses, err := fs.fulfillmentStatuses()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	atRisk := []*gen.OrderAtRisk{}
+	for _, status := range statuses {
+		if status.Order.ReadyByMillis == 0 || !waitsForItems(status) {
+			continue
+		}
+
+		millisLeft := status.Order.ReadyByMillis - now
+		if millisLeft <= within.Milliseconds() {
+			atRisk = append(atRisk, &gen.OrderAtRisk{FulfillmentStatus: status, MillisLeft: millisLeft})
+		}
+	}
+
+	sort.SliceStable(atRisk, func(i, j int) bool {
+		return atRisk[i].MillisLeft < atRisk[j].MillisLeft
+	})
+	return &gen.OrdersAtRiskResponse{Orders: atRisk}, nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/inventory"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// backorderPollInterval is how often the robots' inventory is checked for
+// the items backordered orders wait for.
+const backorderPollInterval = time.Second
+
+var errNoInventory = status.Error(codes.FailedPrecondition, "items are sorted at a put wall, there is no robot inventory to admit orders against")
+
+// Admission decides what happens to loaded orders whose items the robots do
+// not hold.
+type Admission string
+
+const (
+	// AdmitAll admits every order without looking at the robots' inventory,
+	// for items that are loaded after their orders.
+	AdmitAll Admission = "all"
+	// RejectShort refuses the orders the robots do not hold every item of.
+	RejectShort Admission = "reject"
+	// BackorderShort holds such orders back until their items are in stock.
+	BackorderShort Admission = "backorder"
+)
+
+// Admissions lists the admission policies.
+var Admissions = []Admission{AdmitAll, RejectShort, BackorderShort}
+
+// ValidateAdmission fails unless admission is one of Admissions.
+func ValidateAdmission(admission string) error {
+	for _, name := range Admissions {
+		if Admission(admission) == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown admission policy %q, must be one of %v", admission, Admissions)
+}
+
+// reservations keeps the robots' stock set aside for the admitted orders, so
+// that an order is only admitted if its items are not already promised to
+// another, and the orders waiting for stock.
+type reservations struct {
+	// reserved holds the quantity of every item code set aside for an
+	// order, by order id.
+	reserved map[string]map[string]int32
+	// orderIds are the orders with reservations, in the order they were
+	// admitted.
+	orderIds   []string
+	backorders []*backorder
+	mu         sync.Mutex
+}
+
+// backorder is an order waiting for the items it misses since it was loaded.
+type backorder struct {
+	order   *gen.Order
+	missing map[string]int32
+	since   time.Time
+}
+
+func newReservations() *reservations {
+	return &reservations{
+		reserved: make(map[string]map[string]int32),
+		mu:       sync.Mutex{},
+	}
+}
+
+// available returns the stock that is not reserved. Must be called with
+// r.mu held.
+func (r *reservations) available(stock map[string]int32) map[string]int32 {
+	available := map[string]int32{}
+	for code, quantity := range stock {
+		available[code] = quantity
+	}
+	for _, items := range r.reserved {
+		for code, quantity := range items {
+			available[code] -= quantity
+		}
+	}
+	return available
+}
+
+// reserve sets the items of order aside if enough of them are available and
+// otherwise returns the items it misses. Must be called with r.mu held.
+func (r *reservations) reserve(order *gen.Order, available map[string]int32) map[string]int32 {
+	needed := inventory.Count(order.Items)
+
+	missing := map[string]int32{}
+	for code, quantity := range needed {
+		if available[code] < quantity {
+			missing[code] = quantity - available[code]
+		}
+	}
+	if len(missing) > 0 {
+		return missing
+	}
+
+	for code, quantity := range needed {
+		available[code] -= quantity
+	}
+	if _, ok := r.reserved[order.Id]; !ok {
+		r.orderIds = append(r.orderIds, order.Id)
+	}
+	r.reserved[order.Id] = needed
+	return nil
+}
+
+// consume records that an item of an order was sorted and no longer needs
+// setting aside.
+func (r *reservations) consume(orderId, itemCode string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, ok := r.reserved[orderId]
+	if !ok || items[itemCode] == 0 {
+		return
+	}
+
+	items[itemCode]--
+	if items[itemCode] == 0 {
+		delete(items, itemCode)
+	}
+}
+
+// release drops the reservations of orders that are no longer sorted.
+func (r *reservations) release(orderIds ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	released := map[string]bool{}
+	for _, orderId := range orderIds {
+		released[orderId] = true
+		delete(r.reserved, orderId)
+	}
+
+	remaining := []string{}
+	for _, orderId := range r.orderIds {
+		if !released[orderId] {
+			remaining = append(remaining, orderId)
+		}
+	}
+	r.orderIds = remaining
+}
+
+// backordered returns the orders waiting for stock, oldest first.
+func (r *reservations) backordered() []*gen.Order {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orders := []*gen.Order{}
+	for _, backorder := range r.backorders {
+		orders = append(orders, backorder.order)
+	}
+	return orders
+}
+
+// backorderStatuses reports the orders waiting for stock, which have no
+// cubby yet.
+func (r *reservations) backorderStatuses() []*gen.FulfillmentStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := []*gen.FulfillmentStatus{}
+	for _, backorder := range r.backorders {
+		statuses = append(statuses, &gen.FulfillmentStatus{Order: backorder.order, Status: gen.OrderStatus_BACKORDERED})
+	}
+	return statuses
+}
+
+// admitBackorders reserves available stock for the orders waiting for it,
+// oldest first, and returns the ones that got their items. Must be called
+// with r.mu held.
+func (r *reservations) admitBackorders(available map[string]int32) []*backorder {
+	admitted := []*backorder{}
+	waiting := []*backorder{}
+	for _, backorder := range r.backorders {
+		if missing := r.reserve(backorder.order, available); missing != nil {
+			backorder.missing = missing
+			waiting = append(waiting, backorder)
+			continue
+		}
+		log.Println("Backordered order ", backorder.order.Id, " is in stock and was admitted after ", time.Since(backorder.since).Round(time.Second))
+		admitted = append(admitted, backorder)
+	}
+	r.backorders = waiting
+	return admitted
+}
+
+// putBack makes admitted backorders that could not be sorted wait for stock
+// again, where they waited before.
+func (r *reservations) putBack(backorders []*backorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.backorders = append(append([]*backorder{}, backorders...), r.backorders...)
+	sort.SliceStable(r.backorders, func(i, j int) bool {
+		return r.backorders[i].since.Before(r.backorders[j].since)
+	})
+}
+
+func (r *reservations) hasBackorders() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.backorders) > 0
+}
+
+// stock adds up the inventories of the robots of the pool. A robot that
+// cannot report its inventory is left out, as its items cannot be counted on.
+func (fs *fulfillmentService) stock(ctx context.Context) (map[string]int32, error) {
+	if len(fs.pool.robots) == 0 {
+		return nil, errNoInventory
+	}
+
+	stock := map[string]int32{}
+	reported := 0
+	for _, r := range fs.pool.robots {
+		levels, err := r.robot.Inventory(ctx)
+		if err != nil {
+			log.Println("Error while listing the inventory of robot ", r.name, " occured: ", err.Error())
+			continue
+		}
+
+		reported++
+		for _, level := range levels {
+			stock[level.Code] += level.Quantity
+		}
+	}
+	if reported == 0 {
+		return nil, status.Error(codes.Unavailable, "no robot of the pool reported its inventory")
+	}
+	return stock, nil
+}
+
+// admitOrders reserves stock for orders and returns the ones to sort. Under
+// RejectShort the orders that cannot get their items are returned as
+// rejected, under BackorderShort they wait for stock and are admitted ahead
+// of the loaded orders once the robots hold their items.
+func (fs *fulfillmentService) admitOrders(ctx context.Context, orders []*gen.Order) (admitted, rejected, backordered []*gen.Order, err error) {
+	if fs.admission == AdmitAll {
+		return orders, nil, nil, nil
+	}
+
+	stock, err := fs.stock(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fs.reservations.mu.Lock()
+	defer fs.reservations.mu.Unlock()
+
+	available := fs.reservations.available(stock)
+	for _, backorder := range fs.reservations.admitBackorders(available) {
+		admitted = append(admitted, backorder.order)
+	}
+
+	waiting := fs.reservations.backorders
+	for _, order := range orders {
+		missing := fs.reservations.reserve(order, available)
+		switch {
+		case missing == nil:
+			admitted = append(admitted, order)
+		case fs.admission == BackorderShort:
+			waiting = append(waiting, &backorder{order: order, missing: missing, since: time.Now()})
+			backordered = append(backordered, order)
+		default:
+			rejected = append(rejected, order)
+		}
+	}
+	fs.reservations.backorders = waiting
+	return admitted, rejected, backordered, nil
+}
+
+// GetReservations lists the stock reserved for each admitted order that is
+// still sorted, the orders waiting for stock and the stock left over.
+func (fs *fulfillmentService) GetReservations(ctx context.Context, in *gen.Empty) (*gen.ReservationsResponse, error) {
+	stock := map[string]int32{}
+	if len(fs.pool.robots) > 0 {
+		var err error
+		if stock, err = fs.stock(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	fs.reservations.mu.Lock()
+	defer fs.reservations.mu.Unlock()
+
+	resp := &gen.ReservationsResponse{
+		Reservations: []*gen.Reservation{},
+		Backorders:   []*gen.Backorder{},
+		Available:    inventory.Levels(fs.reservations.available(stock)),
+	}
+	for _, orderId := range fs.reservations.orderIds {
+		resp.Reservations = append(resp.Reservations, &gen.Reservation{OrderId: orderId, Items: inventory.Levels(fs.reservations.reserved[orderId])})
+	}
+	now := time.Now()
+	for _, backorder := range fs.reservations.backorders {
+		resp.Backorders = append(resp.Backorders, &gen.Backorder{
+			OrderId:     backorder.order.Id,
+			Missing:     inventory.Levels(backorder.missing),
+			SinceMillis: backorder.since.UnixNano() / int64(time.Millisecond),
+			AgeMillis:   now.Sub(backorder.since).Milliseconds(),
+		})
+	}
+	return resp, nil
+}
+
+// resumeBackorders admits the orders waiting for stock as soon as the robots
+// hold their items, checking the robots' inventory every
+// backorderPollInterval, until the service shuts down. Items loaded after
+// their orders are thus sorted without loading the orders again.
+func (fs *fulfillmentService) resumeBackorders(ctx context.Context) {
+	for {
+		timer := time.NewTimer(backorderPollInterval)
+		select {
+		case <-timer.C:
+		case <-fs.stopping:
+			timer.Stop()
+			return
+		}
+
+		if err := fs.admitBackorders(ctx); err != nil {
+			log.Println("Error while resuming backorders occured: ", err.Error())
+		}
+	}
+}
+
+// admitBackorders sorts the orders waiting for stock that the robots now
+// hold the items of. If they cannot be sorted, as the service is shutting
+// down, they wait for stock again so that Shutdown reports them.
+func (fs *fulfillmentService) admitBackorders(ctx context.Context) error {
+	if !fs.reservations.hasBackorders() {
+		return nil
+	}
+
+	stock, err := fs.stock(ctx)
+	if err != nil {
+		return err
+	}
+
+	fs.reservations.mu.Lock()
+	admitted := fs.reservations.admitBackorders(fs.reservations.available(stock))
+	fs.reservations.mu.Unlock()
+	if len(admitted) == 0 {
+		return nil
+	}
+
+	orders := []*gen.Order{}
+	for _, backorder := range admitted {
+		orders = append(orders, backorder.order)
+	}
+	if err := fs.enqueue(orders); err != nil {
+		fs.reservations.putBack(admitted)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultOrderRetention is how long the status of an order is kept after its
+// cubby is freed unless the parameters say otherwise.
+const defaultOrderRetention = time.Hour
+
+// loadedOrders keeps the ids of the orders loaded, from when they are loaded
+// until their status is no longer kept, so that no id is loaded twice.
+type loadedOrders struct {
+	orderIds map[string]bool
+	mu       sync.Mutex
+}
+
+func newLoadedOrders() *loadedOrders {
+	return &loadedOrders{
+		orderIds: make(map[string]bool),
+		mu:       sync.Mutex{},
+	}
+}
+
+// add records the ids of orders, unless one of them is loaded already or
+// twice among them, in which case none is.
+func (l *loadedOrders) add(orders []*gen.Order) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen := map[string]bool{}
+	for _, order := range orders {
+		if seen[order.Id] {
+			return status.Errorf(codes.InvalidArgument, "order %s is loaded twice", order.Id)
+		}
+		if l.orderIds[order.Id] {
+			return status.Errorf(codes.AlreadyExists, "order %s is already loaded", order.Id)
+		}
+		seen[order.Id] = true
+	}
+
+	for orderId := range seen {
+		l.orderIds[orderId] = true
+	}
+	return nil
+}
+
+// remove lets the orders be loaded again.
+func (l *loadedOrders) remove(orderIds ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, orderId := range orderIds {
+		delete(l.orderIds, orderId)
+	}
+}
+
+// evictOrders forgets the orders whose cubby was freed more than
+// orderRetention before now, so their ids can be loaded again.
+func (fs *fulfillmentService) evictOrders(now time.Time) {
+	fs.loaded.remove(fs.state.EvictOrders(now.Add(-fs.orderRetention))...)
+}
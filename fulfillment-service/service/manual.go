@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// manualStation is a robot of the pool that operators pick for by hand. They
+// scan each item they pick, which is the robot's next pick, and confirm
+// putting it into its cubby.
+type manualStation interface {
+	Scan(ctx context.Context, itemCode string) (*gen.Cubby, error)
+	ConfirmPut(ctx context.Context, itemCode, cubbyId string) error
+}
+
+// manualStation returns the robot of the pool operators pick for, if any.
+func (fs *fulfillmentService) manualStation() (manualStation, bool) {
+	for _, r := range fs.pool.robots {
+		if station, ok := r.robot.(manualStation); ok {
+			return station, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// partialCheckInterval is how often orders are checked for having run past
+// their partial timeout.
+const partialCheckInterval = time.Second
+
+// PartialPolicies names the policies for orders still missing items at their
+// partial timeout.
+var PartialPolicies = map[string]gen.PartialPolicy{
+	"release": gen.PartialPolicy_RELEASE_PARTIAL,
+	"hold":    gen.PartialPolicy_HOLD_PARTIAL,
+	"cancel":  gen.PartialPolicy_CANCEL_PARTIAL,
+}
+
+// ParsePartialPolicy returns the policy of one of the names of
+// PartialPolicies.
+func ParsePartialPolicy(name string) (gen.PartialPolicy, error) {
+	policy, ok := PartialPolicies[name]
+	if !ok {
+		return gen.PartialPolicy_SYSTEM_POLICY, fmt.Errorf("unknown partial policy %q, must be one of release, hold or cancel", name)
+	}
+	return policy, nil
+}
+
+// enforcePartialTimeouts decides for the orders that run past their partial
+// timeout until the service shuts down.
+func (fs *fulfillmentService) enforcePartialTimeouts() {
+	for {
+		timer := time.NewTimer(partialCheckInterval)
+		select {
+		case <-timer.C:
+		case <-fs.stopping:
+			timer.Stop()
+			return
+		}
+
+		fs.timeOutPartialOrders(time.Now())
+	}
+}
+
+// timeOutPartialOrders applies their partial policy, or the service's, to
+// the orders that are still missing items after their partial timeout, or
+// the service's, as of now. Orders without a timeout wait for their items.
+func (fs *fulfillmentService) timeOutPartialOrders(now time.Time) {
+	orderDataSlice, err := fs.state.GetAllOrdersData()
+	if err != nil {
+		log.Println("Error while checking partial timeouts occured: ", err.Error())
+		return
+	}
+
+	for _, orderData := range orderDataSlice {
+		if orderData.Status != gen.OrderStatus_PENDING {
+			continue
+		}
+
+		timeout := fs.orderPartialTimeout(orderData.PartialTimeoutMillis)
+		if timeout <= 0 || now.Sub(orderData.AddedAt) < timeout {
+			continue
+		}
+
+		fs.decidePartial(orderData.Id, fs.orderPartialPolicy(orderData.PartialPolicy))
+	}
+}
+
+// orderPartialTimeout returns the partial timeout of an order that sets
+// timeoutMillis, or the service's if it sets none.
+func (fs *fulfillmentService) orderPartialTimeout(timeoutMillis int64) time.Duration {
+	if timeoutMillis > 0 {
+		return time.Duration(timeoutMillis) * time.Millisecond
+	}
+	return fs.partialTimeout
+}
+
+// orderPartialPolicy returns the partial policy of an order that sets
+// policy, or the service's if it sets none.
+func (fs *fulfillmentService) orderPartialPolicy(policy gen.PartialPolicy) gen.PartialPolicy {
+	if policy == gen.PartialPolicy_SYSTEM_POLICY {
+		return fs.partialPolicy
+	}
+	return policy
+}
+
+// checkPartialPolicies fails for orders that would be held at their partial
+// timeout while sorting one batch at a time. A batch frees every cubby for
+// the next one once it is done, so an order cannot be held past its batch.
+func (fs *fulfillmentService) checkPartialPolicies(orders []*gen.Order) error {
+	if fs.flow != nil {
+		return nil
+	}
+
+	for _, order := range orders {
+		if fs.orderPartialTimeout(order.PartialTimeoutMillis) > 0 && fs.orderPartialPolicy(order.PartialPolicy) == gen.PartialPolicy_HOLD_PARTIAL {
+			return status.Errorf(codes.InvalidArgument, "order %s would be held at its partial timeout, which only continuous sorting can do; release or cancel it instead", order.Id)
+		}
+	}
+	return nil
+}
+
+// decidePartial applies policy to an order still missing items. A released
+// order stops waiting for them and is picked up as it is, a cancelled one
+// also gives up its cubby, and a held one goes on waiting.
+func (fs *fulfillmentService) decidePartial(orderId string, policy gen.PartialPolicy) {
+	missing, err := fs.state.DecidePartial(orderId, policy)
+	if err != nil {
+		log.Println("Error while deciding for partial order ", orderId, " occured: ", err.Error())
+		return
+	}
+
+	log.Printf("Order %s timed out missing %d items, decided to %s.", orderId, len(missing), policy)
+	if policy == gen.PartialPolicy_HOLD_PARTIAL {
+		return
+	}
+
+	fs.reservations.release(orderId)
+	if fs.putWall != nil {
+		fs.putWall.drop(len(missing))
+	}
+	if fs.flow != nil && policy == gen.PartialPolicy_CANCEL_PARTIAL {
+		if err := fs.releaseOrder(orderId); err != nil {
+			log.Println("Error while freeing the cubby of cancelled order ", orderId, " occured: ", err.Error())
+		}
+		return
+	}
+
+	fs.dropPicks(orderId)
+	if policy == gen.PartialPolicy_CANCEL_PARTIAL {
+		if err := fs.state.ReleaseCubby(orderId); err != nil {
+			log.Println("Error while freeing the cubby of cancelled order ", orderId, " occured: ", err.Error())
+		}
+	}
+}
+
+// dropPicks stops the robots from picking for an order that no longer waits
+// for its items.
+func (fs *fulfillmentService) dropPicks(orderId string) {
+	if fs.flow != nil {
+		fs.flow.mu.Lock()
+		defer fs.flow.mu.Unlock()
+
+		for _, picks := range fs.flow.picks {
+			picks.removeOrder(orderId)
+		}
+		return
+	}
+
+	fs.processingMu.Lock()
+	defer fs.processingMu.Unlock()
+
+	for _, picks := range fs.batchPicks {
+		picks.removeOrder(orderId)
+	}
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/robot"
+	"github.com/Emoto13/sort-system/fulfillment-service/state"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errNoRobots = status.Error(codes.Unavailable, "no robot in the pool can sort items")
+
+// poolRobot is a robot of the pool. A robot that failed sits out until it
+// reports that it is no longer faulted.
+type poolRobot struct {
+	name   string
+	robot  robot.Robot
+	health RobotHealth
+	wallId string
+	failed bool
+}
+
+// robotPool holds the robots that sort batches in parallel, each from its
+// own input bin.
+type robotPool struct {
+	robots []*poolRobot
+	mu     sync.Mutex
+}
+
+func newRobotPool(robots []PooledRobot) *robotPool {
+	pool := &robotPool{mu: sync.Mutex{}}
+	for _, r := range robots {
+		pool.robots = append(pool.robots, &poolRobot{name: r.Name, robot: r.Robot, health: r.Health, wallId: r.WallId, failed: r.Failed})
+	}
+	return pool
+}
+
+// available returns the robots that can sort the next batch, letting failed
+// robots rejoin once they report that they are no longer faulted. The failed
+// robots are asked for their status without holding the pool's lock, so that
+// a robot slow to answer does not hold up the others.
+func (p *robotPool) available(ctx context.Context) []*poolRobot {
+	p.mu.Lock()
+	failed := []*poolRobot{}
+	for _, r := range p.robots {
+		if r.failed {
+			failed = append(failed, r)
+		}
+	}
+	p.mu.Unlock()
+
+	recovered := map[*poolRobot]bool{}
+	for _, r := range failed {
+		robotStatus, err := r.robot.Status(ctx)
+		if err == nil && robotStatus.State != gen.RobotState_FAULTED {
+			recovered[r] = true
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	robots := []*poolRobot{}
+	for _, r := range p.robots {
+		if r.failed && recovered[r] {
+			log.Println("Robot ", r.name, " recovered and is back in the pool.")
+			r.failed = false
+		}
+		if !r.failed {
+			robots = append(robots, r)
+		}
+	}
+	return robots
+}
+
+func (p *robotPool) isFailed(r *poolRobot) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return r.failed
+}
+
+// fail takes r out of the pool.
+func (p *robotPool) fail(r *poolRobot, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !r.failed {
+		log.Println("Robot ", r.name, " failed and was taken out of the pool: ", err.Error())
+		r.failed = true
+	}
+}
+
+// batchPicks hands out the picks of a batch to the robots sorting it. Every
+// pick is attributed to an order, which fails if the robot picks an item no
+// order is waiting for. A robot that cannot make a pick gives it back for
+// another robot to make. When sorting continuously, the picks stay open for
+// the orders still to come until they are closed.
+type batchPicks struct {
+	orderIds []string
+	// claimed counts the picks that were handed out and are not done yet.
+	claimed int
+	open    bool
+	cond    *sync.Cond
+}
+
+func newBatchPicks(orders []*gen.Order) *batchPicks {
+	orderIds := []string{}
+	for _, order := range orders {
+		for range order.Items {
+			orderIds = append(orderIds, order.Id)
+		}
+	}
+	return &batchPicks{orderIds: orderIds, cond: sync.NewCond(&sync.Mutex{})}
+}
+
+// picksByWall splits the picks of a batch among the walls the robots serve.
+// The robots that serve no wall in particular pick for the walls no robot of
+// their own serves. It also returns the picks no robot can make.
+func (fs *fulfillmentService) picksByWall(orders []*gen.Order, robots []*poolRobot) (map[string]*batchPicks, int) {
+	served := map[string]bool{}
+	for _, r := range robots {
+		served[r.wallId] = true
+	}
+
+	ordersByWall := map[string][]*gen.Order{}
+	unserved := 0
+	for _, order := range orders {
+		wallId := ""
+		if orderData, err := fs.state.GetOrderDataById(order.Id); err == nil {
+			wallId = orderData.Cubby.WallId
+		}
+		if !served[wallId] {
+			if !served[""] {
+				unserved += len(order.Items)
+				continue
+			}
+			wallId = ""
+		}
+		ordersByWall[wallId] = append(ordersByWall[wallId], order)
+	}
+
+	picks := map[string]*batchPicks{}
+	for wallId := range served {
+		picks[wallId] = newBatchPicks(ordersByWall[wallId])
+	}
+	return picks, unserved
+}
+
+// openPicks returns picks that orders are added to as they come.
+func openPicks() *batchPicks {
+	return &batchPicks{open: true, cond: sync.NewCond(&sync.Mutex{})}
+}
+
+// add adds the picks of order.
+func (b *batchPicks) add(order *gen.Order) {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	for range order.Items {
+		b.orderIds = append(b.orderIds, order.Id)
+	}
+	b.cond.Broadcast()
+}
+
+// removeOrder drops the picks of an order that no longer waits for items.
+func (b *batchPicks) removeOrder(orderId string) {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	orderIds := []string{}
+	for _, id := range b.orderIds {
+		if id != orderId {
+			orderIds = append(orderIds, id)
+		}
+	}
+	b.orderIds = orderIds
+	b.cond.Broadcast()
+}
+
+// close stops handing out picks.
+func (b *batchPicks) close() {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	b.open = false
+	b.orderIds = nil
+	b.cond.Broadcast()
+}
+
+// claim hands out the next pick. While every pick is handed out, it waits
+// for picks to be given back or added, and it reports false once none can be.
+func (b *batchPicks) claim() (string, bool) {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	for len(b.orderIds) == 0 && (b.claimed > 0 || b.open) {
+		b.cond.Wait()
+	}
+	if len(b.orderIds) == 0 {
+		return "", false
+	}
+
+	orderId := b.orderIds[0]
+	b.orderIds = b.orderIds[1:]
+	b.claimed++
+	return orderId, true
+}
+
+// done records that a claimed pick was made.
+func (b *batchPicks) done() {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	b.claimed--
+	b.cond.Broadcast()
+}
+
+// giveBack returns a claimed pick that was not made.
+func (b *batchPicks) giveBack(orderId string) {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	b.orderIds = append([]string{orderId}, b.orderIds...)
+	b.claimed--
+	b.cond.Broadcast()
+}
+
+func (b *batchPicks) remaining() int {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	return len(b.orderIds) + b.claimed
+}
+
+// sortBatch has every available robot sort the batch until all its picks are
+// made or no robot can make the rest.
+func (fs *fulfillmentService) sortBatch(ctx context.Context, orders []*gen.Order) error {
+	robots := fs.pool.available(ctx)
+	if len(robots) == 0 {
+		return errNoRobots
+	}
+
+	picks, unserved := fs.picksByWall(orders, robots)
+	fs.setBatchPicks(picks)
+	defer fs.setBatchPicks(nil)
+
+	errs := make(chan error, len(robots))
+	for _, r := range robots {
+		go func(r *poolRobot) {
+			errs <- fs.sortWith(ctx, r, picks[r.wallId])
+		}(r)
+	}
+
+	var err error
+	for range robots {
+		if robotErr := <-errs; robotErr != nil && (err == nil || robotErr == errShuttingDown) {
+			err = robotErr
+		}
+	}
+
+	remaining := unserved
+	for _, wallPicks := range picks {
+		remaining += wallPicks.remaining()
+	}
+	if remaining > 0 {
+		if err == nil {
+			err = errNoRobots
+		}
+		return err
+	}
+	return nil
+}
+
+func (fs *fulfillmentService) setBatchPicks(picks map[string]*batchPicks) {
+	fs.processingMu.Lock()
+	defer fs.processingMu.Unlock()
+
+	fs.batchPicks = picks
+}
+
+// matchItem finds the cubby of an order waiting for the item r picked, on
+// r's wall if r serves one.
+func (fs *fulfillmentService) matchItem(r *poolRobot, itemCode string) (*state.OrderCubby, error) {
+	if r.wallId == "" {
+		return fs.state.GetOrderCubbyByItemCode(itemCode)
+	}
+	return fs.state.GetOrderCubbyByItemCodeOnWall(itemCode, r.wallId)
+}
+
+// sortWith makes picks with r until there are none left or r cannot go on.
+// An item r holds when it fails is returned to its input bin, and its order
+// keeps waiting for it.
+func (fs *fulfillmentService) sortWith(ctx context.Context, r *poolRobot, picks *batchPicks) error {
+	for {
+		orderId, ok := picks.claim()
+		if !ok {
+			return nil
+		}
+
+		if err := fs.waitForRobotHealth(ctx, r); err != nil {
+			picks.giveBack(orderId)
+			return err
+		}
+
+		resp, err := fs.selectItem(ctx, r)
+		if err != nil {
+			picks.giveBack(orderId)
+			if fs.isStopping() {
+				return errShuttingDown
+			}
+			if status.Code(err) == codes.Unavailable && ctx.Err() == nil {
+				fs.pool.fail(r, err)
+			}
+			return err
+		}
+
+		orderCubby, err := fs.matchItem(r, resp.Item.Code)
+		if err != nil {
+			log.Println(err)
+			fs.state.FailPendingOrder(orderId)
+			fs.releaseFailedOrder(orderId)
+			fs.returnItem(r, resp)
+			picks.done()
+			continue
+		}
+
+		err = fs.moveItem(ctx, r, orderCubby.Cubby, resp.PickToken)
+		if err != nil {
+			if status.Code(err) == codes.DataLoss {
+				fs.state.AddItemStatusForOrder(orderCubby.Order.Id, state.Failed)
+				fs.releaseFailedOrder(orderCubby.Order.Id)
+				log.Println("Item with code ", resp.Item.Code, " was lost: ", err.Error())
+				picks.done()
+				continue
+			}
+
+			fs.returnItem(r, resp)
+			fs.state.ReturnOrderCubby(resp.Item.Code, orderCubby)
+			picks.giveBack(orderId)
+			if fs.isStopping() {
+				return errShuttingDown
+			}
+			if ctx.Err() == nil {
+				fs.pool.fail(r, err)
+			}
+			return err
+		}
+
+		fs.state.AddItemStatusForOrder(orderCubby.Order.Id, state.Ready)
+		fs.reservations.consume(orderCubby.Order.Id, resp.Item.Code)
+		picks.done()
+		fmt.Println("Item with code ", resp.Item.Code, " is moved to: ", orderCubby.Cubby.Id, " by robot ", r.name)
+	}
+}
+
+// releaseFailedOrder drops the reservations of an order that failed, as the
+// rest of its items are no longer set aside for it.
+func (fs *fulfillmentService) releaseFailedOrder(orderId string) {
+	orderData, err := fs.state.GetOrderDataById(orderId)
+	if err == nil && orderData.Status == gen.OrderStatus_FAILED {
+		fs.reservations.release(orderId)
+	}
+}
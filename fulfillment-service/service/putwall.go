@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/state"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errNotByHand = status.Error(codes.FailedPrecondition, "items are sorted by a robot, not by hand")
+
+// putWall keeps track of the batch in progress while operators sort it by
+// hand. Any number of operators scan items at the same time; each scanned
+// item waits for its put to be confirmed, and counts as lost if that takes
+// longer than putTimeout.
+type putWall struct {
+	state      state.State
+	putTimeout time.Duration
+	pending    []*pendingPut
+	// unsorted counts the items of the batch in progress that were neither
+	// put into their cubbies nor lost yet.
+	unsorted int
+	// changed is signalled whenever an item is put.
+	changed chan struct{}
+	mu      sync.Mutex
+}
+
+// pendingPut is an item that was scanned but not yet put into its cubby.
+type pendingPut struct {
+	itemCode   string
+	orderCubby *state.OrderCubby
+	deadline   time.Time
+}
+
+func newPutWall(state state.State, putTimeout time.Duration) *putWall {
+	return &putWall{
+		state:      state,
+		putTimeout: putTimeout,
+		changed:    make(chan struct{}, 1),
+		mu:         sync.Mutex{},
+	}
+}
+
+// start waits for the given number of items to be sorted.
+func (pw *putWall) start(items int) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	pw.pending = nil
+	pw.unsorted = items
+}
+
+// add waits for more items to be sorted.
+func (pw *putWall) add(items int) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	pw.unsorted += items
+}
+
+// drop stops waiting for items that will not be sorted.
+func (pw *putWall) drop(items int) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	pw.unsorted -= items
+	if pw.unsorted < 0 {
+		pw.unsorted = 0
+	}
+	select {
+	case pw.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (pw *putWall) remaining() int {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	return pw.unsorted
+}
+
+// scan matches a scanned item to the cubby of an order waiting for it, the
+// way items the robot picks are matched.
+func (pw *putWall) scan(itemCode string) (*gen.Cubby, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	if pw.unsorted == 0 {
+		return nil, status.Errorf(codes.NotFound, "no orders are waiting for items, put item %s aside", itemCode)
+	}
+
+	orderCubby, err := pw.state.GetOrderCubbyByItemCode(itemCode)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "item %s belongs to no order waiting for items, put it aside", itemCode)
+	}
+
+	pw.pending = append(pw.pending, &pendingPut{itemCode: itemCode, orderCubby: orderCubby, deadline: time.Now().Add(pw.putTimeout)})
+	return orderCubby.Cubby, nil
+}
+
+// confirm records that a scanned item was put into cubbyId.
+func (pw *putWall) confirm(itemCode, cubbyId string) error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	expected := ""
+	for i, put := range pw.pending {
+		if put.itemCode != itemCode {
+			continue
+		}
+		if put.orderCubby.Cubby.Id != cubbyId {
+			expected = put.orderCubby.Cubby.Id
+			continue
+		}
+
+		pw.pending = append(pw.pending[:i], pw.pending[i+1:]...)
+		pw.unsorted--
+		pw.state.AddItemStatusForOrder(put.orderCubby.Order.Id, state.Ready)
+		select {
+		case pw.changed <- struct{}{}:
+		default:
+		}
+		return nil
+	}
+
+	if expected != "" {
+		return status.Errorf(codes.InvalidArgument, "item %s belongs in cubby %s, not %s", itemCode, expected, cubbyId)
+	}
+	return status.Errorf(codes.FailedPrecondition, "item %s is not waiting to be put into a cubby", itemCode)
+}
+
+// expire fails the orders of the items that were not put in time and returns
+// how long until the next pending put is due.
+func (pw *putWall) expire(now time.Time) time.Duration {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	next := pw.putTimeout
+	pending := []*pendingPut{}
+	for _, put := range pw.pending {
+		if now.Before(put.deadline) {
+			pending = append(pending, put)
+			if put.deadline.Sub(now) < next {
+				next = put.deadline.Sub(now)
+			}
+			continue
+		}
+
+		log.Println("Item with code ", put.itemCode, " was not put into cubby ", put.orderCubby.Cubby.Id, " in time and is lost.")
+		pw.unsorted--
+		pw.state.AddItemStatusForOrder(put.orderCubby.Order.Id, state.Failed)
+	}
+	pw.pending = pending
+	return next
+}
+
+// waitForPuts waits until operators have put every item of the batch into
+// its cubby.
+func (fs *fulfillmentService) waitForPuts(ctx context.Context, orders []*gen.Order) error {
+	items := 0
+	for _, order := range orders {
+		items += len(order.Items)
+	}
+	fs.putWall.start(items)
+	defer fs.putWall.start(0)
+
+	for fs.putWall.remaining() > 0 {
+		timer := time.NewTimer(fs.putWall.expire(time.Now()))
+		select {
+		case <-fs.putWall.changed:
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-fs.stopping:
+			timer.Stop()
+			return errShuttingDown
+		}
+		timer.Stop()
+	}
+	return nil
+}
+
+// ScanItem tells an operator at the put wall, or picking for the robot by
+// hand, which cubby a scanned item goes into. Items that belong to no order
+// waiting for them are NotFound and put aside.
+func (fs *fulfillmentService) ScanItem(ctx context.Context, in *gen.ScanItemRequest) (*gen.ScanItemResponse, error) {
+	var cubby *gen.Cubby
+	var err error
+	if station, ok := fs.manualStation(); ok {
+		cubby, err = station.Scan(ctx, in.ItemCode)
+	} else if fs.putWall != nil {
+		cubby, err = fs.putWall.scan(in.ItemCode)
+	} else {
+		return nil, errNotByHand
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gen.ScanItemResponse{Cubby: cubby}, nil
+}
+
+// ConfirmPut records that an operator put a scanned item into its cubby.
+func (fs *fulfillmentService) ConfirmPut(ctx context.Context, in *gen.ConfirmPutRequest) (*gen.Empty, error) {
+	var err error
+	if station, ok := fs.manualStation(); ok {
+		err = station.ConfirmPut(ctx, in.ItemCode, in.CubbyId)
+	} else if fs.putWall != nil {
+		err = fs.putWall.confirm(in.ItemCode, in.CubbyId)
+	} else {
+		return nil, errNotByHand
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gen.Empty{}, nil
+}
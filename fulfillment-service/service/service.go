@@ -5,81 +5,272 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/Emoto13/sort-system/fulfillment-service/state"
 	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// maxScanAttempts bounds how many times an item is selected again after the
+// robot fails to scan it.
+const maxScanAttempts = 3
+
+// robotStatusPollInterval is how often a paused batch checks whether the
+// robot has been resumed.
+const robotStatusPollInterval = time.Second
+
 type FulfillmentService interface {
 	gen.FulfillmentServer
 	ProcessOrders(ctx context.Context) error
+	Shutdown(ctx context.Context) []*gen.Order
 }
 
 type fulfillmentService struct {
-	sortingRobot     gen.SortingRobotClient
+	pool             *robotPool
+	putWall          *putWall
 	state            state.State
 	orders           chan []*gen.Order
 	processingOrders bool
-	mu               sync.Mutex
+	// batchPicks are the picks of the batch in progress, by wall.
+	batchPicks map[string]*batchPicks
+	// processingMu guards processingOrders and batchPicks, which LoadOrders
+	// and the partial timeouts read while mu is held for the whole batch.
+	processingMu sync.Mutex
+	mu           sync.Mutex
+
+	queue    *batchQueue
+	stopping chan struct{}
+	stopOnce sync.Once
+	abort    chan struct{}
+	done     chan struct{}
+	// interrupted holds the orders of the batch that was being processed
+	// when the service shut down and that did not get all their items.
+	interrupted []*gen.Order
+	// flow is set when orders are sorted continuously rather than one batch
+	// at a time.
+	flow *flow
+	// atRiskMargin is how close to its deadline an order counts as at risk.
+	atRiskMargin time.Duration
+	admission    Admission
+	reservations *reservations
+	// partialTimeout is how long an order may miss items before
+	// partialPolicy is applied to it, unless the order has its own.
+	partialTimeout time.Duration
+	partialPolicy  gen.PartialPolicy
+	loaded         *loadedOrders
+	// orderRetention is how long the status of an order is kept after its
+	// cubby is freed.
+	orderRetention time.Duration
 }
 
 func New(params *FulfillmentServiceParameters) FulfillmentService {
-	return &fulfillmentService{
-		sortingRobot:     params.SortingRobot,
+	fs := &fulfillmentService{
+		pool:             newRobotPool(params.robots()),
 		state:            params.State,
 		orders:           params.Orders,
 		processingOrders: false,
 		mu:               sync.Mutex{},
+		queue:            newBatchQueue(),
+		stopping:         make(chan struct{}),
+		abort:            make(chan struct{}),
+		done:             make(chan struct{}),
+		atRiskMargin:     params.AtRiskMargin,
+		admission:        params.Admission,
+		reservations:     newReservations(),
+		partialTimeout:   params.PartialTimeout,
+		partialPolicy:    params.PartialPolicy,
+		loaded:           newLoadedOrders(),
+		orderRetention:   params.OrderRetention,
+	}
+	if fs.partialPolicy == gen.PartialPolicy_SYSTEM_POLICY {
+		fs.partialPolicy = gen.PartialPolicy_HOLD_PARTIAL
+	}
+	if fs.admission == "" {
+		fs.admission = AdmitAll
+	}
+	if fs.atRiskMargin <= 0 {
+		fs.atRiskMargin = defaultAtRiskMargin
+	}
+	if fs.orderRetention <= 0 {
+		fs.orderRetention = defaultOrderRetention
+	}
+	if len(fs.pool.robots) == 0 {
+		fs.putWall = newPutWall(params.State, params.PutTimeout)
+	}
+	if params.Continuous {
+		fs.flow = newFlow()
 	}
+	return fs
 }
 
 func (fs *fulfillmentService) areOrdersBeingProcessed() bool {
+	fs.processingMu.Lock()
+	defer fs.processingMu.Unlock()
+
 	return fs.processingOrders
 }
 
 func (fs *fulfillmentService) setAreOrdersBeingProcessed(value bool) {
+	fs.processingMu.Lock()
+	defer fs.processingMu.Unlock()
+
 	fs.processingOrders = value
 }
 
 func (fs *fulfillmentService) LoadOrders(ctx context.Context, in *gen.LoadOrdersRequest) (*gen.CompleteResponse, error) {
+	if err := fs.checkPartialPolicies(in.Orders); err != nil {
+		return nil, err
+	}
+
+	if fs.isStopping() {
+		return nil, errNotAcceptingOrders
+	}
+
+	fs.evictOrders(time.Now())
+	if err := fs.loaded.add(in.Orders); err != nil {
+		return nil, err
+	}
+
+	orders, rejected, backordered, err := fs.admitOrders(ctx, in.Orders)
+	if err != nil {
+		fs.loaded.remove(orderIds(in.Orders)...)
+		return nil, err
+	}
+	fs.loaded.remove(orderIds(rejected)...)
+
+	resp := &gen.CompleteResponse{Orders: []*gen.PreparedOrder{}, RejectedOrderIds: orderIds(rejected), BackorderedOrderIds: orderIds(backordered)}
+	if len(orders) == 0 && len(rejected)+len(backordered) > 0 {
+		resp.Status = "No order can be sorted from the robots' inventory yet"
+		return resp, nil
+	}
+
+	if err := fs.enqueue(orders); err != nil {
+		fs.loaded.remove(orderIds(orders)...)
+		return nil, err
+	}
+
+	switch {
+	case fs.flow != nil:
+		resp.Status = "The orders join the orders being sorted"
+	case fs.areOrdersBeingProcessed():
+		resp.Status = "Will start to process the request shortly"
+	default:
+		resp.Status = "The request will be handled immediately"
+	}
+	return resp, nil
+}
+
+// enqueue hands orders over to be sorted. Their reservations are dropped if
+// the service is shutting down.
+func (fs *fulfillmentService) enqueue(orders []*gen.Order) error {
+	batchId, err := fs.queue.add(orders)
+	if err != nil {
+		fs.reservations.release(orderIds(orders)...)
+		return err
+	}
+
 	go func() {
-		fs.orders <- in.Orders
+		select {
+		case fs.orders <- orders:
+			fs.queue.remove(batchId)
+		case <-fs.stopping:
+		}
 	}()
+	return nil
+}
 
-	if fs.areOrdersBeingProcessed() {
-		return &gen.CompleteResponse{Status: "Will start to process the request shortly", Orders: []*gen.PreparedOrder{}}, nil
+func orderIds(orders []*gen.Order) []string {
+	ids := []string{}
+	for _, order := range orders {
+		ids = append(ids, order.Id)
 	}
-
-	return &gen.CompleteResponse{Status: "The request will be handled immediately", Orders: []*gen.PreparedOrder{}}, nil
+	return ids
 }
 
+// ProcessOrders sorts the loaded batches one at a time, or the loaded orders
+// continuously, until the service is shut down.
 func (fs *fulfillmentService) ProcessOrders(ctx context.Context) error {
+	defer close(fs.done)
+
+	// Shutdown reports the backorders, so it waits for them to be resumed
+	// or put back.
+	resumed := make(chan struct{})
+	defer func() { <-resumed }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-fs.abort:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer close(resumed)
+		fs.resumeBackorders(ctx)
+	}()
+	go fs.enforcePartialTimeouts()
+
+	if fs.flow != nil {
+		return fs.processContinuously(ctx)
+	}
+
 	for {
-		orders := <-fs.orders
+		if fs.isStopping() {
+			return nil
+		}
+
+		var orders []*gen.Order
+		select {
+		case orders = <-fs.orders:
+		case <-fs.stopping:
+			return nil
+		}
 
 		fs.mu.Lock()
 		fs.setAreOrdersBeingProcessed(true)
 
 		err := fs.processOrders(ctx, orders)
 		if err != nil {
-			return err
+			log.Println("Error while processing orders occured: ", err.Error())
 		}
 
 		fs.setAreOrdersBeingProcessed(false)
 		fs.mu.Unlock()
 	}
-	return nil
 }
 
 func (fs *fulfillmentService) processOrders(ctx context.Context, orders []*gen.Order) error {
+	defer fs.reservations.release(orderIds(orders)...)
+
+	start := time.Now()
 	err := fs.StartProcessingOrder(ctx, orders)
+	if err == errShuttingDown {
+		fs.interrupted = fs.unfinishedOrders(orders)
+	}
 	if err != nil {
 		return err
 	}
 
+	logThroughput(orders, time.Since(start))
 	return nil
 }
 
+// logThroughput reports how fast a batch went through the robot, so the
+// service can be measured against the robot's simulated arm speeds.
+func logThroughput(orders []*gen.Order, elapsed time.Duration) {
+	items := 0
+	for _, order := range orders {
+		items += len(order.Items)
+	}
+
+	log.Printf("Processed %d orders with %d items in %v (%.2f items/s)", len(orders), items, elapsed, float64(items)/elapsed.Seconds())
+}
+
 func (fs *fulfillmentService) StartProcessingOrder(ctx context.Context, orders []*gen.Order) error {
 	defer func() {
 		if r := recover(); r != nil {
@@ -114,48 +305,144 @@ func (fs *fulfillmentService) GetPreparedOrders(orders []*gen.Order) ([]*gen.Pre
 }
 
 func (fs *fulfillmentService) fulfillOrders(ctx context.Context, orders []*gen.Order) error {
-	for _, order := range orders {
-		for _, _ = range order.Items {
-			resp, err := fs.sortingRobot.SelectItem(ctx, &gen.Empty{})
-			if err != nil {
-				return err
-			}
+	if fs.putWall != nil {
+		if err := fs.waitForPuts(ctx, orders); err != nil {
+			return err
+		}
+		fs.state.ReleaseCubbies()
+		return nil
+	}
 
-			orderCubby, err := fs.state.GetOrderCubbyByItemCode(resp.Item.Code)
-			if err != nil {
-				log.Println(err)
-				fs.state.AddItemStatusForOrder(order.Id, state.Failed)
-				continue
-			}
+	if err := fs.sortBatch(ctx, orders); err != nil {
+		return err
+	}
+	fs.state.ReleaseCubbies()
 
-			_, err = fs.sortingRobot.MoveItem(ctx, &gen.MoveItemRequest{Cubby: orderCubby.Cubby})
-			if err != nil {
-				fs.state.AddItemStatusForOrder(order.Id, state.Failed)
-				return err
-			}
+	return nil
+}
+
+// waitForRobotHealth holds back r's next item until r is healthy.
+func (fs *fulfillmentService) waitForRobotHealth(ctx context.Context, r *poolRobot) error {
+	if fs.isStopping() {
+		return errShuttingDown
+	}
 
-			fs.state.AddItemStatusForOrder(order.Id, state.Ready)
-			fmt.Println("Item with code ", resp.Item.Code, " is moved to: ", orderCubby.Cubby.Id)
+	if r.health == nil {
+		return nil
+	}
+
+	ctx, cancel := fs.untilStopping(ctx)
+	defer cancel()
+
+	err := r.health.WaitHealthy(ctx)
+	if fs.isStopping() {
+		return errShuttingDown
+	}
+	return err
+}
+
+// selectItem asks the robot for the next item, selecting again when the
+// robot could not scan the item it picked or was stopped while picking. The
+// wait for an item ends when the service shuts down, as nothing is held yet.
+func (fs *fulfillmentService) selectItem(ctx context.Context, r *poolRobot) (*gen.SelectItemResponse, error) {
+	ctx, cancel := fs.untilStopping(ctx)
+	defer cancel()
+
+	var err error
+	for attempt := 0; attempt < maxScanAttempts; {
+		var resp *gen.SelectItemResponse
+		resp, err = r.robot.SelectItem(ctx)
+		if status.Code(err) == codes.Aborted {
+			log.Println("Robot failed to scan the selected item, selecting again.")
+			attempt++
+			continue
+		}
+
+		if fs.waitForRobot(ctx, r, err) {
+			continue
 		}
-		fmt.Println(fs.state.GetAllOrdersData())
+		return resp, err
 	}
-	fs.state.Clear()
 
-	return nil
+	return nil, err
+}
+
+// moveItem puts the held item into cubby, moving it again if the robot was
+// stopped on the way.
+func (fs *fulfillmentService) moveItem(ctx context.Context, r *poolRobot, cubby *gen.Cubby, pickToken string) error {
+	for {
+		err := r.robot.MoveItem(ctx, cubby, pickToken)
+		if fs.waitForRobot(ctx, r, err) {
+			continue
+		}
+		return err
+	}
+}
+
+// waitForRobot pauses r's picks while it is emergency stopped and
+// reports whether the call that failed with err should be retried. Calls that
+// were refused or halted by a stop are retried once the robot is resumed; a
+// jammed robot needs an operator, so the call is not retried.
+func (fs *fulfillmentService) waitForRobot(ctx context.Context, r *poolRobot, err error) bool {
+	if status.Code(err) != codes.Unavailable {
+		return false
+	}
+
+	paused := false
+	for {
+		robotStatus, statusErr := r.robot.Status(ctx)
+		if statusErr != nil {
+			return false
+		}
+
+		if robotStatus.State != gen.RobotState_STOPPED {
+			if paused {
+				log.Println("Robot ", r.name, " resumed, continuing its picks.")
+			}
+			return robotStatus.State != gen.RobotState_FAULTED
+		}
+
+		if !paused {
+			log.Println("Robot ", r.name, " is emergency stopped, pausing its picks.")
+			paused = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-fs.stopping:
+			return false
+		case <-time.After(robotStatusPollInterval):
+		}
+	}
 }
 
 func (fs *fulfillmentService) GetOrderFulfillmentStatusById(ctx context.Context, in *gen.OrderIdRequest) (*gen.OrdersStatusResponse, error) {
 	orderData, err := fs.state.GetOrderDataById(in.OrderId)
 	if err != nil {
-		return nil, err
+		for _, backorderStatus := range fs.reservations.backorderStatuses() {
+			if backorderStatus.Order.Id == in.OrderId {
+				return &gen.OrdersStatusResponse{FulfillmentStatus: []*gen.FulfillmentStatus{backorderStatus}}, nil
+			}
+		}
+		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	order := &gen.Order{Id: in.OrderId, Items: orderData.Items}
-	fulfillmentStatus := &gen.FulfillmentStatus{Order: order, Cubby: orderData.Cubby, Status: orderData.Status}
-	return &gen.OrdersStatusResponse{FulfillmentStatus: []*gen.FulfillmentStatus{fulfillmentStatus}}, nil
+	return &gen.OrdersStatusResponse{FulfillmentStatus: []*gen.FulfillmentStatus{fulfillmentStatus(orderData)}}, nil
 }
 
 func (fs *fulfillmentService) GetAllOrdersFulfillmentStatus(ctx context.Context, in *gen.Empty) (*gen.OrdersStatusResponse, error) {
+	fulfillmentStatusSlice, err := fs.fulfillmentStatuses()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gen.OrdersStatusResponse{FulfillmentStatus: fulfillmentStatusSlice}, nil
+}
+
+// fulfillmentStatuses reports every order, those sorted or waiting to be
+// picked up and those waiting for stock.
+func (fs *fulfillmentService) fulfillmentStatuses() ([]*gen.FulfillmentStatus, error) {
 	orderDataSlice, err := fs.state.GetAllOrdersData()
 	if err != nil {
 		return nil, err
@@ -163,18 +450,32 @@ func (fs *fulfillmentService) GetAllOrdersFulfillmentStatus(ctx context.Context,
 
 	fulfillmentStatusSlice := []*gen.FulfillmentStatus{}
 	for _, orderData := range orderDataSlice {
-		order := &gen.Order{Id: orderData.Id, Items: orderData.Items}
-		fulfillmentStatus := &gen.FulfillmentStatus{Order: order, Cubby: orderData.Cubby, Status: orderData.Status}
-		fulfillmentStatusSlice = append(fulfillmentStatusSlice, fulfillmentStatus)
+		fulfillmentStatusSlice = append(fulfillmentStatusSlice, fulfillmentStatus(orderData))
 	}
-
-	return &gen.OrdersStatusResponse{FulfillmentStatus: fulfillmentStatusSlice}, nil
+	return append(fulfillmentStatusSlice, fs.reservations.backorderStatuses()...), nil
 }
 
+// MarkFulfilled records that an order was picked up. An order that is still
+// being sorted cannot be, as its status would be reported from the items
+// sorted for it ever after.
 func (fs *fulfillmentService) MarkFulfilled(ctx context.Context, in *gen.OrderIdRequest) (*gen.Empty, error) {
-	err := fs.state.SetOrderStatus(in.OrderId, gen.OrderStatus_READY)
+	orderData, err := fs.state.GetOrderDataById(in.OrderId)
 	if err != nil {
-		return nil, err
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if orderData.Status == gen.OrderStatus_PENDING {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %s is still being sorted", in.OrderId)
+	}
+
+	err = fs.state.SetOrderStatus(in.OrderId, gen.OrderStatus_READY)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if fs.flow != nil {
+		if err := fs.releaseOrder(in.OrderId); err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
 	}
 
 	return &gen.Empty{}, nil
@@ -1,12 +1,75 @@
 package service
 
 import (
+	"context"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/robot"
 	"github.com/Emoto13/sort-system/fulfillment-service/state"
 	"github.com/Emoto13/sort-system/gen"
 )
 
+// RobotHealth tells whether the sorting robot can take work.
+type RobotHealth interface {
+	WaitHealthy(ctx context.Context) error
+}
+
+// PooledRobot is one robot of a pool that sorts in parallel.
+type PooledRobot struct {
+	// Name tells the robot apart in logs, e.g. its address.
+	Name  string
+	Robot robot.Robot
+	// Health, if set, holds back the robot's picks while it is unhealthy.
+	Health RobotHealth
+	// WallId is the wall the robot sorts into. A robot that serves no wall
+	// in particular sorts for every wall without a robot of its own.
+	WallId string
+	// Failed starts the robot out of the pool, e.g. as it could not be
+	// connected to. It rejoins once it reports that it is not faulted.
+	Failed bool
+}
+
 type FulfillmentServiceParameters struct {
-	SortingRobot gen.SortingRobotClient
-	State        state.State
-	Orders       chan []*gen.Order
+	// Robot sorts the items into cubbies. If neither Robot nor Robots are
+	// set, operators sort them by hand at a put wall instead, see ScanItem.
+	Robot robot.Robot
+	// Robots sort the items in parallel, each from its own input bin.
+	Robots []PooledRobot
+	// Continuous sorts orders as they are loaded, each order on its own,
+	// instead of one batch at a time. Cubbies are freed as their orders are
+	// picked up.
+	Continuous bool
+	// PutTimeout is how long an operator at the put wall has to confirm
+	// putting a scanned item into its cubby before it counts as lost.
+	PutTimeout time.Duration
+	// Admission decides what happens to loaded orders the robots do not hold
+	// the items of. Every order is admitted if it is not set.
+	Admission Admission
+	// PartialTimeout is how long an order may miss items before
+	// PartialPolicy is applied to it, unless the order sets its own. Orders
+	// wait for their items for as long as it takes if it is not set.
+	PartialTimeout time.Duration
+	// PartialPolicy is applied to the orders that do not set their own. Such
+	// orders are held if it is not set.
+	PartialPolicy gen.PartialPolicy
+	// AtRiskMargin is how close to its deadline an order that is not ready
+	// yet counts as at risk, see GetOrdersAtRisk.
+	AtRiskMargin time.Duration
+	// OrderRetention is how long the status of an order is kept after its
+	// cubby is freed, an hour if it is not set. Its id cannot be loaded
+	// again until then.
+	OrderRetention time.Duration
+	// RobotHealth, if set, holds back Robot's picks while it is unhealthy.
+	RobotHealth RobotHealth
+	State       state.State
+	Orders      chan []*gen.Order
+}
+
+// robots returns the pool the service sorts with.
+func (params *FulfillmentServiceParameters) robots() []PooledRobot {
+	robots := append([]PooledRobot{}, params.Robots...)
+	if params.Robot != nil {
+		robots = append(robots, PooledRobot{Name: "robot", Robot: params.Robot, Health: params.RobotHealth})
+	}
+	return robots
 }
@@ -1 +1,793 @@
 package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/fulfillment-service/fakerobot"
+	"github.com/Emoto13/sort-system/fulfillment-service/robot"
+	"github.com/Emoto13/sort-system/fulfillment-service/state"
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestService(sortingRobot gen.SortingRobotClient) *fulfillmentService {
+	return New(&FulfillmentServiceParameters{
+		Robot:  robot.NewGRPC(sortingRobot),
+		State:  state.New(10),
+		Orders: make(chan []*gen.Order),
+	}).(*fulfillmentService)
+}
+
+func orderStatus(t *testing.T, fs *fulfillmentService, orderId string) gen.OrderStatus {
+	orderData, err := fs.state.GetOrderDataById(orderId)
+	assert.Equal(t, err, nil, "The order should exist")
+	return orderData.Status
+}
+
+func TestStartProcessingOrder_MovesItemsToOrderCubbies(t *testing.T) {
+	orders := []*gen.Order{
+		{Id: "1", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}},
+		{Id: "2", Items: []*gen.Item{{Code: "c"}}},
+	}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "c"}, {Code: "a"}, {Code: "b"}}})
+	fs := newTestService(sortingRobot)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+
+	cubbies := map[string]string{}
+	for _, order := range orders {
+		orderData, _ := fs.state.GetOrderDataById(order.Id)
+		for _, item := range order.Items {
+			cubbies[item.Code] = orderData.Cubby.Id
+		}
+		assert.Equal(t, orderData.Status, gen.OrderStatus_READY, "Every order should be ready")
+	}
+
+	expected := []fakerobot.Move{{ItemCode: "c", CubbyId: cubbies["c"]}, {ItemCode: "a", CubbyId: cubbies["a"]}, {ItemCode: "b", CubbyId: cubbies["b"]}}
+	assert.Equal(t, sortingRobot.Moves(), expected, "Every item should be moved to its order's cubby in the order it was picked")
+}
+
+func TestStartProcessingOrder_KeepsStatusOfSortedBatch(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}}))
+
+	assert.Equal(t, fs.StartProcessingOrder(context.Background(), orders), nil, "Processing should succeed")
+
+	resp, err := fs.GetOrderFulfillmentStatusById(context.Background(), &gen.OrderIdRequest{OrderId: "1"})
+	assert.Equal(t, err, nil, "An order of a sorted batch should still be found")
+	assert.Equal(t, resp.FulfillmentStatus[0].Status, gen.OrderStatus_READY, "The order should be reported as ready once its batch is done")
+}
+
+func TestStartProcessingOrder_FailsOrderWhenItemIsDropped(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{
+		Picks:  []*gen.Item{{Code: "a"}, {Code: "b"}},
+		Faults: []fakerobot.Fault{{Method: "MoveItem", Call: 1, Err: status.Error(codes.DataLoss, "item was dropped")}},
+	})
+	fs := newTestService(sortingRobot)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "A dropped item should not stop the batch")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_FAILED, "The order should fail")
+	assert.Equal(t, len(sortingRobot.Moves()), 1, "The item that was not dropped should still be moved")
+	assert.Equal(t, len(sortingRobot.CallsTo("ReturnItem")), 0, "A dropped item should not be returned")
+}
+
+func TestStartProcessingOrder_SelectsAgainAfterScanFailure(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{
+		Picks:  []*gen.Item{{Code: "a"}},
+		Faults: []fakerobot.Fault{{Method: "SelectItem", Call: 1, Err: status.Error(codes.Aborted, "failed to scan item")}},
+	})
+	fs := newTestService(sortingRobot)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order should be ready")
+	assert.Equal(t, len(sortingRobot.CallsTo("SelectItem")), 2, "The item should be selected again")
+}
+
+func TestStartProcessingOrder_ReturnsItemOfNoOrder(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "unknown"}, {Code: "a"}}})
+	fs := newTestService(sortingRobot)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_FAILED, "The order should fail")
+	assert.Equal(t, len(sortingRobot.CallsTo("ReturnItem")), 1, "The item of no order should be returned")
+	assert.Equal(t, len(sortingRobot.Moves()), 0, "No item should be moved")
+}
+
+func TestStartProcessingOrder_ReleasesReservationsOfFailedOrders(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "b"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{
+		Picks:  []*gen.Item{{Code: "a"}, {Code: "unknown"}, {Code: "b"}},
+		Faults: []fakerobot.Fault{{Method: "MoveItem", Call: 1, Err: status.Error(codes.DataLoss, "item was dropped")}},
+	})
+	fs := newTestService(sortingRobot)
+	fs.admission = RejectShort
+	ctx := context.Background()
+
+	_, _, _, err := fs.admitOrders(ctx, orders)
+	assert.Equal(t, err, nil, "Admitting orders should succeed")
+
+	err = fs.StartProcessingOrder(ctx, orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_FAILED, "The order whose item was dropped should fail")
+	assert.Equal(t, orderStatus(t, fs, "2"), gen.OrderStatus_FAILED, "The order an item of no order was picked for should fail")
+
+	reservations, err := fs.GetReservations(ctx, &gen.Empty{})
+	assert.Equal(t, err, nil, "Listing reservations should succeed")
+	assert.Equal(t, reservations.Reservations, []*gen.Reservation{}, "The failed orders should have no items reserved")
+}
+
+func TestStartProcessingOrder_WaitsWhileRobotIsStopped(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{
+		Picks:  []*gen.Item{{Code: "a"}},
+		Faults: []fakerobot.Fault{{Method: "SelectItem", Call: 1, Err: status.Error(codes.Unavailable, "robot is emergency stopped")}},
+		States: []gen.RobotState{gen.RobotState_STOPPED, gen.RobotState_IDLE},
+	})
+	fs := newTestService(sortingRobot)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed once the robot is resumed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order should be ready")
+	assert.Equal(t, len(sortingRobot.CallsTo("GetRobotStatus")), 2, "The robot's status should be polled until it is resumed")
+}
+
+func TestStartProcessingOrder_StopsWhenRobotIsJammed(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{
+		Picks:  []*gen.Item{{Code: "a"}},
+		Faults: []fakerobot.Fault{{Method: "MoveItem", Call: 1, Err: status.Error(codes.Unavailable, "robot jammed")}},
+		States: []gen.RobotState{gen.RobotState_FAULTED},
+	})
+	fs := newTestService(sortingRobot)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, status.Code(err), codes.Unavailable, "Processing should stop when the robot is jammed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_PENDING, "The order should keep waiting for the returned item")
+	assert.Equal(t, len(sortingRobot.CallsTo("ReturnItem")), 1, "The held item should be returned")
+
+	orderCubby, err := fs.state.GetOrderCubbyByItemCode("a")
+	assert.Equal(t, err, nil, "The returned item should still be matched to its order")
+	assert.Equal(t, orderCubby.Order.Id, "1", "The returned item should still belong to its order")
+}
+
+func TestStartProcessingOrder_GivesUpWhenContextIsDone(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}, Latency: time.Minute})
+	fs := newTestService(sortingRobot)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := fs.StartProcessingOrder(ctx, orders)
+	assert.Equal(t, status.Code(err), codes.DeadlineExceeded, "Processing should stop when the context is done")
+	assert.Equal(t, len(sortingRobot.Calls()), 0, "A call cut off by its context should not reach the robot")
+}
+
+func newPoolService(robots ...*fakerobot.Robot) *fulfillmentService {
+	pool := []PooledRobot{}
+	for i, sortingRobot := range robots {
+		pool = append(pool, PooledRobot{Name: fmt.Sprintf("robot-%d", i+1), Robot: robot.NewGRPC(sortingRobot)})
+	}
+	return New(&FulfillmentServiceParameters{
+		Robots: pool,
+		State:  state.New(10),
+		Orders: make(chan []*gen.Order),
+	}).(*fulfillmentService)
+}
+
+func TestStartProcessingOrder_SortsWithEveryRobotOfThePool(t *testing.T) {
+	orders := []*gen.Order{
+		{Id: "1", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}},
+		{Id: "2", Items: []*gen.Item{{Code: "c"}, {Code: "d"}}},
+	}
+	first := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}, {Code: "c"}}, Latency: time.Millisecond})
+	second := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "d"}, {Code: "b"}}, Latency: time.Millisecond})
+	fs := newPoolService(first, second)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order should be ready")
+	assert.Equal(t, orderStatus(t, fs, "2"), gen.OrderStatus_READY, "The order should be ready")
+	assert.Equal(t, len(first.Moves()), 2, "The first robot should sort the items of its input bin")
+	assert.Equal(t, len(second.Moves()), 2, "The second robot should sort the items of its input bin")
+}
+
+func TestStartProcessingOrder_TakesFailedRobotOutOfThePool(t *testing.T) {
+	orders := []*gen.Order{
+		{Id: "1", Items: []*gen.Item{{Code: "a"}}},
+		{Id: "2", Items: []*gen.Item{{Code: "b"}, {Code: "c"}}},
+	}
+	jammed := fakerobot.New(fakerobot.Scenario{
+		Picks:  []*gen.Item{{Code: "a"}},
+		Faults: []fakerobot.Fault{{Method: "MoveItem", Call: 1, Err: status.Error(codes.Unavailable, "robot jammed")}},
+		States: []gen.RobotState{gen.RobotState_FAULTED, gen.RobotState_FAULTED, gen.RobotState_IDLE},
+	})
+	healthy := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "b"}, {Code: "c"}}})
+	fs := newPoolService(jammed, healthy)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.NotEqual(t, err, nil, "The batch should not be done while an item is in the failed robot's input bin")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_PENDING, "The order should keep waiting for the returned item")
+	assert.Equal(t, orderStatus(t, fs, "2"), gen.OrderStatus_READY, "The other robot should go on sorting")
+	assert.Equal(t, len(jammed.CallsTo("ReturnItem")), 1, "The failed robot's item should be returned")
+
+	assert.Equal(t, len(fs.pool.available(context.Background())), 1, "The failed robot should be out of the pool")
+	assert.Equal(t, len(fs.pool.available(context.Background())), 2, "The robot should rejoin the pool once it is no longer faulted")
+}
+
+func TestStartProcessingOrder_SortsWithoutRobotThatStartedFailed(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}}}
+	unreachable := fakerobot.New(fakerobot.Scenario{Faults: []fakerobot.Fault{{Method: "GetRobotStatus", Call: 1, Err: status.Error(codes.Unavailable, "robot unreachable")}}})
+	healthy := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}, {Code: "b"}}})
+	fs := New(&FulfillmentServiceParameters{
+		Robots: []PooledRobot{
+			{Name: "robot-1", Robot: robot.NewGRPC(unreachable), Failed: true},
+			{Name: "robot-2", Robot: robot.NewGRPC(healthy)},
+		},
+		State:  state.New(10),
+		Orders: make(chan []*gen.Order),
+	}).(*fulfillmentService)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order should be ready")
+	assert.Equal(t, len(unreachable.CallsTo("SelectItem")), 0, "The robot that started failed should not pick")
+	assert.Equal(t, len(fs.pool.available(context.Background())), 2, "The robot should join the pool once it reports that it is not faulted")
+}
+
+func TestRobotPool_AsksFailedRobotsForStatusWithoutLocking(t *testing.T) {
+	slow := fakerobot.New(fakerobot.Scenario{Latency: time.Minute})
+	healthy := fakerobot.New(fakerobot.Scenario{})
+	pool := newRobotPool([]PooledRobot{
+		{Name: "robot-1", Robot: robot.NewGRPC(slow), Failed: true},
+		{Name: "robot-2", Robot: robot.NewGRPC(healthy)},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	available := make(chan []*poolRobot)
+	go func() {
+		available <- pool.available(ctx)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	failed := make(chan bool)
+	go func() {
+		failed <- pool.isFailed(pool.robots[1])
+	}()
+	select {
+	case isFailed := <-failed:
+		assert.Equal(t, isFailed, false, "The healthy robot should not be failed")
+	case <-time.After(time.Second):
+		t.Fatal("the pool stayed locked while a failed robot was asked for its status")
+	}
+
+	cancel()
+	robots := <-available
+	assert.Equal(t, len(robots), 1, "The robot that did not answer should stay out of the pool")
+	assert.Equal(t, robots[0].name, "robot-2", "The healthy robot should be available")
+}
+
+func TestStartProcessingOrder_SortsEachWallWithItsRobot(t *testing.T) {
+	orders := []*gen.Order{
+		{Id: "1", Items: []*gen.Item{{Code: "a"}}},
+		{Id: "2", Items: []*gen.Item{{Code: "a"}}},
+	}
+	wallA := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}})
+	wallB := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}})
+	fs := New(&FulfillmentServiceParameters{
+		Robots: []PooledRobot{
+			{Name: "robot-a", Robot: robot.NewGRPC(wallA), WallId: "A"},
+			{Name: "robot-b", Robot: robot.NewGRPC(wallB), WallId: "B"},
+		},
+		State:  state.NewWalls([]state.Wall{{Id: "A", Cubbies: 5}, {Id: "B", Cubbies: 5}}, state.BalanceLoad),
+		Orders: make(chan []*gen.Order),
+	}).(*fulfillmentService)
+
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.Equal(t, err, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order should be ready")
+	assert.Equal(t, orderStatus(t, fs, "2"), gen.OrderStatus_READY, "The order should be ready")
+
+	for _, sortingRobot := range []*fakerobot.Robot{wallA, wallB} {
+		moves := sortingRobot.Moves()
+		assert.Equal(t, len(moves), 1, "Each robot should sort the item of its wall")
+	}
+	first, _ := fs.state.GetOrderDataById("1")
+	second, _ := fs.state.GetOrderDataById("2")
+	assert.Equal(t, wallA.Moves()[0].CubbyId, first.Cubby.Id, "The robot should sort into the cubby on its wall")
+	assert.Equal(t, wallB.Moves()[0].CubbyId, second.Cubby.Id, "The robot should sort into the cubby on its wall")
+}
+
+func newPutWallService(putTimeout time.Duration) *fulfillmentService {
+	return New(&FulfillmentServiceParameters{
+		PutTimeout: putTimeout,
+		State:      state.New(10),
+		Orders:     make(chan []*gen.Order),
+	}).(*fulfillmentService)
+}
+
+// startPutWallBatch processes orders at the put wall and waits until the
+// batch is ready for scans.
+func startPutWallBatch(t *testing.T, fs *fulfillmentService, orders []*gen.Order) chan error {
+	processed := make(chan error, 1)
+	go func() {
+		processed <- fs.StartProcessingOrder(context.Background(), orders)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for fs.putWall.remaining() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("The batch should start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return processed
+}
+
+func TestScanItem_TellsOperatorTheCubby(t *testing.T) {
+	orders := []*gen.Order{
+		{Id: "1", Items: []*gen.Item{{Code: "a"}}},
+		{Id: "2", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}},
+	}
+	fs := newPutWallService(time.Minute)
+	processed := startPutWallBatch(t, fs, orders)
+	ctx := context.Background()
+
+	first, err := fs.ScanItem(ctx, &gen.ScanItemRequest{ItemCode: "a"})
+	assert.Equal(t, err, nil, "Scanning an item of an order should succeed")
+	second, err := fs.ScanItem(ctx, &gen.ScanItemRequest{ItemCode: "a"})
+	assert.Equal(t, err, nil, "Another operator scanning the same item should succeed")
+	third, err := fs.ScanItem(ctx, &gen.ScanItemRequest{ItemCode: "b"})
+	assert.Equal(t, err, nil, "Scanning an item of an order should succeed")
+
+	firstOrder, _ := fs.state.GetOrderDataById("1")
+	secondOrder, _ := fs.state.GetOrderDataById("2")
+	assert.Equal(t, first.Cubby.Id, firstOrder.Cubby.Id, "The first item should go into the first order's cubby")
+	assert.Equal(t, second.Cubby.Id, secondOrder.Cubby.Id, "The second item should go into the second order's cubby")
+	assert.Equal(t, third.Cubby.Id, secondOrder.Cubby.Id, "The item should go into its order's cubby")
+
+	_, err = fs.ConfirmPut(ctx, &gen.ConfirmPutRequest{ItemCode: "b", CubbyId: firstOrder.Cubby.Id})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument, "Confirming the wrong cubby should fail")
+
+	for _, resp := range []*gen.ScanItemResponse{second, first} {
+		_, err = fs.ConfirmPut(ctx, &gen.ConfirmPutRequest{ItemCode: "a", CubbyId: resp.Cubby.Id})
+		assert.Equal(t, err, nil, "Confirming the put should succeed")
+	}
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order with every item put should be ready")
+	assert.Equal(t, orderStatus(t, fs, "2"), gen.OrderStatus_PENDING, "The order should wait for its last item")
+
+	_, err = fs.ConfirmPut(ctx, &gen.ConfirmPutRequest{ItemCode: "b", CubbyId: third.Cubby.Id})
+	assert.Equal(t, err, nil, "Confirming the put should succeed")
+	assert.Equal(t, <-processed, nil, "The batch should be done once every item is put")
+	assert.Equal(t, orderStatus(t, fs, "2"), gen.OrderStatus_READY, "The order should be ready")
+}
+
+func TestScanItem_ItemOfNoOrder(t *testing.T) {
+	fs := newPutWallService(time.Minute)
+
+	_, err := fs.ScanItem(context.Background(), &gen.ScanItemRequest{ItemCode: "a"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "An item should be put aside while no batch is sorted")
+
+	startPutWallBatch(t, fs, []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}})
+
+	_, err = fs.ScanItem(context.Background(), &gen.ScanItemRequest{ItemCode: "unknown"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "An item of no order should be put aside")
+}
+
+func TestScanItem_NotPutWall(t *testing.T) {
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{}))
+
+	_, err := fs.ScanItem(context.Background(), &gen.ScanItemRequest{ItemCode: "a"})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "Scanning should fail when a robot sorts the items")
+}
+
+func TestScanItem_PicksForOperator(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	fs := New(&FulfillmentServiceParameters{
+		Robots: []PooledRobot{{Name: "operator", Robot: robot.NewManual(time.Minute)}},
+		State:  state.New(10),
+		Orders: make(chan []*gen.Order),
+	}).(*fulfillmentService)
+
+	processed := make(chan error)
+	go func() {
+		processed <- fs.StartProcessingOrder(context.Background(), orders)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := fs.ScanItem(ctx, &gen.ScanItemRequest{ItemCode: "a"})
+	assert.Equal(t, err, nil, "Scanning an item of the order should succeed")
+	orderData, _ := fs.state.GetOrderDataById("1")
+	assert.Equal(t, resp.Cubby.Id, orderData.Cubby.Id, "The operator should be told the order's cubby")
+
+	_, err = fs.ConfirmPut(ctx, &gen.ConfirmPutRequest{ItemCode: "a", CubbyId: resp.Cubby.Id})
+	assert.Equal(t, err, nil, "Confirming the put should succeed")
+	assert.Equal(t, <-processed, nil, "Processing should succeed")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_READY, "The order should be ready")
+}
+
+func TestScanItem_OperatorPutsAsideItemOfNoOrder(t *testing.T) {
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	fs := New(&FulfillmentServiceParameters{
+		Robots: []PooledRobot{{Name: "operator", Robot: robot.NewManual(time.Minute)}},
+		State:  state.New(10),
+		Orders: make(chan []*gen.Order),
+	}).(*fulfillmentService)
+	go fs.StartProcessingOrder(context.Background(), orders)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := fs.ScanItem(ctx, &gen.ScanItemRequest{ItemCode: "unknown"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "An item of no order should be put aside")
+}
+
+func TestConfirmPut_FailsOrderOfItemNotPutInTime(t *testing.T) {
+	fs := newPutWallService(10 * time.Millisecond)
+	processed := startPutWallBatch(t, fs, []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}})
+
+	resp, err := fs.ScanItem(context.Background(), &gen.ScanItemRequest{ItemCode: "a"})
+	assert.Equal(t, err, nil, "Scanning an item of the order should succeed")
+
+	assert.Equal(t, <-processed, nil, "The batch should be done once its last item is lost")
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_FAILED, "The order should fail")
+
+	_, err = fs.ConfirmPut(context.Background(), &gen.ConfirmPutRequest{ItemCode: "a", CubbyId: resp.Cubby.Id})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "A lost item should not be confirmed")
+}
+
+func TestLoadOrders_ReportsBatchInProgress(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}, Latency: 20 * time.Millisecond})
+	fs := newTestService(sortingRobot)
+	go fs.ProcessOrders(context.Background())
+	t.Cleanup(func() {
+		fs.Shutdown(context.Background())
+	})
+	ctx := context.Background()
+
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	for len(sortingRobot.Calls()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	resp, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "2", Items: []*gen.Item{{Code: "b"}}}}})
+	assert.Equal(t, err, nil, "Loading orders while a batch is sorted should succeed")
+	assert.Equal(t, resp.Status, "Will start to process the request shortly", "The orders should wait for the batch being sorted")
+}
+
+func newContinuousService(t *testing.T, sortingRobot *fakerobot.Robot, numberOfCubbies int) *fulfillmentService {
+	fs := New(&FulfillmentServiceParameters{
+		Robot:      robot.NewGRPC(sortingRobot),
+		State:      state.New(numberOfCubbies),
+		Orders:     make(chan []*gen.Order),
+		Continuous: true,
+	}).(*fulfillmentService)
+	go fs.ProcessOrders(context.Background())
+	t.Cleanup(func() {
+		fs.Shutdown(context.Background())
+	})
+	return fs
+}
+
+// waitForStatus waits until the order has orderStatus.
+func waitForStatus(t *testing.T, fs *fulfillmentService, orderId string, orderStatus gen.OrderStatus) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		orderData, err := fs.state.GetOrderDataById(orderId)
+		if err == nil && orderData.Status == orderStatus {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Order %s should become %v", orderId, orderStatus)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestProcessOrders_ContinuouslyFinishesEachOrderOnItsOwn(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}})
+	fs := newContinuousService(t, sortingRobot, 10)
+	ctx := context.Background()
+
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}, {Code: "c"}}}}})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	for len(sortingRobot.Moves()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "2", Items: []*gen.Item{{Code: "b"}}}}})
+	assert.Equal(t, err, nil, "Loading orders while others are sorted should succeed")
+	sortingRobot.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "b"}}})
+
+	waitForStatus(t, fs, "2", gen.OrderStatus_READY)
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_PENDING, "The order that still waits for an item should not hold back the other")
+}
+
+func TestProcessOrders_ContinuouslyFreesCubbyOnPickup(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}, {Code: "b"}}})
+	fs := newContinuousService(t, sortingRobot, 1)
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "b"}}}}
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	waitForStatus(t, fs, "1", gen.OrderStatus_READY)
+
+	_, err = fs.state.GetOrderDataById("2")
+	assert.NotEqual(t, err, nil, "The order should wait for a free cubby")
+
+	_, err = fs.MarkFulfilled(ctx, &gen.OrderIdRequest{OrderId: "1"})
+	assert.Equal(t, err, nil, "Marking the order as picked up should succeed")
+
+	waitForStatus(t, fs, "2", gen.OrderStatus_READY)
+	first, _ := fs.state.GetOrderDataById("1")
+	second, _ := fs.state.GetOrderDataById("2")
+	assert.Equal(t, second.Cubby.Id, first.Cubby.Id, "The order should get the cubby that was picked up")
+}
+
+func TestMarkFulfilled_RejectsOrderBeingSorted(t *testing.T) {
+	fs := newContinuousService(t, fakerobot.New(fakerobot.Scenario{}), 1)
+	ctx := context.Background()
+
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	waitForStatus(t, fs, "1", gen.OrderStatus_PENDING)
+
+	_, err = fs.MarkFulfilled(ctx, &gen.OrderIdRequest{OrderId: "1"})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "An order still being sorted should not be picked up")
+	assert.Equal(t, fs.state.FreeCubbies(), 0, "The order should keep its cubby")
+
+	_, err = fs.MarkFulfilled(ctx, &gen.OrderIdRequest{OrderId: "2"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "An unknown order should not be picked up")
+}
+
+func TestGetOrdersAtRisk_ListsOrdersDueSoonestFirst(t *testing.T) {
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{}))
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	fs.state.AddOrders([]*gen.Order{
+		{Id: "later", ReadyByMillis: now + time.Hour.Milliseconds()},
+		{Id: "soon", ReadyByMillis: now + time.Minute.Milliseconds()},
+		{Id: "overdue", ReadyByMillis: now - time.Minute.Milliseconds()},
+		{Id: "ready", Items: []*gen.Item{{Code: "a"}}, ReadyByMillis: now},
+		{Id: "no-deadline"},
+	})
+	fs.state.AddItemStatusForOrder("ready", state.Ready)
+
+	resp, err := fs.GetOrdersAtRisk(context.Background(), &gen.OrdersAtRiskRequest{})
+	assert.Equal(t, err, nil, "Listing orders at risk should succeed")
+
+	orderIds := []string{}
+	for _, order := range resp.Orders {
+		orderIds = append(orderIds, order.FulfillmentStatus.Order.Id)
+	}
+	assert.Equal(t, orderIds, []string{"overdue", "soon"}, "Orders that are not ready and due within the margin should be listed, overdue first")
+	assert.Equal(t, resp.Orders[0].MillisLeft < 0, true, "An overdue order should have no time left")
+
+	resp, err = fs.GetOrdersAtRisk(context.Background(), &gen.OrdersAtRiskRequest{WithinMillis: 2 * time.Hour.Milliseconds()})
+	assert.Equal(t, err, nil, "Listing orders at risk should succeed")
+	assert.Equal(t, len(resp.Orders), 3, "A wider window should list the orders due later too")
+}
+
+func TestLoadOrders_RejectsOrdersOutOfStock(t *testing.T) {
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}, {Code: "b"}, {Code: "c"}}}))
+	fs.admission = RejectShort
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "a"}}}, {Id: "3", Items: []*gen.Item{{Code: "b"}}}}
+	resp, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	assert.Equal(t, resp.RejectedOrderIds, []string{"2"}, "The order whose item is reserved for another should be rejected")
+
+	reservations, err := fs.GetReservations(ctx, &gen.Empty{})
+	assert.Equal(t, err, nil, "Listing reservations should succeed")
+	assert.Equal(t, reservations.Reservations, []*gen.Reservation{
+		{OrderId: "1", Items: []*gen.StockLevel{{Code: "a", Quantity: 1}}},
+		{OrderId: "3", Items: []*gen.StockLevel{{Code: "b", Quantity: 1}}},
+	}, "The admitted orders should have their items reserved")
+	assert.Equal(t, reservations.Available, []*gen.StockLevel{{Code: "c", Quantity: 1}}, "Only the stock no order reserved should be available")
+}
+
+func TestLoadOrders_RejectsOrdersLoadedAlready(t *testing.T) {
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}}))
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, status.Code(err), codes.AlreadyExists, "An order should not be loaded again")
+
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "2"}, {Id: "2"}}})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument, "An order should not be loaded twice at once")
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "2"}}})
+	assert.Equal(t, err, nil, "Orders of a refused request should be loaded later")
+
+	assert.Equal(t, fs.StartProcessingOrder(ctx, orders), nil, "Processing should succeed")
+	fs.state.ReleaseCubbies()
+	fs.evictOrders(time.Now())
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, status.Code(err), codes.AlreadyExists, "An order should not be loaded again while its status is kept")
+
+	fs.evictOrders(time.Now().Add(2 * defaultOrderRetention))
+	_, err = fs.GetOrderFulfillmentStatusById(ctx, &gen.OrderIdRequest{OrderId: "1"})
+	assert.Equal(t, status.Code(err), codes.NotFound, "An evicted order should no longer be found")
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "An evicted order should be loaded again")
+}
+
+func TestLoadOrders_LoadsRejectedOrdersAgain(t *testing.T) {
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{}))
+	fs.admission = RejectShort
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}
+	for i := 0; i < 2; i++ {
+		resp, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+		assert.Equal(t, err, nil, "Loading orders should succeed")
+		assert.Equal(t, resp.RejectedOrderIds, []string{"1"}, "The order out of stock should be rejected, not taken as loaded")
+	}
+}
+
+func TestLoadOrders_BackordersOrdersOutOfStock(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}})
+	fs := newTestService(sortingRobot)
+	fs.admission = BackorderShort
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}}}
+	resp, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	assert.Equal(t, resp.BackorderedOrderIds, []string{"2"}, "The order out of stock should be backordered")
+
+	reservations, _ := fs.GetReservations(ctx, &gen.Empty{})
+	assert.Equal(t, len(reservations.Backorders), 1, "The order should wait for stock")
+	assert.Equal(t, reservations.Backorders[0].OrderId, "2", "The order should wait for stock")
+	assert.Equal(t, reservations.Backorders[0].Missing, []*gen.StockLevel{{Code: "a", Quantity: 1}, {Code: "b", Quantity: 1}}, "The backorder should list the items it misses")
+
+	sortingRobot.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "a"}, {Code: "b"}}})
+	_, err = fs.LoadOrders(ctx, &gen.LoadOrdersRequest{})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+
+	reservations, _ = fs.GetReservations(ctx, &gen.Empty{})
+	assert.Equal(t, len(reservations.Backorders), 0, "The backorder should be admitted once its items are in stock")
+	assert.Equal(t, len(reservations.Reservations), 2, "The admitted backorder should have its items reserved")
+}
+
+func TestProcessOrders_ResumesBackordersWhenStockArrives(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{})
+	fs := newContinuousService(t, sortingRobot, 10)
+	fs.admission = BackorderShort
+	ctx := context.Background()
+
+	resp, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}}})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	assert.Equal(t, resp.BackorderedOrderIds, []string{"1"}, "The order should wait for its items")
+
+	statuses, err := fs.GetOrderFulfillmentStatusById(ctx, &gen.OrderIdRequest{OrderId: "1"})
+	assert.Equal(t, err, nil, "The backorder should have a status")
+	assert.Equal(t, statuses.FulfillmentStatus[0].Status, gen.OrderStatus_BACKORDERED, "The order should be backordered")
+
+	sortingRobot.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "a"}}})
+	waitForStatus(t, fs, "1", gen.OrderStatus_READY)
+
+	reservations, _ := fs.GetReservations(ctx, &gen.Empty{})
+	assert.Equal(t, len(reservations.Backorders), 0, "The order should no longer wait for stock")
+}
+
+func TestAdmitBackorders_KeepsBackordersAtShutdown(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{})
+	fs := newTestService(sortingRobot)
+	fs.admission = BackorderShort
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "b"}}}}
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+
+	sortingRobot.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "a"}}})
+	fs.queue.close()
+	err = fs.admitBackorders(ctx)
+	assert.Equal(t, err, errNotAcceptingOrders, "The backorder in stock should not be sorted while shutting down")
+
+	reservations, _ := fs.GetReservations(ctx, &gen.Empty{})
+	assert.Equal(t, orderIds(fs.reservations.backordered()), []string{"1", "2"}, "The orders should wait for stock again, in the order they were loaded")
+	assert.Equal(t, len(reservations.Reservations), 0, "The order put back should have no items reserved")
+}
+
+func TestTimeOutPartialOrders_ReleasesOrderMissingItems(t *testing.T) {
+	fs := newTestService(fakerobot.New(fakerobot.Scenario{Picks: []*gen.Item{{Code: "a"}}}))
+	fs.partialTimeout = time.Minute
+	fs.partialPolicy = gen.PartialPolicy_RELEASE_PARTIAL
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}, {Code: "b"}}}}
+	err := fs.StartProcessingOrder(context.Background(), orders)
+	assert.NotEqual(t, err, nil, "The batch should not be done without all its items")
+
+	fs.timeOutPartialOrders(time.Now())
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_PENDING, "The order should wait for its items until its timeout")
+
+	fs.timeOutPartialOrders(time.Now().Add(2 * time.Minute))
+	resp, err := fs.GetOrderFulfillmentStatusById(context.Background(), &gen.OrderIdRequest{OrderId: "1"})
+	assert.Equal(t, err, nil, "The order should have a status")
+	assert.Equal(t, resp.FulfillmentStatus[0].Status, gen.OrderStatus_PARTIALLY_READY, "The order should be released as it is")
+	assert.Equal(t, resp.FulfillmentStatus[0].PartialDecision, gen.PartialPolicy_RELEASE_PARTIAL, "The status should report the decision")
+	assert.Equal(t, len(resp.FulfillmentStatus[0].MissingItems), 1, "The status should report the missing item")
+	assert.Equal(t, resp.FulfillmentStatus[0].MissingItems[0].Code, "b", "The status should report the missing item")
+}
+
+func TestTimeOutPartialOrders_CancelledOrderFreesItsCubby(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{})
+	fs := newContinuousService(t, sortingRobot, 1)
+	ctx := context.Background()
+
+	orders := []*gen.Order{
+		{Id: "1", Items: []*gen.Item{{Code: "a"}}, PartialPolicy: gen.PartialPolicy_CANCEL_PARTIAL, PartialTimeoutMillis: time.Minute.Milliseconds()},
+		{Id: "2", Items: []*gen.Item{{Code: "b"}}},
+	}
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Loading orders should succeed")
+	waitForStatus(t, fs, "1", gen.OrderStatus_PENDING)
+
+	fs.timeOutPartialOrders(time.Now().Add(2 * time.Minute))
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_CANCELLED, "The order should be cancelled at its own timeout")
+
+	waitForStatus(t, fs, "2", gen.OrderStatus_PENDING)
+	sortingRobot.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "b"}}})
+	waitForStatus(t, fs, "2", gen.OrderStatus_READY)
+}
+
+func TestLoadOrders_RejectsOrdersHeldInBatches(t *testing.T) {
+	tests := []struct {
+		name           string
+		partialTimeout time.Duration
+		order          *gen.Order
+		code           codes.Code
+	}{
+		{"Order held at its own timeout", 0, &gen.Order{Id: "1", Items: []*gen.Item{{Code: "a"}}, PartialPolicy: gen.PartialPolicy_HOLD_PARTIAL, PartialTimeoutMillis: time.Minute.Milliseconds()}, codes.InvalidArgument},
+		{"Order held at the service's timeout", time.Minute, &gen.Order{Id: "1", Items: []*gen.Item{{Code: "a"}}}, codes.InvalidArgument},
+		{"Order released at the service's timeout", time.Minute, &gen.Order{Id: "1", Items: []*gen.Item{{Code: "a"}}, PartialPolicy: gen.PartialPolicy_RELEASE_PARTIAL}, codes.OK},
+		{"Order held without a timeout", 0, &gen.Order{Id: "1", Items: []*gen.Item{{Code: "a"}}, PartialPolicy: gen.PartialPolicy_HOLD_PARTIAL}, codes.OK},
+	}
+
+	for _, test := range tests {
+		fs := newTestService(fakerobot.New(fakerobot.Scenario{}))
+		fs.partialTimeout = test.partialTimeout
+
+		_, err := fs.LoadOrders(context.Background(), &gen.LoadOrdersRequest{Orders: []*gen.Order{test.order}})
+		assert.Equal(t, status.Code(err), test.code, test.name+" should be answered with "+test.code.String())
+	}
+}
+
+func TestTimeOutPartialOrders_HeldOrderKeepsItsCubby(t *testing.T) {
+	sortingRobot := fakerobot.New(fakerobot.Scenario{})
+	fs := newContinuousService(t, sortingRobot, 1)
+	ctx := context.Background()
+
+	orders := []*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}, PartialPolicy: gen.PartialPolicy_HOLD_PARTIAL, PartialTimeoutMillis: time.Minute.Milliseconds()}}
+	_, err := fs.LoadOrders(ctx, &gen.LoadOrdersRequest{Orders: orders})
+	assert.Equal(t, err, nil, "Sorting continuously should hold orders")
+	waitForStatus(t, fs, "1", gen.OrderStatus_PENDING)
+
+	fs.timeOutPartialOrders(time.Now().Add(2 * time.Minute))
+	assert.Equal(t, orderStatus(t, fs, "1"), gen.OrderStatus_PARTIALLY_READY, "The order should be held at its timeout")
+	assert.Equal(t, fs.state.FreeCubbies(), 0, "The held order should keep its cubby")
+
+	sortingRobot.LoadItems(ctx, &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "a"}}})
+	waitForStatus(t, fs, "1", gen.OrderStatus_READY)
+}
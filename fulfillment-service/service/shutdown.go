@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// returnItemTimeout bounds how long putting an item back into the robot's
+// input bin may take, also when the batch itself was cut off.
+const returnItemTimeout = 10 * time.Second
+
+var (
+	// errShuttingDown ends the batch in progress when the service shuts down.
+	errShuttingDown = errors.New("batch was interrupted by shutdown")
+
+	errNotAcceptingOrders = status.Error(codes.Unavailable, "fulfillment service is shutting down and does not accept orders")
+)
+
+// batchQueue keeps the batches that were loaded but not yet taken up for
+// processing, so they can be reported if the service shuts down.
+type batchQueue struct {
+	batches     map[int][]*gen.Order
+	nextBatchId int
+	closed      bool
+	mu          sync.Mutex
+}
+
+func newBatchQueue() *batchQueue {
+	return &batchQueue{
+		batches: make(map[int][]*gen.Order),
+		mu:      sync.Mutex{},
+	}
+}
+
+func (q *batchQueue) add(orders []*gen.Order) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return 0, errNotAcceptingOrders
+	}
+
+	batchId := q.nextBatchId
+	q.nextBatchId++
+	q.batches[batchId] = orders
+	return batchId, nil
+}
+
+func (q *batchQueue) remove(batchId int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.batches, batchId)
+}
+
+// close refuses any further batches and returns the queued ones in the order
+// they were loaded.
+func (q *batchQueue) close() []*gen.Order {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+
+	batchIds := []int{}
+	for batchId := range q.batches {
+		batchIds = append(batchIds, batchId)
+	}
+	sort.Ints(batchIds)
+
+	orders := []*gen.Order{}
+	for _, batchId := range batchIds {
+		orders = append(orders, q.batches[batchId]...)
+	}
+	return orders
+}
+
+func (fs *fulfillmentService) isStopping() bool {
+	select {
+	case <-fs.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// untilStopping returns a context that is also cancelled when the service
+// starts shutting down.
+func (fs *fulfillmentService) untilStopping(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-fs.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// Shutdown stops accepting orders and lets the item in the robot's gripper
+// finish its move. If ctx is done first, the move is cut off and the item is
+// returned to the robot's input bin. It returns the orders that were not
+// sorted: what was left of the batch in progress, the queued batches and the
+// orders waiting for stock.
+// ProcessOrders must be running, and Shutdown must only be called once.
+func (fs *fulfillmentService) Shutdown(ctx context.Context) []*gen.Order {
+	queued := fs.queue.close()
+	fs.stopOnce.Do(func() {
+		close(fs.stopping)
+	})
+
+	select {
+	case <-fs.done:
+	case <-ctx.Done():
+		log.Println("Timed out waiting for the current item, abandoning it.")
+		close(fs.abort)
+		<-fs.done
+	}
+
+	unprocessed := []*gen.Order{}
+	seen := map[string]bool{}
+	for _, order := range append(append(fs.interrupted, queued...), fs.reservations.backordered()...) {
+		if !seen[order.Id] {
+			seen[order.Id] = true
+			unprocessed = append(unprocessed, order)
+		}
+	}
+	return unprocessed
+}
+
+// unfinishedOrders returns the orders that are still waiting for some of
+// their items, held ones included.
+func (fs *fulfillmentService) unfinishedOrders(orders []*gen.Order) []*gen.Order {
+	unfinished := []*gen.Order{}
+	for _, order := range orders {
+		orderData, err := fs.state.GetOrderDataById(order.Id)
+		if err != nil || orderData.Status == gen.OrderStatus_PENDING || orderData.PartialDecision == gen.PartialPolicy_HOLD_PARTIAL && orderData.Status == gen.OrderStatus_PARTIALLY_READY {
+			unfinished = append(unfinished, order)
+		}
+	}
+	return unfinished
+}
+
+// returnItem puts an item that will not be moved back into r's input bin, so
+// r is not left holding it.
+func (fs *fulfillmentService) returnItem(r *poolRobot, resp *gen.SelectItemResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), returnItemTimeout)
+	defer cancel()
+
+	err := r.robot.ReturnItem(ctx, resp.PickToken)
+	if err != nil {
+		log.Println("Error while returning item with code ", resp.Item.Code, " occured: ", err.Error())
+		return
+	}
+
+	log.Println("Item with code ", resp.Item.Code, " was returned to the input bin.")
+}
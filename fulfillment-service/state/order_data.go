@@ -1,11 +1,29 @@
 package state
 
-import "github.com/Emoto13/sort-system/gen"
+import (
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+)
 
 type OrderData struct {
 	Id                     string
 	Items                  []*gen.Item
+	CustomerId             string
+	Priority               int32
+	ReadyByMillis          int64
+	PartialPolicy          gen.PartialPolicy
+	PartialTimeoutMillis   int64
 	Cubby                  *gen.Cubby
 	Status                 gen.OrderStatus
 	itemsFulfillmentStatus []ItemStatus
+	// AddedAt is when the order got its cubby.
+	AddedAt time.Time
+	// ReleasedAt is when the order's cubby was freed, zero while the order
+	// holds it.
+	ReleasedAt time.Time
+	// PartialDecision is what was decided for the order when it was still
+	// missing MissingItems at its partial timeout, see DecidePartial.
+	PartialDecision gen.PartialPolicy
+	MissingItems    []*gen.Item
 }
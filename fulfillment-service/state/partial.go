@@ -0,0 +1,84 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// DecidePartial applies decision to an order that is still missing items when
+// its partial timeout runs out, and returns the items it misses. A released
+// or cancelled order no longer waits for them, a held order goes on waiting.
+func (sm *state) DecidePartial(orderId string, decision gen.PartialPolicy) ([]*gen.Item, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	status, err := sm.getOrderStatus(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	data := sm.orderIdToData[orderId]
+	if status != gen.OrderStatus_PENDING || data.PartialDecision != gen.PartialPolicy_SYSTEM_POLICY {
+		return nil, fmt.Errorf("order %s is not waiting for items", orderId)
+	}
+
+	data.MissingItems = sm.waitingItems(data)
+	data.PartialDecision = decision
+	if decision != gen.PartialPolicy_HOLD_PARTIAL {
+		sm.stopWaitingForItems(data)
+	}
+	return data.MissingItems, nil
+}
+
+// waitingItems returns the items of an order no item was matched to yet.
+// Must be called with sm.mu held.
+func (sm *state) waitingItems(data *OrderData) []*gen.Item {
+	waiting := map[string]int{}
+	for _, item := range data.Items {
+		if _, ok := waiting[item.Code]; ok {
+			continue
+		}
+		waiting[item.Code] = 0
+		for _, orderCubby := range sm.itemCodeToOrderCubby[item.Code] {
+			if orderCubby.Order.Id == data.Id {
+				waiting[item.Code]++
+			}
+		}
+	}
+
+	items := []*gen.Item{}
+	for _, item := range data.Items {
+		if waiting[item.Code] > 0 {
+			items = append(items, item)
+			waiting[item.Code]--
+		}
+	}
+	return items
+}
+
+// stopWaitingForItems stops matching items to an order. Must be called with
+// sm.mu held.
+func (sm *state) stopWaitingForItems(data *OrderData) {
+	for _, item := range data.Items {
+		orderCubbies := []*OrderCubby{}
+		for _, orderCubby := range sm.itemCodeToOrderCubby[item.Code] {
+			if orderCubby.Order.Id != data.Id {
+				orderCubbies = append(orderCubbies, orderCubby)
+			}
+		}
+		sm.itemCodeToOrderCubby[item.Code] = orderCubbies
+	}
+}
+
+// partialStatus is the status of an order that is still missing items once
+// a decision was made for it at its partial timeout.
+func partialStatus(status gen.OrderStatus, decision gen.PartialPolicy) gen.OrderStatus {
+	if status != gen.OrderStatus_PENDING || decision == gen.PartialPolicy_SYSTEM_POLICY {
+		return status
+	}
+	if decision == gen.PartialPolicy_CANCEL_PARTIAL {
+		return gen.OrderStatus_CANCELLED
+	}
+	return gen.OrderStatus_PARTIALLY_READY
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecidePartial(t *testing.T) {
+	s := New(5)
+	items := []*gen.Item{{Code: "a"}, {Code: "b"}}
+	s.AddOrders([]*gen.Order{{Id: "released", Items: items}, {Id: "held", Items: items}})
+
+	orderCubby, _ := s.GetOrderCubbyByItemCode("a")
+	s.AddItemStatusForOrder(orderCubby.Order.Id, Ready)
+	orderCubby, _ = s.GetOrderCubbyByItemCode("a")
+	s.AddItemStatusForOrder(orderCubby.Order.Id, Ready)
+
+	missing, err := s.DecidePartial("released", gen.PartialPolicy_RELEASE_PARTIAL)
+	assert.Equal(t, err, nil, "Deciding for an order missing items should succeed")
+	assert.Equal(t, missing, []*gen.Item{{Code: "b"}}, "The items not sorted should be missing")
+	orderData, _ := s.GetOrderDataById("released")
+	assert.Equal(t, orderData.Status, gen.OrderStatus_PARTIALLY_READY, "The released order should be partially ready")
+
+	s.DecidePartial("held", gen.PartialPolicy_HOLD_PARTIAL)
+	orderData, _ = s.GetOrderDataById("held")
+	assert.Equal(t, orderData.Status, gen.OrderStatus_PARTIALLY_READY, "The held order should be partially ready")
+
+	orderCubby, err = s.GetOrderCubbyByItemCode("b")
+	assert.Equal(t, err, nil, "The held order should still wait for its item")
+	assert.Equal(t, orderCubby.Order.Id, "held", "The released order should no longer wait for its item")
+	s.AddItemStatusForOrder(orderCubby.Order.Id, Ready)
+
+	orderData, _ = s.GetOrderDataById("held")
+	assert.Equal(t, orderData.Status, gen.OrderStatus_READY, "The held order should be ready once it gets its item")
+	_, err = s.DecidePartial("held", gen.PartialPolicy_CANCEL_PARTIAL)
+	assert.NotEqual(t, err, nil, "An order that is ready should not be decided for")
+}
+
+func TestFailPendingOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision gen.PartialPolicy
+		status   gen.OrderStatus
+	}{
+		{"Pending", gen.PartialPolicy_SYSTEM_POLICY, gen.OrderStatus_FAILED},
+		{"Released", gen.PartialPolicy_RELEASE_PARTIAL, gen.OrderStatus_PARTIALLY_READY},
+		{"Held", gen.PartialPolicy_HOLD_PARTIAL, gen.OrderStatus_PARTIALLY_READY},
+		{"Cancelled", gen.PartialPolicy_CANCEL_PARTIAL, gen.OrderStatus_CANCELLED},
+	}
+
+	for _, test := range tests {
+		s := New(1)
+		s.AddOrders([]*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}})
+		if test.decision != gen.PartialPolicy_SYSTEM_POLICY {
+			s.DecidePartial("1", test.decision)
+		}
+
+		assert.Equal(t, s.FailPendingOrder("1"), nil, test.name+": failing the order should succeed")
+		orderData, _ := s.GetOrderDataById("1")
+		assert.Equal(t, orderData.Status, test.status, test.name+": the order should end "+test.status.String())
+	}
+
+	assert.NotEqual(t, New(1).FailPendingOrder("1"), nil, "Failing an order that does not exist should fail")
+}
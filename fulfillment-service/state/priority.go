@@ -0,0 +1,37 @@
+package state
+
+// servedBefore tells whether a should get an item before b when both wait
+// for it: orders of higher priority first, then the ones due earlier, and
+// orders without a deadline last. Orders that are alike are served in the
+// order they were added.
+func servedBefore(a, b *OrderCubby) bool {
+	if a.Order.Priority != b.Order.Priority {
+		return a.Order.Priority > b.Order.Priority
+	}
+
+	aReadyBy, bReadyBy := a.Order.ReadyByMillis, b.Order.ReadyByMillis
+	if aReadyBy == 0 || bReadyBy == 0 {
+		return aReadyBy != 0 && bReadyBy == 0
+	}
+	return aReadyBy < bReadyBy
+}
+
+// takeOrderCubby takes the order cubby that is served first among those
+// waiting for itemCode that match, if any. Must be called with sm.mu held.
+func (sm *state) takeOrderCubby(itemCode string, match func(*OrderCubby) bool) *OrderCubby {
+	orderCubbies := sm.itemCodeToOrderCubby[itemCode]
+
+	best := -1
+	for i, orderCubby := range orderCubbies {
+		if match(orderCubby) && (best < 0 || servedBefore(orderCubby, orderCubbies[best])) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+
+	orderCubby := orderCubbies[best]
+	sm.itemCodeToOrderCubby[itemCode] = append(orderCubbies[:best:best], orderCubbies[best+1:]...)
+	return orderCubby
+}
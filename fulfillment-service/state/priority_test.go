@@ -0,0 +1,28 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrderCubbyByItemCode_ServesUrgentOrdersFirst(t *testing.T) {
+	s := New(5)
+	item := []*gen.Item{{Code: "a"}}
+	s.AddOrders([]*gen.Order{
+		{Id: "no-deadline", Items: item},
+		{Id: "late", Items: item, ReadyByMillis: 2000},
+		{Id: "early", Items: item, ReadyByMillis: 1000},
+		{Id: "priority", Items: item, Priority: 1},
+		{Id: "also-no-deadline", Items: item},
+	})
+
+	served := []string{}
+	for range []int{1, 2, 3, 4, 5} {
+		orderCubby, err := s.GetOrderCubbyByItemCode("a")
+		assert.Equal(t, err, nil, "Every order should get the item")
+		served = append(served, orderCubby.Order.Id)
+	}
+	assert.Equal(t, served, []string{"priority", "early", "late", "no-deadline", "also-no-deadline"}, "Items should go to higher priority, then earlier deadline orders first")
+}
@@ -2,7 +2,10 @@ package state
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/Emoto13/sort-system/gen"
 	"github.com/preslavmihaylov/ordertocubby"
@@ -12,26 +15,50 @@ type State interface {
 	AddOrders(orders []*gen.Order)
 
 	GetOrderCubbyByItemCode(itemCode string) (*OrderCubby, error)
+	GetOrderCubbyByItemCodeOnWall(itemCode, wallId string) (*OrderCubby, error)
+	ReturnOrderCubby(itemCode string, orderCubby *OrderCubby)
 	GetOrderDataById(orderId string) (OrderData, error)
 	GetAllOrdersData() ([]OrderData, error)
 
 	AddItemStatusForOrder(orderId string, itemStatus ItemStatus) error
+	FailPendingOrder(orderId string) error
 	SetOrderStatus(orderId string, status gen.OrderStatus) error
-	Clear()
+	ReleaseCubbies()
+	ReleaseCubby(orderId string) error
+	EvictOrders(releasedBefore time.Time) []string
+	FreeCubbies() int
+	DecidePartial(orderId string, decision gen.PartialPolicy) ([]*gen.Item, error)
 }
 
 type state struct {
 	itemCodeToOrderCubby map[string][]*OrderCubby
 	cubbyIdToOrderId     map[string]string
 	orderIdToData        map[string]*OrderData
-	mu                   sync.RWMutex
+	walls                []Wall
+	routing              Routing
+	// wallLoad counts the taken cubbies of each wall.
+	wallLoad map[string]int
+	// customerIdToWallId is the wall a customer's orders are kept on.
+	customerIdToWallId map[string]string
+	mu                 sync.RWMutex
 }
 
-func New() State {
+// New returns the state of a single wall of numberOfCubbies cubbies.
+func New(numberOfCubbies int) State {
+	return NewWalls([]Wall{{Cubbies: numberOfCubbies}}, BalanceLoad)
+}
+
+// NewWalls returns the state of several walls, with orders routed to them
+// by routing.
+func NewWalls(walls []Wall, routing Routing) State {
 	return &state{
 		itemCodeToOrderCubby: make(map[string][]*OrderCubby),
 		cubbyIdToOrderId:     make(map[string]string),
 		orderIdToData:        make(map[string]*OrderData),
+		walls:                walls,
+		routing:              routing,
+		wallLoad:             make(map[string]int),
+		customerIdToWallId:   make(map[string]string),
 		mu:                   sync.RWMutex{},
 	}
 }
@@ -47,18 +74,20 @@ func (sm *state) doesOrderWithIdExist(orderId string) bool {
 	return ok
 }
 
-func (sm *state) getCubbyIdByOrderId(orderId string, times int) string {
-	cubbyId := ordertocubby.Map(orderId, uint32(times), 10)
+func (sm *state) getCubby(orderId string, times int, wall Wall) *gen.Cubby {
+	slot := ordertocubby.Map(orderId, uint32(times), uint32(wall.Cubbies))
 	attemptsToAvoidCollision := 1
 	for true {
-		if _, ok := sm.cubbyIdToOrderId[cubbyId]; !ok {
+		if _, ok := sm.cubbyIdToOrderId[cubbyId(wall.Id, slot)]; !ok {
 			break
 		}
 
-		cubbyId = ordertocubby.Map(orderId, uint32(times+attemptsToAvoidCollision), 10)
+		slot = ordertocubby.Map(orderId, uint32(times+attemptsToAvoidCollision), uint32(wall.Cubbies))
 		attemptsToAvoidCollision++
 	}
-	return cubbyId
+
+	slotNumber, _ := strconv.Atoi(slot)
+	return &gen.Cubby{Id: cubbyId(wall.Id, slot), WallId: wall.Id, Slot: int32(slotNumber)}
 }
 
 func (sm *state) getOrderStatus(orderId string) (gen.OrderStatus, error) {
@@ -79,10 +108,8 @@ func (sm *state) getOrderStatus(orderId string) (gen.OrderStatus, error) {
 		}
 	}
 
-	for _, itemStatus := range data.itemsFulfillmentStatus {
-		if itemStatus == Pending {
-			return gen.OrderStatus_PENDING, nil
-		}
+	if len(data.itemsFulfillmentStatus) < len(data.Items) {
+		return gen.OrderStatus_PENDING, nil
 	}
 	return gen.OrderStatus_READY, nil
 }
@@ -91,29 +118,57 @@ func (sm *state) AddOrders(orders []*gen.Order) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	now := time.Now()
 	for i, order := range orders {
-		cubbyId := sm.getCubbyIdByOrderId(order.Id, i)
-		sm.cubbyIdToOrderId[cubbyId] = order.Id
+		wall := sm.routeOrder(order)
+		cubby := sm.getCubby(order.Id, i, wall)
+		sm.cubbyIdToOrderId[cubby.Id] = order.Id
+		sm.wallLoad[wall.Id]++
+		if order.CustomerId != "" {
+			sm.customerIdToWallId[order.CustomerId] = wall.Id
+		}
 
-		cubby := &gen.Cubby{Id: cubbyId}
-		sm.orderIdToData[order.Id] = &OrderData{Id: order.Id, Items: order.Items, Cubby: cubby, Status: gen.OrderStatus_PENDING}
+		sm.orderIdToData[order.Id] = &OrderData{Id: order.Id, Items: order.Items, CustomerId: order.CustomerId, Priority: order.Priority, ReadyByMillis: order.ReadyByMillis, PartialPolicy: order.PartialPolicy, PartialTimeoutMillis: order.PartialTimeoutMillis, Cubby: cubby, Status: gen.OrderStatus_PENDING, AddedAt: now}
 		sm.mapItemCodesToOrderCubby(order.Items, order, cubby)
 	}
 }
 
 func (sm *state) GetOrderCubbyByItemCode(itemCode string) (*OrderCubby, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 
-	if len(sm.itemCodeToOrderCubby[itemCode]) == 0 {
+	orderCubby := sm.takeOrderCubby(itemCode, func(*OrderCubby) bool { return true })
+	if orderCubby == nil {
 		return nil, fmt.Errorf("item: " + itemCode + " was distributed to all necessary cubbies")
 	}
+	return orderCubby, nil
+}
+
+// GetOrderCubbyByItemCodeOnWall is GetOrderCubbyByItemCode for the robot of
+// one wall, which only sorts into the cubbies of that wall.
+func (sm *state) GetOrderCubbyByItemCodeOnWall(itemCode, wallId string) (*OrderCubby, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 
-	orderCubby := sm.itemCodeToOrderCubby[itemCode][0]
-	sm.itemCodeToOrderCubby[itemCode] = sm.itemCodeToOrderCubby[itemCode][1:]
+	orderCubby := sm.takeOrderCubby(itemCode, func(orderCubby *OrderCubby) bool {
+		return orderCubby.Cubby.WallId == wallId
+	})
+	if orderCubby == nil {
+		return nil, fmt.Errorf("item: " + itemCode + " was distributed to all necessary cubbies on wall " + wallId)
+	}
 	return orderCubby, nil
 }
 
+// ReturnOrderCubby gives back an order cubby taken with
+// GetOrderCubbyByItemCode for an item that was not put into it, so the order
+// still waits for the item.
+func (sm *state) ReturnOrderCubby(itemCode string, orderCubby *OrderCubby) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.itemCodeToOrderCubby[itemCode] = append([]*OrderCubby{orderCubby}, sm.itemCodeToOrderCubby[itemCode]...)
+}
+
 func (sm *state) GetOrderDataById(orderId string) (OrderData, error) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -121,15 +176,7 @@ func (sm *state) GetOrderDataById(orderId string) (OrderData, error) {
 	if !sm.doesOrderWithIdExist(orderId) {
 		return OrderData{}, fmt.Errorf("no order with such id: " + orderId)
 	}
-
-	status, err := sm.getOrderStatus(orderId)
-	if err != nil {
-		return OrderData{}, err
-	}
-
-	data := sm.orderIdToData[orderId]
-	data.Status = status
-	return *data, nil
+	return sm.getOrderData(orderId)
 }
 
 func (sm *state) GetAllOrdersData() ([]OrderData, error) {
@@ -137,8 +184,8 @@ func (sm *state) GetAllOrdersData() ([]OrderData, error) {
 	defer sm.mu.RUnlock()
 
 	orderDataSlice := []OrderData{}
-	for _, orderData := range sm.orderIdToData {
-		data, err := sm.GetOrderDataById(orderData.Id)
+	for orderId := range sm.orderIdToData {
+		data, err := sm.getOrderData(orderId)
 		if err != nil {
 			return nil, err
 		}
@@ -149,13 +196,85 @@ func (sm *state) GetAllOrdersData() ([]OrderData, error) {
 	return orderDataSlice, nil
 }
 
-func (sm *state) Clear() {
+// getOrderData returns the data of an existing order with its current
+// status. The caller holds sm.mu.
+func (sm *state) getOrderData(orderId string) (OrderData, error) {
+	status, err := sm.getOrderStatus(orderId)
+	if err != nil {
+		return OrderData{}, err
+	}
+
+	data := *sm.orderIdToData[orderId]
+	data.Status = partialStatus(status, data.PartialDecision)
+	return data, nil
+}
+
+// ReleaseCubbies frees every cubby for the next batch once the current one is
+// sorted. The batch's orders can still be looked up, so clients see how they
+// ended, until they are evicted.
+func (sm *state) ReleaseCubbies() {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	now := time.Now()
+	for _, orderId := range sm.cubbyIdToOrderId {
+		sm.orderIdToData[orderId].ReleasedAt = now
+	}
 	sm.itemCodeToOrderCubby = map[string][]*OrderCubby{}
 	sm.cubbyIdToOrderId = map[string]string{}
-	sm.orderIdToData = map[string]*OrderData{}
+	sm.wallLoad = map[string]int{}
+	sm.customerIdToWallId = map[string]string{}
+}
+
+// ReleaseCubby frees the cubby of an order that was picked up, which no
+// longer waits for the items it did not get.
+func (sm *state) ReleaseCubby(orderId string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if !sm.doesOrderWithIdExist(orderId) {
+		return fmt.Errorf("no order with such ID")
+	}
+
+	data := sm.orderIdToData[orderId]
+	if sm.cubbyIdToOrderId[data.Cubby.Id] != orderId {
+		return nil
+	}
+	delete(sm.cubbyIdToOrderId, data.Cubby.Id)
+	sm.wallLoad[data.Cubby.WallId]--
+	data.ReleasedAt = time.Now()
+
+	sm.stopWaitingForItems(data)
+	return nil
+}
+
+// EvictOrders forgets the orders whose cubby was freed before releasedBefore,
+// so they are no longer looked up, and returns their ids.
+func (sm *state) EvictOrders(releasedBefore time.Time) []string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	evicted := []string{}
+	for orderId, data := range sm.orderIdToData {
+		if !data.ReleasedAt.IsZero() && data.ReleasedAt.Before(releasedBefore) {
+			delete(sm.orderIdToData, orderId)
+			evicted = append(evicted, orderId)
+		}
+	}
+	sort.Strings(evicted)
+	return evicted
+}
+
+// FreeCubbies counts the cubbies no order is sorted into.
+func (sm *state) FreeCubbies() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	free := 0
+	for _, wall := range sm.walls {
+		free += wall.Cubbies
+	}
+	return free - len(sm.cubbyIdToOrderId)
 }
 
 func (sm *state) SetOrderStatus(orderId string, status gen.OrderStatus) error {
@@ -183,3 +302,26 @@ func (sm *state) AddItemStatusForOrder(orderId string, itemStatus ItemStatus) er
 	data.itemsFulfillmentStatus = append(data.itemsFulfillmentStatus, itemStatus)
 	return nil
 }
+
+// FailPendingOrder fails an order an item was picked for that could not be
+// sorted. An order that is no longer pending keeps its status, e.g. one that
+// was released or cancelled at its partial timeout.
+func (sm *state) FailPendingOrder(orderId string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if !sm.doesOrderWithIdExist(orderId) {
+		return fmt.Errorf("no order with such ID")
+	}
+
+	data, err := sm.getOrderData(orderId)
+	if err != nil {
+		return err
+	}
+	if data.Status != gen.OrderStatus_PENDING {
+		return nil
+	}
+
+	sm.orderIdToData[orderId].itemsFulfillmentStatus = append(sm.orderIdToData[orderId].itemsFulfillmentStatus, Failed)
+	return nil
+}
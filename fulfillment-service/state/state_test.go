@@ -0,0 +1,83 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseCubbies_KeepsOrdersOfTheBatch(t *testing.T) {
+	s := New(1)
+	s.AddOrders([]*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}})
+	assert.Equal(t, s.AddItemStatusForOrder("1", Ready), nil, "The item should be sorted into the order's cubby")
+
+	s.ReleaseCubbies()
+
+	orderData, err := s.GetOrderDataById("1")
+	assert.Equal(t, err, nil, "The order should still be looked up once its batch is done")
+	assert.Equal(t, orderData.Status, gen.OrderStatus_READY, "The order should keep the status it ended with")
+	_, err = s.GetOrderCubbyByItemCode("a")
+	assert.NotEqual(t, err, nil, "Items should no longer be matched to the order")
+
+	s.AddOrders([]*gen.Order{{Id: "2", Items: []*gen.Item{{Code: "a"}}}})
+	orderCubby, err := s.GetOrderCubbyByItemCode("a")
+	assert.Equal(t, err, nil, "The cubby should be free for the next batch")
+	assert.Equal(t, orderCubby.Order.Id, "2", "Items should be matched to the next batch's order")
+
+	orders, err := s.GetAllOrdersData()
+	assert.Equal(t, err, nil, "Every order should be listed")
+	assert.Equal(t, len(orders), 2, "Orders of finished batches should be listed with the current ones")
+}
+
+func TestEvictOrders(t *testing.T) {
+	s := New(10)
+	s.AddOrders([]*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "b"}}}})
+	assert.Equal(t, s.ReleaseCubby("1"), nil, "The order's cubby should be freed")
+	released := time.Now()
+
+	assert.Equal(t, s.EvictOrders(released.Add(-time.Minute)), []string{}, "An order released since should be kept")
+	assert.Equal(t, s.EvictOrders(released.Add(time.Minute)), []string{"1"}, "The order released before should be evicted")
+	_, err := s.GetOrderDataById("1")
+	assert.NotEqual(t, err, nil, "An evicted order should no longer be looked up")
+	_, err = s.GetOrderDataById("2")
+	assert.Equal(t, err, nil, "An order holding its cubby should never be evicted")
+
+	s.ReleaseCubbies()
+	assert.Equal(t, s.EvictOrders(time.Now().Add(time.Minute)), []string{"2"}, "The orders of a finished batch should be evicted")
+}
+
+func TestGetAllOrdersData_WhileCubbiesAreReleased(t *testing.T) {
+	s := New(10)
+	s.AddOrders([]*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "b"}}}})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	for i := 0; i < 4; i++ {
+		go func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					s.ReleaseCubbies()
+				}
+			}
+		}()
+	}
+
+	listed := make(chan struct{})
+	go func() {
+		for i := 0; i < 100000; i++ {
+			s.GetAllOrdersData()
+		}
+		close(listed)
+	}()
+
+	select {
+	case <-listed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("listing the orders did not finish while cubbies were released")
+	}
+}
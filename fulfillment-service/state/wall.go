@@ -0,0 +1,69 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// Wall is a wall of cubbies, served by its own robot.
+type Wall struct {
+	Id      string
+	Cubbies int
+}
+
+// Routing decides which wall an order is sorted on.
+type Routing string
+
+const (
+	// BalanceLoad sorts every order on the wall with the most free cubbies.
+	BalanceLoad Routing = "balance"
+	// KeepCustomerTogether sorts an order on the wall that already holds
+	// orders of the same customer while it has free cubbies, and balances
+	// load otherwise.
+	KeepCustomerTogether Routing = "customer"
+)
+
+// Routings lists the routing policies.
+var Routings = []Routing{BalanceLoad, KeepCustomerTogether}
+
+// ValidateRouting fails unless routing is one of Routings.
+func ValidateRouting(routing string) error {
+	for _, name := range Routings {
+		if Routing(routing) == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown wall routing %q, must be one of %v", routing, Routings)
+}
+
+// cubbyId names a cubby after its wall and slot. The cubbies of an unnamed
+// wall are named after their slot alone.
+func cubbyId(wallId, slot string) string {
+	if wallId == "" {
+		return slot
+	}
+	return wallId + "-" + slot
+}
+
+// routeOrder picks the wall order is sorted on. Must be called with sm.mu
+// held.
+func (sm *state) routeOrder(order *gen.Order) Wall {
+	if sm.routing == KeepCustomerTogether && order.CustomerId != "" {
+		if wallId, ok := sm.customerIdToWallId[order.CustomerId]; ok {
+			for _, wall := range sm.walls {
+				if wall.Id == wallId && sm.wallLoad[wall.Id] < wall.Cubbies {
+					return wall
+				}
+			}
+		}
+	}
+
+	best := sm.walls[0]
+	for _, wall := range sm.walls[1:] {
+		if wall.Cubbies-sm.wallLoad[wall.Id] > best.Cubbies-sm.wallLoad[best.Id] {
+			best = wall
+		}
+	}
+	return best
+}
@@ -0,0 +1,56 @@
+package state
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func wallOf(t *testing.T, s State, orderId string) string {
+	orderData, err := s.GetOrderDataById(orderId)
+	assert.Equal(t, err, nil, "The order should exist")
+	return orderData.Cubby.WallId
+}
+
+func TestAddOrders_BalancesLoadAcrossWalls(t *testing.T) {
+	s := NewWalls([]Wall{{Id: "A", Cubbies: 2}, {Id: "B", Cubbies: 3}}, BalanceLoad)
+
+	s.AddOrders([]*gen.Order{{Id: "1"}, {Id: "2"}, {Id: "3"}, {Id: "4"}})
+
+	walls := map[string]int{}
+	for _, orderId := range []string{"1", "2", "3", "4"} {
+		walls[wallOf(t, s, orderId)]++
+	}
+	assert.Equal(t, walls, map[string]int{"A": 2, "B": 2}, "Orders should go to the wall with the most free cubbies")
+
+	orderData, _ := s.GetOrderDataById("1")
+	assert.Equal(t, orderData.Cubby.Id, orderData.Cubby.WallId+"-"+strconv.Itoa(int(orderData.Cubby.Slot)), "The cubby should be named after its wall and slot")
+}
+
+func TestAddOrders_KeepsCustomerTogether(t *testing.T) {
+	s := NewWalls([]Wall{{Id: "A", Cubbies: 2}, {Id: "B", Cubbies: 2}}, KeepCustomerTogether)
+
+	s.AddOrders([]*gen.Order{{Id: "1", CustomerId: "alice"}, {Id: "2", CustomerId: "bob"}, {Id: "3", CustomerId: "alice"}, {Id: "4", CustomerId: "alice"}})
+
+	assert.Equal(t, wallOf(t, s, "3"), wallOf(t, s, "1"), "A customer's orders should be kept on one wall")
+	assert.NotEqual(t, wallOf(t, s, "2"), wallOf(t, s, "1"), "Another customer's order should balance the load")
+	assert.Equal(t, wallOf(t, s, "4"), wallOf(t, s, "2"), "An order should go to another wall once its customer's wall is full")
+}
+
+func TestGetOrderCubbyByItemCodeOnWall(t *testing.T) {
+	s := NewWalls([]Wall{{Id: "A", Cubbies: 1}, {Id: "B", Cubbies: 1}}, BalanceLoad)
+	s.AddOrders([]*gen.Order{{Id: "1", Items: []*gen.Item{{Code: "a"}}}, {Id: "2", Items: []*gen.Item{{Code: "a"}}}})
+
+	orderCubby, err := s.GetOrderCubbyByItemCodeOnWall("a", wallOf(t, s, "2"))
+	assert.Equal(t, err, nil, "The item should be matched on the wall")
+	assert.Equal(t, orderCubby.Order.Id, "2", "The item should be matched to the order on the wall")
+
+	_, err = s.GetOrderCubbyByItemCodeOnWall("a", wallOf(t, s, "2"))
+	assert.NotEqual(t, err, nil, "No other order on the wall should wait for the item")
+
+	orderCubby, err = s.GetOrderCubbyByItemCode("a")
+	assert.Equal(t, err, nil, "The item should still be matched on the other wall")
+	assert.Equal(t, orderCubby.Order.Id, "1", "The item should be matched to the order on the other wall")
+}
@@ -26,6 +26,14 @@ const (
 	OrderStatus_PENDING OrderStatus = 0
 	OrderStatus_READY   OrderStatus = 1
 	OrderStatus_FAILED  OrderStatus = 2
+	// BACKORDERED orders wait for their items to be loaded into the robots
+	// before they get a cubby.
+	OrderStatus_BACKORDERED OrderStatus = 3
+	// PARTIALLY_READY orders were still missing items when their partial
+	// timeout ran out, and were released as they are or are held.
+	OrderStatus_PARTIALLY_READY OrderStatus = 4
+	// CANCELLED orders were cancelled when their partial timeout ran out.
+	OrderStatus_CANCELLED OrderStatus = 5
 )
 
 // Enum value maps for OrderStatus.
@@ -34,11 +42,17 @@ var (
 		0: "PENDING",
 		1: "READY",
 		2: "FAILED",
+		3: "BACKORDERED",
+		4: "PARTIALLY_READY",
+		5: "CANCELLED",
 	}
 	OrderStatus_value = map[string]int32{
-		"PENDING": 0,
-		"READY":   1,
-		"FAILED":  2,
+		"PENDING":         0,
+		"READY":           1,
+		"FAILED":          2,
+		"BACKORDERED":     3,
+		"PARTIALLY_READY": 4,
+		"CANCELLED":       5,
 	}
 )
 
@@ -77,6 +91,12 @@ type FulfillmentStatus struct {
 	Cubby  *Cubby      `protobuf:"bytes,1,opt,name=cubby,proto3" json:"cubby,omitempty"`
 	Order  *Order      `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
 	Status OrderStatus `protobuf:"varint,3,opt,name=status,proto3,enum=fulfillment.OrderStatus" json:"status,omitempty"`
+	// missingItems are the items the order was missing when its partial
+	// timeout ran out.
+	MissingItems []*Item `protobuf:"bytes,4,rep,name=missingItems,proto3" json:"missingItems,omitempty"`
+	// partialDecision is what was decided for the order when its partial
+	// timeout ran out, or SYSTEM_POLICY while it has not.
+	PartialDecision PartialPolicy `protobuf:"varint,5,opt,name=partialDecision,proto3,enum=types.PartialPolicy" json:"partialDecision,omitempty"`
 }
 
 func (x *FulfillmentStatus) Reset() {
@@ -132,6 +152,20 @@ func (x *FulfillmentStatus) GetStatus() OrderStatus {
 	return OrderStatus_PENDING
 }
 
+func (x *FulfillmentStatus) GetMissingItems() []*Item {
+	if x != nil {
+		return x.MissingItems
+	}
+	return nil
+}
+
+func (x *FulfillmentStatus) GetPartialDecision() PartialPolicy {
+	if x != nil {
+		return x.PartialDecision
+	}
+	return PartialPolicy_SYSTEM_POLICY
+}
+
 type OrderIdRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -288,6 +322,12 @@ type CompleteResponse struct {
 
 	Status string           `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	Orders []*PreparedOrder `protobuf:"bytes,2,rep,name=orders,proto3" json:"orders,omitempty"`
+	// rejectedOrderIds are the orders that were not loaded as the robots do
+	// not hold their items.
+	RejectedOrderIds []string `protobuf:"bytes,3,rep,name=rejectedOrderIds,proto3" json:"rejectedOrderIds,omitempty"`
+	// backorderedOrderIds are the orders that wait for their items to be
+	// loaded into the robots.
+	BackorderedOrderIds []string `protobuf:"bytes,4,rep,name=backorderedOrderIds,proto3" json:"backorderedOrderIds,omitempty"`
 }
 
 func (x *CompleteResponse) Reset() {
@@ -336,6 +376,20 @@ func (x *CompleteResponse) GetOrders() []*PreparedOrder {
 	return nil
 }
 
+func (x *CompleteResponse) GetRejectedOrderIds() []string {
+	if x != nil {
+		return x.RejectedOrderIds
+	}
+	return nil
+}
+
+func (x *CompleteResponse) GetBackorderedOrderIds() []string {
+	if x != nil {
+		return x.BackorderedOrderIds
+	}
+	return nil
+}
+
 type LoadOrdersRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -383,12 +437,509 @@ func (x *LoadOrdersRequest) GetOrders() []*Order {
 	return nil
 }
 
+type ScanItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemCode string `protobuf:"bytes,1,opt,name=itemCode,proto3" json:"itemCode,omitempty"`
+}
+
+func (x *ScanItemRequest) Reset() {
+	*x = ScanItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScanItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanItemRequest) ProtoMessage() {}
+
+func (x *ScanItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanItemRequest.ProtoReflect.Descriptor instead.
+func (*ScanItemRequest) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScanItemRequest) GetItemCode() string {
+	if x != nil {
+		return x.ItemCode
+	}
+	return ""
+}
+
+type ScanItemResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cubby *Cubby `protobuf:"bytes,1,opt,name=cubby,proto3" json:"cubby,omitempty"`
+}
+
+func (x *ScanItemResponse) Reset() {
+	*x = ScanItemResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScanItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanItemResponse) ProtoMessage() {}
+
+func (x *ScanItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanItemResponse.ProtoReflect.Descriptor instead.
+func (*ScanItemResponse) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScanItemResponse) GetCubby() *Cubby {
+	if x != nil {
+		return x.Cubby
+	}
+	return nil
+}
+
+type ConfirmPutRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemCode string `protobuf:"bytes,1,opt,name=itemCode,proto3" json:"itemCode,omitempty"`
+	CubbyId  string `protobuf:"bytes,2,opt,name=cubbyId,proto3" json:"cubbyId,omitempty"`
+}
+
+func (x *ConfirmPutRequest) Reset() {
+	*x = ConfirmPutRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmPutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPutRequest) ProtoMessage() {}
+
+func (x *ConfirmPutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPutRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPutRequest) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConfirmPutRequest) GetItemCode() string {
+	if x != nil {
+		return x.ItemCode
+	}
+	return ""
+}
+
+func (x *ConfirmPutRequest) GetCubbyId() string {
+	if x != nil {
+		return x.CubbyId
+	}
+	return ""
+}
+
+type OrdersAtRiskRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// withinMillis is how soon an order must be due to be at risk. The
+	// service's default is used if it is 0.
+	WithinMillis int64 `protobuf:"varint,1,opt,name=withinMillis,proto3" json:"withinMillis,omitempty"`
+}
+
+func (x *OrdersAtRiskRequest) Reset() {
+	*x = OrdersAtRiskRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrdersAtRiskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdersAtRiskRequest) ProtoMessage() {}
+
+func (x *OrdersAtRiskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdersAtRiskRequest.ProtoReflect.Descriptor instead.
+func (*OrdersAtRiskRequest) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrdersAtRiskRequest) GetWithinMillis() int64 {
+	if x != nil {
+		return x.WithinMillis
+	}
+	return 0
+}
+
+type OrderAtRisk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FulfillmentStatus *FulfillmentStatus `protobuf:"bytes,1,opt,name=fulfillmentStatus,proto3" json:"fulfillmentStatus,omitempty"`
+	// millisLeft is how long until the order is due, negative if overdue.
+	MillisLeft int64 `protobuf:"varint,2,opt,name=millisLeft,proto3" json:"millisLeft,omitempty"`
+}
+
+func (x *OrderAtRisk) Reset() {
+	*x = OrderAtRisk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderAtRisk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderAtRisk) ProtoMessage() {}
+
+func (x *OrderAtRisk) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderAtRisk.ProtoReflect.Descriptor instead.
+func (*OrderAtRisk) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OrderAtRisk) GetFulfillmentStatus() *FulfillmentStatus {
+	if x != nil {
+		return x.FulfillmentStatus
+	}
+	return nil
+}
+
+func (x *OrderAtRisk) GetMillisLeft() int64 {
+	if x != nil {
+		return x.MillisLeft
+	}
+	return 0
+}
+
+type OrdersAtRiskResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Orders []*OrderAtRisk `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+}
+
+func (x *OrdersAtRiskResponse) Reset() {
+	*x = OrdersAtRiskResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrdersAtRiskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdersAtRiskResponse) ProtoMessage() {}
+
+func (x *OrdersAtRiskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdersAtRiskResponse.ProtoReflect.Descriptor instead.
+func (*OrdersAtRiskResponse) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OrdersAtRiskResponse) GetOrders() []*OrderAtRisk {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+// Reservation is the stock set aside for an order, less the items already
+// sorted into its cubby.
+type Reservation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrderId string        `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Items   []*StockLevel `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Reservation) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Reservation) GetItems() []*StockLevel {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Backorder is an order waiting for stock, with the items missing to admit it.
+type Backorder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrderId string        `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Missing []*StockLevel `protobuf:"bytes,2,rep,name=missing,proto3" json:"missing,omitempty"`
+	// sinceMillis is when the order was backordered, in milliseconds since
+	// the Unix epoch.
+	SinceMillis int64 `protobuf:"varint,3,opt,name=sinceMillis,proto3" json:"sinceMillis,omitempty"`
+	// ageMillis is how long the order has been waiting.
+	AgeMillis int64 `protobuf:"varint,4,opt,name=ageMillis,proto3" json:"ageMillis,omitempty"`
+}
+
+func (x *Backorder) Reset() {
+	*x = Backorder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Backorder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Backorder) ProtoMessage() {}
+
+func (x *Backorder) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Backorder.ProtoReflect.Descriptor instead.
+func (*Backorder) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Backorder) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Backorder) GetMissing() []*StockLevel {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+func (x *Backorder) GetSinceMillis() int64 {
+	if x != nil {
+		return x.SinceMillis
+	}
+	return 0
+}
+
+func (x *Backorder) GetAgeMillis() int64 {
+	if x != nil {
+		return x.AgeMillis
+	}
+	return 0
+}
+
+type ReservationsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reservations []*Reservation `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	Backorders   []*Backorder   `protobuf:"bytes,2,rep,name=backorders,proto3" json:"backorders,omitempty"`
+	// available is the stock that is not reserved.
+	Available []*StockLevel `protobuf:"bytes,3,rep,name=available,proto3" json:"available,omitempty"`
+}
+
+func (x *ReservationsResponse) Reset() {
+	*x = ReservationsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fulfillment_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReservationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationsResponse) ProtoMessage() {}
+
+func (x *ReservationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fulfillment_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationsResponse.ProtoReflect.Descriptor instead.
+func (*ReservationsResponse) Descriptor() ([]byte, []int) {
+	return file_fulfillment_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReservationsResponse) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+func (x *ReservationsResponse) GetBackorders() []*Backorder {
+	if x != nil {
+		return x.Backorders
+	}
+	return nil
+}
+
+func (x *ReservationsResponse) GetAvailable() []*StockLevel {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
 var File_fulfillment_proto protoreflect.FileDescriptor
 
 var file_fulfillment_proto_rawDesc = []byte{
 	0x0a, 0x11, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74,
-	0x1a, 0x0b, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8d, 0x01,
+	0x1a, 0x0b, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xfe, 0x01,
 	0x0a, 0x11, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61,
 	0x74, 0x75, 0x73, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x62, 0x62, 0x79,
@@ -397,35 +948,105 @@ var file_fulfillment_proto_rawDesc = []byte{
 	0x72, 0x64, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x30, 0x0a, 0x06, 0x73,
 	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x66, 0x75,
 	0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x2a, 0x0a,
-	0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x22, 0x64, 0x0a, 0x14, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x4c, 0x0a, 0x11, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66,
-	0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x46, 0x75, 0x6c, 0x66, 0x69,
-	0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x11, 0x66, 0x75,
-	0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
-	0x57, 0x0a, 0x0d, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72,
-	0x12, 0x22, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x05, 0x6f,
-	0x72, 0x64, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x62, 0x62,
-	0x79, 0x52, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x22, 0x5e, 0x0a, 0x10, 0x43, 0x6f, 0x6d, 0x70,
-	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65,
-	0x6e, 0x74, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72,
-	0x52, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x22, 0x39, 0x0a, 0x11, 0x4c, 0x6f, 0x61, 0x64,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2f, 0x0a,
+	0x0c, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x0c, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x3e,
+	0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0f, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x2a,
+	0x0a, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x22, 0x64, 0x0a, 0x14, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4c, 0x0a, 0x11, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x46, 0x75, 0x6c, 0x66,
+	0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x11, 0x66,
+	0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x22, 0x57, 0x0a, 0x0d, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x12, 0x22, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x05,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x62,
+	0x62, 0x79, 0x52, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x43, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c,
+	0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x52, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x30, 0x0a, 0x13, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x13, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22, 0x39, 0x0a, 0x11, 0x4c, 0x6f, 0x61, 0x64,
 	0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a,
 	0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
 	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x06, 0x6f, 0x72, 0x64,
-	0x65, 0x72, 0x73, 0x2a, 0x31, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12,
-	0x09, 0x0a, 0x05, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41,
-	0x49, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x32, 0xc9, 0x02, 0x0a, 0x0b, 0x46, 0x75, 0x6c, 0x66, 0x69,
+	0x65, 0x72, 0x73, 0x22, 0x2d, 0x0a, 0x0f, 0x53, 0x63, 0x61, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f,
+	0x64, 0x65, 0x22, 0x36, 0x0a, 0x10, 0x53, 0x63, 0x61, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75,
+	0x62, 0x62, 0x79, 0x52, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x22, 0x49, 0x0a, 0x11, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x75, 0x62, 0x62, 0x79, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x75,
+	0x62, 0x62, 0x79, 0x49, 0x64, 0x22, 0x39, 0x0a, 0x13, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x41,
+	0x74, 0x52, 0x69, 0x73, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x0c,
+	0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73,
+	0x22, 0x7b, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x41, 0x74, 0x52, 0x69, 0x73, 0x6b, 0x12,
+	0x4c, 0x0a, 0x11, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x75, 0x6c,
+	0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c,
+	0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x11, 0x66, 0x75, 0x6c, 0x66,
+	0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a,
+	0x0a, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x4c, 0x65, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x4c, 0x65, 0x66, 0x74, 0x22, 0x48, 0x0a,
+	0x14, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x41, 0x74, 0x52, 0x69, 0x73, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d,
+	0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x41, 0x74, 0x52, 0x69, 0x73, 0x6b, 0x52,
+	0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x22, 0x50, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x27, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x92, 0x01, 0x0a, 0x09, 0x42, 0x61,
+	0x63, 0x6b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x2b, 0x0a, 0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x20,
+	0x0a, 0x0b, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x67, 0x65, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x67, 0x65, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x22, 0xbd,
+	0x01, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x75, 0x6c, 0x66,
+	0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x2f, 0x0a,
+	0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x2a, 0x66,
+	0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a,
+	0x07, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x45,
+	0x41, 0x44, 0x59, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x41, 0x43, 0x4b, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x45, 0x44,
+	0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x41, 0x52, 0x54, 0x49, 0x41, 0x4c, 0x4c, 0x59, 0x5f,
+	0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x43, 0x45,
+	0x4c, 0x4c, 0x45, 0x44, 0x10, 0x05, 0x32, 0xea, 0x04, 0x0a, 0x0b, 0x46, 0x75, 0x6c, 0x66, 0x69,
 	0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x4b, 0x0a, 0x0a, 0x4c, 0x6f, 0x61, 0x64, 0x4f, 0x72,
 	0x64, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65,
 	0x6e, 0x74, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
@@ -446,9 +1067,28 @@ var file_fulfillment_proto_rawDesc = []byte{
 	0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x1b, 0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c,
 	0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x45, 0x6d, 0x6f, 0x74, 0x6f, 0x31, 0x33, 0x2f, 0x73, 0x6f, 0x72, 0x74, 0x2d, 0x73, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x2f, 0x67, 0x65, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x79, 0x12, 0x47, 0x0a, 0x08, 0x53, 0x63, 0x61, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1c,
+	0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x63, 0x61,
+	0x6e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x66,
+	0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x49,
+	0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0a, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x75, 0x74, 0x12, 0x1e, 0x2e, 0x66, 0x75, 0x6c, 0x66,
+	0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50,
+	0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x56, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x73, 0x41, 0x74, 0x52, 0x69, 0x73, 0x6b, 0x12, 0x20, 0x2e, 0x66, 0x75, 0x6c,
+	0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x41,
+	0x74, 0x52, 0x69, 0x73, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x66,
+	0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x73, 0x41, 0x74, 0x52, 0x69, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x42, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x21, 0x2e, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x52,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x45, 0x6d, 0x6f, 0x74, 0x6f, 0x31, 0x33, 0x2f, 0x73, 0x6f, 0x72, 0x74, 0x2d, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x67, 0x65, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -464,7 +1104,7 @@ func file_fulfillment_proto_rawDescGZIP() []byte {
 }
 
 var file_fulfillment_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_fulfillment_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_fulfillment_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_fulfillment_proto_goTypes = []interface{}{
 	(OrderStatus)(0),             // 0: fulfillment.OrderStatus
 	(*FulfillmentStatus)(nil),    // 1: fulfillment.FulfillmentStatus
@@ -473,32 +1113,62 @@ var file_fulfillment_proto_goTypes = []interface{}{
 	(*PreparedOrder)(nil),        // 4: fulfillment.PreparedOrder
 	(*CompleteResponse)(nil),     // 5: fulfillment.CompleteResponse
 	(*LoadOrdersRequest)(nil),    // 6: fulfillment.LoadOrdersRequest
-	(*Cubby)(nil),                // 7: types.Cubby
-	(*Order)(nil),                // 8: types.Order
-	(*Empty)(nil),                // 9: types.Empty
+	(*ScanItemRequest)(nil),      // 7: fulfillment.ScanItemRequest
+	(*ScanItemResponse)(nil),     // 8: fulfillment.ScanItemResponse
+	(*ConfirmPutRequest)(nil),    // 9: fulfillment.ConfirmPutRequest
+	(*OrdersAtRiskRequest)(nil),  // 10: fulfillment.OrdersAtRiskRequest
+	(*OrderAtRisk)(nil),          // 11: fulfillment.OrderAtRisk
+	(*OrdersAtRiskResponse)(nil), // 12: fulfillment.OrdersAtRiskResponse
+	(*Reservation)(nil),          // 13: fulfillment.Reservation
+	(*Backorder)(nil),            // 14: fulfillment.Backorder
+	(*ReservationsResponse)(nil), // 15: fulfillment.ReservationsResponse
+	(*Cubby)(nil),                // 16: types.Cubby
+	(*Order)(nil),                // 17: types.Order
+	(*Item)(nil),                 // 18: types.Item
+	(PartialPolicy)(0),           // 19: types.PartialPolicy
+	(*StockLevel)(nil),           // 20: types.StockLevel
+	(*Empty)(nil),                // 21: types.Empty
 }
 var file_fulfillment_proto_depIdxs = []int32{
-	7,  // 0: fulfillment.FulfillmentStatus.cubby:type_name -> types.Cubby
-	8,  // 1: fulfillment.FulfillmentStatus.order:type_name -> types.Order
+	16, // 0: fulfillment.FulfillmentStatus.cubby:type_name -> types.Cubby
+	17, // 1: fulfillment.FulfillmentStatus.order:type_name -> types.Order
 	0,  // 2: fulfillment.FulfillmentStatus.status:type_name -> fulfillment.OrderStatus
-	1,  // 3: fulfillment.OrdersStatusResponse.fulfillmentStatus:type_name -> fulfillment.FulfillmentStatus
-	8,  // 4: fulfillment.PreparedOrder.order:type_name -> types.Order
-	7,  // 5: fulfillment.PreparedOrder.cubby:type_name -> types.Cubby
-	4,  // 6: fulfillment.CompleteResponse.orders:type_name -> fulfillment.PreparedOrder
-	8,  // 7: fulfillment.LoadOrdersRequest.orders:type_name -> types.Order
-	6,  // 8: fulfillment.Fulfillment.LoadOrders:input_type -> fulfillment.LoadOrdersRequest
-	2,  // 9: fulfillment.Fulfillment.GetOrderFulfillmentStatusById:input_type -> fulfillment.OrderIdRequest
-	9,  // 10: fulfillment.Fulfillment.GetAllOrdersFulfillmentStatus:input_type -> types.Empty
-	2,  // 11: fulfillment.Fulfillment.MarkFulfilled:input_type -> fulfillment.OrderIdRequest
-	5,  // 12: fulfillment.Fulfillment.LoadOrders:output_type -> fulfillment.CompleteResponse
-	3,  // 13: fulfillment.Fulfillment.GetOrderFulfillmentStatusById:output_type -> fulfillment.OrdersStatusResponse
-	3,  // 14: fulfillment.Fulfillment.GetAllOrdersFulfillmentStatus:output_type -> fulfillment.OrdersStatusResponse
-	9,  // 15: fulfillment.Fulfillment.MarkFulfilled:output_type -> types.Empty
-	12, // [12:16] is the sub-list for method output_type
-	8,  // [8:12] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	18, // 3: fulfillment.FulfillmentStatus.missingItems:type_name -> types.Item
+	19, // 4: fulfillment.FulfillmentStatus.partialDecision:type_name -> types.PartialPolicy
+	1,  // 5: fulfillment.OrdersStatusResponse.fulfillmentStatus:type_name -> fulfillment.FulfillmentStatus
+	17, // 6: fulfillment.PreparedOrder.order:type_name -> types.Order
+	16, // 7: fulfillment.PreparedOrder.cubby:type_name -> types.Cubby
+	4,  // 8: fulfillment.CompleteResponse.orders:type_name -> fulfillment.PreparedOrder
+	17, // 9: fulfillment.LoadOrdersRequest.orders:type_name -> types.Order
+	16, // 10: fulfillment.ScanItemResponse.cubby:type_name -> types.Cubby
+	1,  // 11: fulfillment.OrderAtRisk.fulfillmentStatus:type_name -> fulfillment.FulfillmentStatus
+	11, // 12: fulfillment.OrdersAtRiskResponse.orders:type_name -> fulfillment.OrderAtRisk
+	20, // 13: fulfillment.Reservation.items:type_name -> types.StockLevel
+	20, // 14: fulfillment.Backorder.missing:type_name -> types.StockLevel
+	13, // 15: fulfillment.ReservationsResponse.reservations:type_name -> fulfillment.Reservation
+	14, // 16: fulfillment.ReservationsResponse.backorders:type_name -> fulfillment.Backorder
+	20, // 17: fulfillment.ReservationsResponse.available:type_name -> types.StockLevel
+	6,  // 18: fulfillment.Fulfillment.LoadOrders:input_type -> fulfillment.LoadOrdersRequest
+	2,  // 19: fulfillment.Fulfillment.GetOrderFulfillmentStatusById:input_type -> fulfillment.OrderIdRequest
+	21, // 20: fulfillment.Fulfillment.GetAllOrdersFulfillmentStatus:input_type -> types.Empty
+	2,  // 21: fulfillment.Fulfillment.MarkFulfilled:input_type -> fulfillment.OrderIdRequest
+	7,  // 22: fulfillment.Fulfillment.ScanItem:input_type -> fulfillment.ScanItemRequest
+	9,  // 23: fulfillment.Fulfillment.ConfirmPut:input_type -> fulfillment.ConfirmPutRequest
+	10, // 24: fulfillment.Fulfillment.GetOrdersAtRisk:input_type -> fulfillment.OrdersAtRiskRequest
+	21, // 25: fulfillment.Fulfillment.GetReservations:input_type -> types.Empty
+	5,  // 26: fulfillment.Fulfillment.LoadOrders:output_type -> fulfillment.CompleteResponse
+	3,  // 27: fulfillment.Fulfillment.GetOrderFulfillmentStatusById:output_type -> fulfillment.OrdersStatusResponse
+	3,  // 28: fulfillment.Fulfillment.GetAllOrdersFulfillmentStatus:output_type -> fulfillment.OrdersStatusResponse
+	21, // 29: fulfillment.Fulfillment.MarkFulfilled:output_type -> types.Empty
+	8,  // 30: fulfillment.Fulfillment.ScanItem:output_type -> fulfillment.ScanItemResponse
+	21, // 31: fulfillment.Fulfillment.ConfirmPut:output_type -> types.Empty
+	12, // 32: fulfillment.Fulfillment.GetOrdersAtRisk:output_type -> fulfillment.OrdersAtRiskResponse
+	15, // 33: fulfillment.Fulfillment.GetReservations:output_type -> fulfillment.ReservationsResponse
+	26, // [26:34] is the sub-list for method output_type
+	18, // [18:26] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_fulfillment_proto_init() }
@@ -580,6 +1250,114 @@ func file_fulfillment_proto_init() {
 				return nil
 			}
 		}
+		file_fulfillment_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScanItemRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScanItemResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmPutRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrdersAtRiskRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderAtRisk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrdersAtRiskResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Reservation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Backorder); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fulfillment_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReservationsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -587,7 +1365,7 @@ func file_fulfillment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_fulfillment_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
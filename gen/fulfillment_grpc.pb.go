@@ -23,6 +23,16 @@ type FulfillmentClient interface {
 	GetOrderFulfillmentStatusById(ctx context.Context, in *OrderIdRequest, opts ...grpc.CallOption) (*OrdersStatusResponse, error)
 	GetAllOrdersFulfillmentStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OrdersStatusResponse, error)
 	MarkFulfilled(ctx context.Context, in *OrderIdRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Manual sorting, for the manual robot driver: an operator scans an item
+	// and is told its cubby, then confirms putting it there.
+	ScanItem(ctx context.Context, in *ScanItemRequest, opts ...grpc.CallOption) (*ScanItemResponse, error)
+	ConfirmPut(ctx context.Context, in *ConfirmPutRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Lists the orders that are not ready and are due within the request's
+	// window or overdue, the most urgent first.
+	GetOrdersAtRisk(ctx context.Context, in *OrdersAtRiskRequest, opts ...grpc.CallOption) (*OrdersAtRiskResponse, error)
+	// Lists the robots' stock reserved for the orders admitted, the orders
+	// waiting for stock and the stock that is not reserved.
+	GetReservations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReservationsResponse, error)
 }
 
 type fulfillmentClient struct {
@@ -69,6 +79,42 @@ func (c *fulfillmentClient) MarkFulfilled(ctx context.Context, in *OrderIdReques
 	return out, nil
 }
 
+func (c *fulfillmentClient) ScanItem(ctx context.Context, in *ScanItemRequest, opts ...grpc.CallOption) (*ScanItemResponse, error) {
+	out := new(ScanItemResponse)
+	err := c.cc.Invoke(ctx, "/fulfillment.Fulfillment/ScanItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fulfillmentClient) ConfirmPut(ctx context.Context, in *ConfirmPutRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/fulfillment.Fulfillment/ConfirmPut", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fulfillmentClient) GetOrdersAtRisk(ctx context.Context, in *OrdersAtRiskRequest, opts ...grpc.CallOption) (*OrdersAtRiskResponse, error) {
+	out := new(OrdersAtRiskResponse)
+	err := c.cc.Invoke(ctx, "/fulfillment.Fulfillment/GetOrdersAtRisk", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fulfillmentClient) GetReservations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReservationsResponse, error) {
+	out := new(ReservationsResponse)
+	err := c.cc.Invoke(ctx, "/fulfillment.Fulfillment/GetReservations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FulfillmentServer is the server API for Fulfillment service.
 // All implementations should embed UnimplementedFulfillmentServer
 // for forward compatibility
@@ -78,6 +124,16 @@ type FulfillmentServer interface {
 	GetOrderFulfillmentStatusById(context.Context, *OrderIdRequest) (*OrdersStatusResponse, error)
 	GetAllOrdersFulfillmentStatus(context.Context, *Empty) (*OrdersStatusResponse, error)
 	MarkFulfilled(context.Context, *OrderIdRequest) (*Empty, error)
+	// Manual sorting, for the manual robot driver: an operator scans an item
+	// and is told its cubby, then confirms putting it there.
+	ScanItem(context.Context, *ScanItemRequest) (*ScanItemResponse, error)
+	ConfirmPut(context.Context, *ConfirmPutRequest) (*Empty, error)
+	// Lists the orders that are not ready and are due within the request's
+	// window or overdue, the most urgent first.
+	GetOrdersAtRisk(context.Context, *OrdersAtRiskRequest) (*OrdersAtRiskResponse, error)
+	// Lists the robots' stock reserved for the orders admitted, the orders
+	// waiting for stock and the stock that is not reserved.
+	GetReservations(context.Context, *Empty) (*ReservationsResponse, error)
 }
 
 // UnimplementedFulfillmentServer should be embedded to have forward compatible implementations.
@@ -96,6 +152,18 @@ func (UnimplementedFulfillmentServer) GetAllOrdersFulfillmentStatus(context.Cont
 func (UnimplementedFulfillmentServer) MarkFulfilled(context.Context, *OrderIdRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MarkFulfilled not implemented")
 }
+func (UnimplementedFulfillmentServer) ScanItem(context.Context, *ScanItemRequest) (*ScanItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScanItem not implemented")
+}
+func (UnimplementedFulfillmentServer) ConfirmPut(context.Context, *ConfirmPutRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPut not implemented")
+}
+func (UnimplementedFulfillmentServer) GetOrdersAtRisk(context.Context, *OrdersAtRiskRequest) (*OrdersAtRiskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrdersAtRisk not implemented")
+}
+func (UnimplementedFulfillmentServer) GetReservations(context.Context, *Empty) (*ReservationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReservations not implemented")
+}
 
 // UnsafeFulfillmentServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to FulfillmentServer will
@@ -180,6 +248,78 @@ func _Fulfillment_MarkFulfilled_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Fulfillment_ScanItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FulfillmentServer).ScanItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fulfillment.Fulfillment/ScanItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FulfillmentServer).ScanItem(ctx, req.(*ScanItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fulfillment_ConfirmPut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FulfillmentServer).ConfirmPut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fulfillment.Fulfillment/ConfirmPut",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FulfillmentServer).ConfirmPut(ctx, req.(*ConfirmPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fulfillment_GetOrdersAtRisk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrdersAtRiskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FulfillmentServer).GetOrdersAtRisk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fulfillment.Fulfillment/GetOrdersAtRisk",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FulfillmentServer).GetOrdersAtRisk(ctx, req.(*OrdersAtRiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fulfillment_GetReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FulfillmentServer).GetReservations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fulfillment.Fulfillment/GetReservations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FulfillmentServer).GetReservations(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Fulfillment_ServiceDesc is the grpc.ServiceDesc for Fulfillment service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -203,6 +343,22 @@ var Fulfillment_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MarkFulfilled",
 			Handler:    _Fulfillment_MarkFulfilled_Handler,
 		},
+		{
+			MethodName: "ScanItem",
+			Handler:    _Fulfillment_ScanItem_Handler,
+		},
+		{
+			MethodName: "ConfirmPut",
+			Handler:    _Fulfillment_ConfirmPut_Handler,
+		},
+		{
+			MethodName: "GetOrdersAtRisk",
+			Handler:    _Fulfillment_GetOrdersAtRisk_Handler,
+		},
+		{
+			MethodName: "GetReservations",
+			Handler:    _Fulfillment_GetReservations_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "fulfillment.proto",
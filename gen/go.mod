@@ -1,3 +1,8 @@
 module github.com/Emoto13/sort-system/gen
 
 go 1.16
+
+require (
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.25.0
+)
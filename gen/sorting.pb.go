@@ -20,31 +20,682 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RobotState int32
+
+const (
+	RobotState_IDLE         RobotState = 0
+	RobotState_BUSY         RobotState = 1
+	RobotState_HOLDING_ITEM RobotState = 2
+	RobotState_FAULTED      RobotState = 3
+	RobotState_STOPPED      RobotState = 4
+)
+
+// Enum value maps for RobotState.
+var (
+	RobotState_name = map[int32]string{
+		0: "IDLE",
+		1: "BUSY",
+		2: "HOLDING_ITEM",
+		3: "FAULTED",
+		4: "STOPPED",
+	}
+	RobotState_value = map[string]int32{
+		"IDLE":         0,
+		"BUSY":         1,
+		"HOLDING_ITEM": 2,
+		"FAULTED":      3,
+		"STOPPED":      4,
+	}
+)
+
+func (x RobotState) Enum() *RobotState {
+	p := new(RobotState)
+	*p = x
+	return p
+}
+
+func (x RobotState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RobotState) Descriptor() protoreflect.EnumDescriptor {
+	return file_sorting_proto_enumTypes[0].Descriptor()
+}
+
+func (RobotState) Type() protoreflect.EnumType {
+	return &file_sorting_proto_enumTypes[0]
+}
+
+func (x RobotState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RobotState.Descriptor instead.
+func (RobotState) EnumDescriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{0}
+}
+
+type FaultType int32
+
+const (
+	FaultType_NO_FAULT     FaultType = 0
+	FaultType_DROP         FaultType = 1
+	FaultType_MIS_SORT     FaultType = 2
+	FaultType_JAM          FaultType = 3
+	FaultType_SCAN_FAILURE FaultType = 4
+)
+
+// Enum value maps for FaultType.
+var (
+	FaultType_name = map[int32]string{
+		0: "NO_FAULT",
+		1: "DROP",
+		2: "MIS_SORT",
+		3: "JAM",
+		4: "SCAN_FAILURE",
+	}
+	FaultType_value = map[string]int32{
+		"NO_FAULT":     0,
+		"DROP":         1,
+		"MIS_SORT":     2,
+		"JAM":          3,
+		"SCAN_FAILURE": 4,
+	}
+)
+
+func (x FaultType) Enum() *FaultType {
+	p := new(FaultType)
+	*p = x
+	return p
+}
+
+func (x FaultType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FaultType) Descriptor() protoreflect.EnumDescriptor {
+	return file_sorting_proto_enumTypes[1].Descriptor()
+}
+
+func (FaultType) Type() protoreflect.EnumType {
+	return &file_sorting_proto_enumTypes[1]
+}
+
+func (x FaultType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FaultType.Descriptor instead.
+func (FaultType) EnumDescriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{1}
+}
+
+type RobotEventType int32
+
+const (
+	RobotEventType_UNKNOWN_EVENT  RobotEventType = 0
+	RobotEventType_ITEMS_LOADED   RobotEventType = 1
+	RobotEventType_ITEM_SELECTED  RobotEventType = 2
+	RobotEventType_ITEM_MOVED     RobotEventType = 3
+	RobotEventType_ITEM_RETURNED  RobotEventType = 4
+	RobotEventType_ERROR          RobotEventType = 5
+	RobotEventType_FAULT          RobotEventType = 6
+	RobotEventType_FAULTS_CLEARED RobotEventType = 7
+	RobotEventType_ROBOT_STOPPED  RobotEventType = 8
+	RobotEventType_ROBOT_RESUMED  RobotEventType = 9
+)
+
+// Enum value maps for RobotEventType.
+var (
+	RobotEventType_name = map[int32]string{
+		0: "UNKNOWN_EVENT",
+		1: "ITEMS_LOADED",
+		2: "ITEM_SELECTED",
+		3: "ITEM_MOVED",
+		4: "ITEM_RETURNED",
+		5: "ERROR",
+		6: "FAULT",
+		7: "FAULTS_CLEARED",
+		8: "ROBOT_STOPPED",
+		9: "ROBOT_RESUMED",
+	}
+	RobotEventType_value = map[string]int32{
+		"UNKNOWN_EVENT":  0,
+		"ITEMS_LOADED":   1,
+		"ITEM_SELECTED":  2,
+		"ITEM_MOVED":     3,
+		"ITEM_RETURNED":  4,
+		"ERROR":          5,
+		"FAULT":          6,
+		"FAULTS_CLEARED": 7,
+		"ROBOT_STOPPED":  8,
+		"ROBOT_RESUMED":  9,
+	}
+)
+
+func (x RobotEventType) Enum() *RobotEventType {
+	p := new(RobotEventType)
+	*p = x
+	return p
+}
+
+func (x RobotEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RobotEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_sorting_proto_enumTypes[2].Descriptor()
+}
+
+func (RobotEventType) Type() protoreflect.EnumType {
+	return &file_sorting_proto_enumTypes[2]
+}
+
+func (x RobotEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RobotEventType.Descriptor instead.
+func (RobotEventType) EnumDescriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{2}
+}
+
 type LoadItemsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Items []*Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Items []*Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *LoadItemsRequest) Reset() {
+	*x = LoadItemsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadItemsRequest) ProtoMessage() {}
+
+func (x *LoadItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadItemsRequest.ProtoReflect.Descriptor instead.
+func (*LoadItemsRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoadItemsRequest) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type MoveItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cubby     *Cubby `protobuf:"bytes,1,opt,name=cubby,proto3" json:"cubby,omitempty"`
+	PickToken string `protobuf:"bytes,2,opt,name=pickToken,proto3" json:"pickToken,omitempty"`
+}
+
+func (x *MoveItemRequest) Reset() {
+	*x = MoveItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveItemRequest) ProtoMessage() {}
+
+func (x *MoveItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveItemRequest.ProtoReflect.Descriptor instead.
+func (*MoveItemRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MoveItemRequest) GetCubby() *Cubby {
+	if x != nil {
+		return x.Cubby
+	}
+	return nil
+}
+
+func (x *MoveItemRequest) GetPickToken() string {
+	if x != nil {
+		return x.PickToken
+	}
+	return ""
+}
+
+type SelectItemResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Item      *Item  `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	PickToken string `protobuf:"bytes,2,opt,name=pickToken,proto3" json:"pickToken,omitempty"`
+}
+
+func (x *SelectItemResponse) Reset() {
+	*x = SelectItemResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SelectItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectItemResponse) ProtoMessage() {}
+
+func (x *SelectItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectItemResponse.ProtoReflect.Descriptor instead.
+func (*SelectItemResponse) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SelectItemResponse) GetItem() *Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *SelectItemResponse) GetPickToken() string {
+	if x != nil {
+		return x.PickToken
+	}
+	return ""
+}
+
+type ReturnItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PickToken string `protobuf:"bytes,1,opt,name=pickToken,proto3" json:"pickToken,omitempty"`
+}
+
+func (x *ReturnItemRequest) Reset() {
+	*x = ReturnItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReturnItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnItemRequest) ProtoMessage() {}
+
+func (x *ReturnItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnItemRequest.ProtoReflect.Descriptor instead.
+func (*ReturnItemRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReturnItemRequest) GetPickToken() string {
+	if x != nil {
+		return x.PickToken
+	}
+	return ""
+}
+
+type AuditStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CubbiesToItems []*CubbyToItems `protobuf:"bytes,1,rep,name=cubbiesToItems,proto3" json:"cubbiesToItems,omitempty"`
+}
+
+func (x *AuditStateResponse) Reset() {
+	*x = AuditStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditStateResponse) ProtoMessage() {}
+
+func (x *AuditStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditStateResponse.ProtoReflect.Descriptor instead.
+func (*AuditStateResponse) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AuditStateResponse) GetCubbiesToItems() []*CubbyToItems {
+	if x != nil {
+		return x.CubbiesToItems
+	}
+	return nil
+}
+
+type CubbyToItems struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cubby *Cubby  `protobuf:"bytes,1,opt,name=cubby,proto3" json:"cubby,omitempty"`
+	Items []*Item `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *CubbyToItems) Reset() {
+	*x = CubbyToItems{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CubbyToItems) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CubbyToItems) ProtoMessage() {}
+
+func (x *CubbyToItems) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CubbyToItems.ProtoReflect.Descriptor instead.
+func (*CubbyToItems) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CubbyToItems) GetCubby() *Cubby {
+	if x != nil {
+		return x.Cubby
+	}
+	return nil
+}
+
+func (x *CubbyToItems) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type AcquireLeaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Holder    string `protobuf:"bytes,1,opt,name=holder,proto3" json:"holder,omitempty"`
+	TtlMillis int64  `protobuf:"varint,2,opt,name=ttlMillis,proto3" json:"ttlMillis,omitempty"`
+}
+
+func (x *AcquireLeaseRequest) Reset() {
+	*x = AcquireLeaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AcquireLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcquireLeaseRequest) ProtoMessage() {}
+
+func (x *AcquireLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcquireLeaseRequest.ProtoReflect.Descriptor instead.
+func (*AcquireLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AcquireLeaseRequest) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *AcquireLeaseRequest) GetTtlMillis() int64 {
+	if x != nil {
+		return x.TtlMillis
+	}
+	return 0
+}
+
+type LeaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LeaseId string `protobuf:"bytes,1,opt,name=leaseId,proto3" json:"leaseId,omitempty"`
+}
+
+func (x *LeaseRequest) Reset() {
+	*x = LeaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseRequest) ProtoMessage() {}
+
+func (x *LeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseRequest.ProtoReflect.Descriptor instead.
+func (*LeaseRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LeaseRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+type Lease struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LeaseId         string `protobuf:"bytes,1,opt,name=leaseId,proto3" json:"leaseId,omitempty"`
+	Holder          string `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	ExpiresAtMillis int64  `protobuf:"varint,3,opt,name=expiresAtMillis,proto3" json:"expiresAtMillis,omitempty"`
+}
+
+func (x *Lease) Reset() {
+	*x = Lease{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sorting_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Lease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lease) ProtoMessage() {}
+
+func (x *Lease) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lease.ProtoReflect.Descriptor instead.
+func (*Lease) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Lease) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *Lease) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *Lease) GetExpiresAtMillis() int64 {
+	if x != nil {
+		return x.ExpiresAtMillis
+	}
+	return 0
+}
+
+type InjectFaultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fault FaultType `protobuf:"varint,1,opt,name=fault,proto3,enum=FaultType" json:"fault,omitempty"`
 }
 
-func (x *LoadItemsRequest) Reset() {
-	*x = LoadItemsRequest{}
+func (x *InjectFaultRequest) Reset() {
+	*x = InjectFaultRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sorting_proto_msgTypes[0]
+		mi := &file_sorting_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LoadItemsRequest) String() string {
+func (x *InjectFaultRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadItemsRequest) ProtoMessage() {}
+func (*InjectFaultRequest) ProtoMessage() {}
 
-func (x *LoadItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sorting_proto_msgTypes[0]
+func (x *InjectFaultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,43 +706,52 @@ func (x *LoadItemsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadItemsRequest.ProtoReflect.Descriptor instead.
-func (*LoadItemsRequest) Descriptor() ([]byte, []int) {
-	return file_sorting_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use InjectFaultRequest.ProtoReflect.Descriptor instead.
+func (*InjectFaultRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *LoadItemsRequest) GetItems() []*Item {
+func (x *InjectFaultRequest) GetFault() FaultType {
 	if x != nil {
-		return x.Items
+		return x.Fault
 	}
-	return nil
+	return FaultType_NO_FAULT
 }
 
-type MoveItemRequest struct {
+type RobotStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cubby *Cubby `protobuf:"bytes,1,opt,name=cubby,proto3" json:"cubby,omitempty"`
+	State             RobotState `protobuf:"varint,1,opt,name=state,proto3,enum=RobotState" json:"state,omitempty"`
+	SelectedItem      *Item      `protobuf:"bytes,2,opt,name=selectedItem,proto3" json:"selectedItem,omitempty"`
+	ItemsInBin        int32      `protobuf:"varint,3,opt,name=itemsInBin,proto3" json:"itemsInBin,omitempty"`
+	ItemsPicked       int64      `protobuf:"varint,4,opt,name=itemsPicked,proto3" json:"itemsPicked,omitempty"`
+	ItemsPlaced       int64      `protobuf:"varint,5,opt,name=itemsPlaced,proto3" json:"itemsPlaced,omitempty"`
+	ItemsDropped      int64      `protobuf:"varint,6,opt,name=itemsDropped,proto3" json:"itemsDropped,omitempty"`
+	ItemsMisSorted    int64      `protobuf:"varint,7,opt,name=itemsMisSorted,proto3" json:"itemsMisSorted,omitempty"`
+	ScanFailures      int64      `protobuf:"varint,8,opt,name=scanFailures,proto3" json:"scanFailures,omitempty"`
+	SelectionTimeouts int64      `protobuf:"varint,9,opt,name=selectionTimeouts,proto3" json:"selectionTimeouts,omitempty"`
+	ItemsReturned     int64      `protobuf:"varint,10,opt,name=itemsReturned,proto3" json:"itemsReturned,omitempty"`
 }
 
-func (x *MoveItemRequest) Reset() {
-	*x = MoveItemRequest{}
+func (x *RobotStatus) Reset() {
+	*x = RobotStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sorting_proto_msgTypes[1]
+		mi := &file_sorting_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MoveItemRequest) String() string {
+func (x *RobotStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MoveItemRequest) ProtoMessage() {}
+func (*RobotStatus) ProtoMessage() {}
 
-func (x *MoveItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sorting_proto_msgTypes[1]
+func (x *RobotStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -102,43 +762,108 @@ func (x *MoveItemRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MoveItemRequest.ProtoReflect.Descriptor instead.
-func (*MoveItemRequest) Descriptor() ([]byte, []int) {
-	return file_sorting_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use RobotStatus.ProtoReflect.Descriptor instead.
+func (*RobotStatus) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *MoveItemRequest) GetCubby() *Cubby {
+func (x *RobotStatus) GetState() RobotState {
 	if x != nil {
-		return x.Cubby
+		return x.State
+	}
+	return RobotState_IDLE
+}
+
+func (x *RobotStatus) GetSelectedItem() *Item {
+	if x != nil {
+		return x.SelectedItem
 	}
 	return nil
 }
 
-type SelectItemResponse struct {
+func (x *RobotStatus) GetItemsInBin() int32 {
+	if x != nil {
+		return x.ItemsInBin
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetItemsPicked() int64 {
+	if x != nil {
+		return x.ItemsPicked
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetItemsPlaced() int64 {
+	if x != nil {
+		return x.ItemsPlaced
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetItemsDropped() int64 {
+	if x != nil {
+		return x.ItemsDropped
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetItemsMisSorted() int64 {
+	if x != nil {
+		return x.ItemsMisSorted
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetScanFailures() int64 {
+	if x != nil {
+		return x.ScanFailures
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetSelectionTimeouts() int64 {
+	if x != nil {
+		return x.SelectionTimeouts
+	}
+	return 0
+}
+
+func (x *RobotStatus) GetItemsReturned() int64 {
+	if x != nil {
+		return x.ItemsReturned
+	}
+	return 0
+}
+
+// InventoryResponse lists the items the robot holds that are not sorted yet,
+// in its input bin or its gripper, by code.
+type InventoryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Item *Item `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Items []*StockLevel `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 }
 
-func (x *SelectItemResponse) Reset() {
-	*x = SelectItemResponse{}
+func (x *InventoryResponse) Reset() {
+	*x = InventoryResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sorting_proto_msgTypes[2]
+		mi := &file_sorting_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SelectItemResponse) String() string {
+func (x *InventoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SelectItemResponse) ProtoMessage() {}
+func (*InventoryResponse) ProtoMessage() {}
 
-func (x *SelectItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sorting_proto_msgTypes[2]
+func (x *InventoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -149,43 +874,43 @@ func (x *SelectItemResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SelectItemResponse.ProtoReflect.Descriptor instead.
-func (*SelectItemResponse) Descriptor() ([]byte, []int) {
-	return file_sorting_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use InventoryResponse.ProtoReflect.Descriptor instead.
+func (*InventoryResponse) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *SelectItemResponse) GetItem() *Item {
+func (x *InventoryResponse) GetItems() []*StockLevel {
 	if x != nil {
-		return x.Item
+		return x.Items
 	}
 	return nil
 }
 
-type AuditStateResponse struct {
+type WatchRobotEventsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CubbiesToItems []*CubbyToItems `protobuf:"bytes,1,rep,name=cubbiesToItems,proto3" json:"cubbiesToItems,omitempty"`
+	AfterSequence int64 `protobuf:"varint,1,opt,name=afterSequence,proto3" json:"afterSequence,omitempty"`
 }
 
-func (x *AuditStateResponse) Reset() {
-	*x = AuditStateResponse{}
+func (x *WatchRobotEventsRequest) Reset() {
+	*x = WatchRobotEventsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sorting_proto_msgTypes[3]
+		mi := &file_sorting_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuditStateResponse) String() string {
+func (x *WatchRobotEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuditStateResponse) ProtoMessage() {}
+func (*WatchRobotEventsRequest) ProtoMessage() {}
 
-func (x *AuditStateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sorting_proto_msgTypes[3]
+func (x *WatchRobotEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -196,44 +921,50 @@ func (x *AuditStateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuditStateResponse.ProtoReflect.Descriptor instead.
-func (*AuditStateResponse) Descriptor() ([]byte, []int) {
-	return file_sorting_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use WatchRobotEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchRobotEventsRequest) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *AuditStateResponse) GetCubbiesToItems() []*CubbyToItems {
+func (x *WatchRobotEventsRequest) GetAfterSequence() int64 {
 	if x != nil {
-		return x.CubbiesToItems
+		return x.AfterSequence
 	}
-	return nil
+	return 0
 }
 
-type CubbyToItems struct {
+type RobotEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cubby *Cubby  `protobuf:"bytes,1,opt,name=cubby,proto3" json:"cubby,omitempty"`
-	Items []*Item `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Sequence        int64          `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Type            RobotEventType `protobuf:"varint,2,opt,name=type,proto3,enum=RobotEventType" json:"type,omitempty"`
+	TimestampMillis int64          `protobuf:"varint,3,opt,name=timestampMillis,proto3" json:"timestampMillis,omitempty"`
+	Item            *Item          `protobuf:"bytes,4,opt,name=item,proto3" json:"item,omitempty"`
+	Cubby           *Cubby         `protobuf:"bytes,5,opt,name=cubby,proto3" json:"cubby,omitempty"`
+	ItemCount       int32          `protobuf:"varint,6,opt,name=itemCount,proto3" json:"itemCount,omitempty"`
+	Fault           FaultType      `protobuf:"varint,7,opt,name=fault,proto3,enum=FaultType" json:"fault,omitempty"`
+	Message         string         `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *CubbyToItems) Reset() {
-	*x = CubbyToItems{}
+func (x *RobotEvent) Reset() {
+	*x = RobotEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sorting_proto_msgTypes[4]
+		mi := &file_sorting_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CubbyToItems) String() string {
+func (x *RobotEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CubbyToItems) ProtoMessage() {}
+func (*RobotEvent) ProtoMessage() {}
 
-func (x *CubbyToItems) ProtoReflect() protoreflect.Message {
-	mi := &file_sorting_proto_msgTypes[4]
+func (x *RobotEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sorting_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,25 +975,67 @@ func (x *CubbyToItems) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CubbyToItems.ProtoReflect.Descriptor instead.
-func (*CubbyToItems) Descriptor() ([]byte, []int) {
-	return file_sorting_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use RobotEvent.ProtoReflect.Descriptor instead.
+func (*RobotEvent) Descriptor() ([]byte, []int) {
+	return file_sorting_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CubbyToItems) GetCubby() *Cubby {
+func (x *RobotEvent) GetSequence() int64 {
 	if x != nil {
-		return x.Cubby
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RobotEvent) GetType() RobotEventType {
+	if x != nil {
+		return x.Type
+	}
+	return RobotEventType_UNKNOWN_EVENT
+}
+
+func (x *RobotEvent) GetTimestampMillis() int64 {
+	if x != nil {
+		return x.TimestampMillis
+	}
+	return 0
+}
+
+func (x *RobotEvent) GetItem() *Item {
+	if x != nil {
+		return x.Item
 	}
 	return nil
 }
 
-func (x *CubbyToItems) GetItems() []*Item {
+func (x *RobotEvent) GetCubby() *Cubby {
 	if x != nil {
-		return x.Items
+		return x.Cubby
 	}
 	return nil
 }
 
+func (x *RobotEvent) GetItemCount() int32 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+func (x *RobotEvent) GetFault() FaultType {
+	if x != nil {
+		return x.Fault
+	}
+	return FaultType_NO_FAULT
+}
+
+func (x *RobotEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_sorting_proto protoreflect.FileDescriptor
 
 var file_sorting_proto_rawDesc = []byte{
@@ -271,40 +1044,169 @@ var file_sorting_proto_rawDesc = []byte{
 	0x4c, 0x6f, 0x61, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x21, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
 	0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74,
-	0x65, 0x6d, 0x73, 0x22, 0x35, 0x0a, 0x0f, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x65, 0x6d, 0x73, 0x22, 0x53, 0x0a, 0x0f, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75,
-	0x62, 0x62, 0x79, 0x52, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x22, 0x35, 0x0a, 0x12, 0x53, 0x65,
-	0x6c, 0x65, 0x63, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x1f, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
-	0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69, 0x74, 0x65,
-	0x6d, 0x22, 0x4b, 0x0a, 0x12, 0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0e, 0x63, 0x75, 0x62, 0x62, 0x69,
-	0x65, 0x73, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0d, 0x2e, 0x43, 0x75, 0x62, 0x62, 0x79, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x0e,
-	0x63, 0x75, 0x62, 0x62, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x55,
-	0x0a, 0x0c, 0x43, 0x75, 0x62, 0x62, 0x79, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x22,
-	0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x62, 0x62, 0x79, 0x52, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x69,
+	0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x69, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x53, 0x0a, 0x12, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f,
+	0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x12,
+	0x1c, 0x0a, 0x09, 0x70, 0x69, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x70, 0x69, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x31, 0x0a,
+	0x11, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x69, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x69, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x22, 0x4b, 0x0a, 0x12, 0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0e, 0x63, 0x75, 0x62, 0x62, 0x69, 0x65,
+	0x73, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x43, 0x75, 0x62, 0x62, 0x79, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x0e, 0x63,
+	0x75, 0x62, 0x62, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x55, 0x0a,
+	0x0c, 0x43, 0x75, 0x62, 0x62, 0x79, 0x54, 0x6f, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x22, 0x0a,
+	0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x62, 0x62, 0x79, 0x52, 0x05, 0x63, 0x75, 0x62, 0x62,
+	0x79, 0x12, 0x21, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x22, 0x4b, 0x0a, 0x13, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4c,
+	0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x74, 0x6c, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x74, 0x6c, 0x4d, 0x69, 0x6c, 0x6c, 0x69,
+	0x73, 0x22, 0x28, 0x0a, 0x0c, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x63, 0x0a, 0x05, 0x4c,
+	0x65, 0x61, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x41, 0x74, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73,
+	0x22, 0x36, 0x0a, 0x12, 0x49, 0x6e, 0x6a, 0x65, 0x63, 0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x05, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x22, 0x89, 0x03, 0x0a, 0x0b, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x0c, 0x73,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0c,
+	0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1e, 0x0a, 0x0a,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x42, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x42, 0x69, 0x6e, 0x12, 0x20, 0x0a, 0x0b,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x69, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x69, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x20,
+	0x0a, 0x0b, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x44, 0x72, 0x6f,
+	0x70, 0x70, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x4d, 0x69, 0x73,
+	0x53, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x4d, 0x69, 0x73, 0x53, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c,
+	0x73, 0x63, 0x61, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x73, 0x63, 0x61, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73,
+	0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x73, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x12, 0x24,
+	0x0a, 0x0d, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x74, 0x75,
+	0x72, 0x6e, 0x65, 0x64, 0x22, 0x3c, 0x0a, 0x11, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x22, 0x3f, 0x0a, 0x17, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a,
+	0x0d, 0x61, 0x66, 0x74, 0x65, 0x72, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x61, 0x66, 0x74, 0x65, 0x72, 0x53, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x22, 0x96, 0x02, 0x0a, 0x0a, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x23,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x52,
+	0x6f, 0x62, 0x6f, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x12, 0x1f, 0x0a,
+	0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x12, 0x22,
+	0x0a, 0x05, 0x63, 0x75, 0x62, 0x62, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
 	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x62, 0x62, 0x79, 0x52, 0x05, 0x63, 0x75, 0x62,
-	0x62, 0x79, 0x12, 0x21, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05,
-	0x69, 0x74, 0x65, 0x6d, 0x73, 0x32, 0xd0, 0x01, 0x0a, 0x0c, 0x53, 0x6f, 0x72, 0x74, 0x69, 0x6e,
-	0x67, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x2e, 0x0a, 0x09, 0x4c, 0x6f, 0x61, 0x64, 0x49, 0x74,
-	0x65, 0x6d, 0x73, 0x12, 0x11, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x08, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74,
-	0x65, 0x6d, 0x12, 0x10, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x0a, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x49, 0x74,
-	0x65, 0x6d, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x1a, 0x13, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x0a, 0x41, 0x75, 0x64, 0x69, 0x74,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x45, 0x6d, 0x6f, 0x74, 0x6f, 0x31, 0x33, 0x2f, 0x73,
-	0x6f, 0x72, 0x74, 0x2d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x67, 0x65, 0x6e, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x62, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x20, 0x0a, 0x05, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0a, 0x2e, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2a, 0x4c, 0x0a, 0x0a,
+	0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44,
+	0x4c, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x55, 0x53, 0x59, 0x10, 0x01, 0x12, 0x10,
+	0x0a, 0x0c, 0x48, 0x4f, 0x4c, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x49, 0x54, 0x45, 0x4d, 0x10, 0x02,
+	0x12, 0x0b, 0x0a, 0x07, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0b, 0x0a,
+	0x07, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x04, 0x2a, 0x4c, 0x0a, 0x09, 0x46, 0x61,
+	0x75, 0x6c, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x4f, 0x5f, 0x46, 0x41,
+	0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x52, 0x4f, 0x50, 0x10, 0x01, 0x12,
+	0x0c, 0x0a, 0x08, 0x4d, 0x49, 0x53, 0x5f, 0x53, 0x4f, 0x52, 0x54, 0x10, 0x02, 0x12, 0x07, 0x0a,
+	0x03, 0x4a, 0x41, 0x4d, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x43, 0x41, 0x4e, 0x5f, 0x46,
+	0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x10, 0x04, 0x2a, 0xbb, 0x01, 0x0a, 0x0e, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x00, 0x12, 0x10,
+	0x0a, 0x0c, 0x49, 0x54, 0x45, 0x4d, 0x53, 0x5f, 0x4c, 0x4f, 0x41, 0x44, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x11, 0x0a, 0x0d, 0x49, 0x54, 0x45, 0x4d, 0x5f, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x45,
+	0x44, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x49, 0x54, 0x45, 0x4d, 0x5f, 0x4d, 0x4f, 0x56, 0x45,
+	0x44, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x54, 0x45, 0x4d, 0x5f, 0x52, 0x45, 0x54, 0x55,
+	0x52, 0x4e, 0x45, 0x44, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10,
+	0x05, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e,
+	0x46, 0x41, 0x55, 0x4c, 0x54, 0x53, 0x5f, 0x43, 0x4c, 0x45, 0x41, 0x52, 0x45, 0x44, 0x10, 0x07,
+	0x12, 0x11, 0x0a, 0x0d, 0x52, 0x4f, 0x42, 0x4f, 0x54, 0x5f, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45,
+	0x44, 0x10, 0x08, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x4f, 0x42, 0x4f, 0x54, 0x5f, 0x52, 0x45, 0x53,
+	0x55, 0x4d, 0x45, 0x44, 0x10, 0x09, 0x32, 0xe3, 0x05, 0x0a, 0x0c, 0x53, 0x6f, 0x72, 0x74, 0x69,
+	0x6e, 0x67, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x2e, 0x0a, 0x09, 0x4c, 0x6f, 0x61, 0x64, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x12, 0x11, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x08, 0x4d, 0x6f, 0x76, 0x65, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x10, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x0a, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x13, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x52, 0x65, 0x74, 0x75,
+	0x72, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x2e, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x49,
+	0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x0a, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0c, 0x41,
+	0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x14, 0x2e, 0x41, 0x63,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x06, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x22, 0x00, 0x12, 0x25, 0x0a, 0x0a, 0x52,
+	0x65, 0x6e, 0x65, 0x77, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x0d, 0x2e, 0x4c, 0x65, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x06, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x2d, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61,
+	0x73, 0x65, 0x12, 0x0d, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x32, 0x0a, 0x0b, 0x49, 0x6e, 0x6a, 0x65, 0x63, 0x74, 0x46, 0x61, 0x75, 0x6c, 0x74,
+	0x12, 0x13, 0x2e, 0x49, 0x6e, 0x6a, 0x65, 0x63, 0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2a, 0x0a, 0x0a, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x46, 0x61,
+	0x75, 0x6c, 0x74, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x2e, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x0c, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
+	0x00, 0x12, 0x2d, 0x0a, 0x0d, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74,
+	0x6f, 0x70, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
+	0x12, 0x26, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x33, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x49,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x12, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x24, 0x5a, 0x22,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x45, 0x6d, 0x6f, 0x74, 0x6f,
+	0x31, 0x33, 0x2f, 0x73, 0x6f, 0x72, 0x74, 0x2d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x67,
+	0x65, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -319,37 +1221,81 @@ func file_sorting_proto_rawDescGZIP() []byte {
 	return file_sorting_proto_rawDescData
 }
 
-var file_sorting_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_sorting_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_sorting_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_sorting_proto_goTypes = []interface{}{
-	(*LoadItemsRequest)(nil),   // 0: LoadItemsRequest
-	(*MoveItemRequest)(nil),    // 1: MoveItemRequest
-	(*SelectItemResponse)(nil), // 2: SelectItemResponse
-	(*AuditStateResponse)(nil), // 3: AuditStateResponse
-	(*CubbyToItems)(nil),       // 4: CubbyToItems
-	(*Item)(nil),               // 5: types.Item
-	(*Cubby)(nil),              // 6: types.Cubby
-	(*Empty)(nil),              // 7: types.Empty
+	(RobotState)(0),                 // 0: RobotState
+	(FaultType)(0),                  // 1: FaultType
+	(RobotEventType)(0),             // 2: RobotEventType
+	(*LoadItemsRequest)(nil),        // 3: LoadItemsRequest
+	(*MoveItemRequest)(nil),         // 4: MoveItemRequest
+	(*SelectItemResponse)(nil),      // 5: SelectItemResponse
+	(*ReturnItemRequest)(nil),       // 6: ReturnItemRequest
+	(*AuditStateResponse)(nil),      // 7: AuditStateResponse
+	(*CubbyToItems)(nil),            // 8: CubbyToItems
+	(*AcquireLeaseRequest)(nil),     // 9: AcquireLeaseRequest
+	(*LeaseRequest)(nil),            // 10: LeaseRequest
+	(*Lease)(nil),                   // 11: Lease
+	(*InjectFaultRequest)(nil),      // 12: InjectFaultRequest
+	(*RobotStatus)(nil),             // 13: RobotStatus
+	(*InventoryResponse)(nil),       // 14: InventoryResponse
+	(*WatchRobotEventsRequest)(nil), // 15: WatchRobotEventsRequest
+	(*RobotEvent)(nil),              // 16: RobotEvent
+	(*Item)(nil),                    // 17: types.Item
+	(*Cubby)(nil),                   // 18: types.Cubby
+	(*StockLevel)(nil),              // 19: types.StockLevel
+	(*Empty)(nil),                   // 20: types.Empty
 }
 var file_sorting_proto_depIdxs = []int32{
-	5,  // 0: LoadItemsRequest.items:type_name -> types.Item
-	6,  // 1: MoveItemRequest.cubby:type_name -> types.Cubby
-	5,  // 2: SelectItemResponse.item:type_name -> types.Item
-	4,  // 3: AuditStateResponse.cubbiesToItems:type_name -> CubbyToItems
-	6,  // 4: CubbyToItems.cubby:type_name -> types.Cubby
-	5,  // 5: CubbyToItems.items:type_name -> types.Item
-	0,  // 6: SortingRobot.LoadItems:input_type -> LoadItemsRequest
-	1,  // 7: SortingRobot.MoveItem:input_type -> MoveItemRequest
-	7,  // 8: SortingRobot.SelectItem:input_type -> types.Empty
-	7,  // 9: SortingRobot.AuditState:input_type -> types.Empty
-	7,  // 10: SortingRobot.LoadItems:output_type -> types.Empty
-	7,  // 11: SortingRobot.MoveItem:output_type -> types.Empty
-	2,  // 12: SortingRobot.SelectItem:output_type -> SelectItemResponse
-	3,  // 13: SortingRobot.AuditState:output_type -> AuditStateResponse
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	17, // 0: LoadItemsRequest.items:type_name -> types.Item
+	18, // 1: MoveItemRequest.cubby:type_name -> types.Cubby
+	17, // 2: SelectItemResponse.item:type_name -> types.Item
+	8,  // 3: AuditStateResponse.cubbiesToItems:type_name -> CubbyToItems
+	18, // 4: CubbyToItems.cubby:type_name -> types.Cubby
+	17, // 5: CubbyToItems.items:type_name -> types.Item
+	1,  // 6: InjectFaultRequest.fault:type_name -> FaultType
+	0,  // 7: RobotStatus.state:type_name -> RobotState
+	17, // 8: RobotStatus.selectedItem:type_name -> types.Item
+	19, // 9: InventoryResponse.items:type_name -> types.StockLevel
+	2,  // 10: RobotEvent.type:type_name -> RobotEventType
+	17, // 11: RobotEvent.item:type_name -> types.Item
+	18, // 12: RobotEvent.cubby:type_name -> types.Cubby
+	1,  // 13: RobotEvent.fault:type_name -> FaultType
+	3,  // 14: SortingRobot.LoadItems:input_type -> LoadItemsRequest
+	4,  // 15: SortingRobot.MoveItem:input_type -> MoveItemRequest
+	20, // 16: SortingRobot.SelectItem:input_type -> types.Empty
+	6,  // 17: SortingRobot.ReturnItem:input_type -> ReturnItemRequest
+	20, // 18: SortingRobot.AuditState:input_type -> types.Empty
+	9,  // 19: SortingRobot.AcquireLease:input_type -> AcquireLeaseRequest
+	10, // 20: SortingRobot.RenewLease:input_type -> LeaseRequest
+	10, // 21: SortingRobot.ReleaseLease:input_type -> LeaseRequest
+	12, // 22: SortingRobot.InjectFault:input_type -> InjectFaultRequest
+	20, // 23: SortingRobot.ClearFault:input_type -> types.Empty
+	20, // 24: SortingRobot.GetRobotStatus:input_type -> types.Empty
+	20, // 25: SortingRobot.EmergencyStop:input_type -> types.Empty
+	20, // 26: SortingRobot.Resume:input_type -> types.Empty
+	15, // 27: SortingRobot.WatchRobotEvents:input_type -> WatchRobotEventsRequest
+	20, // 28: SortingRobot.ListInventory:input_type -> types.Empty
+	20, // 29: SortingRobot.LoadItems:output_type -> types.Empty
+	20, // 30: SortingRobot.MoveItem:output_type -> types.Empty
+	5,  // 31: SortingRobot.SelectItem:output_type -> SelectItemResponse
+	20, // 32: SortingRobot.ReturnItem:output_type -> types.Empty
+	7,  // 33: SortingRobot.AuditState:output_type -> AuditStateResponse
+	11, // 34: SortingRobot.AcquireLease:output_type -> Lease
+	11, // 35: SortingRobot.RenewLease:output_type -> Lease
+	20, // 36: SortingRobot.ReleaseLease:output_type -> types.Empty
+	20, // 37: SortingRobot.InjectFault:output_type -> types.Empty
+	20, // 38: SortingRobot.ClearFault:output_type -> types.Empty
+	13, // 39: SortingRobot.GetRobotStatus:output_type -> RobotStatus
+	20, // 40: SortingRobot.EmergencyStop:output_type -> types.Empty
+	20, // 41: SortingRobot.Resume:output_type -> types.Empty
+	16, // 42: SortingRobot.WatchRobotEvents:output_type -> RobotEvent
+	14, // 43: SortingRobot.ListInventory:output_type -> InventoryResponse
+	29, // [29:44] is the sub-list for method output_type
+	14, // [14:29] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_sorting_proto_init() }
@@ -396,7 +1342,7 @@ func file_sorting_proto_init() {
 			}
 		}
 		file_sorting_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuditStateResponse); i {
+			switch v := v.(*ReturnItemRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -408,6 +1354,18 @@ func file_sorting_proto_init() {
 			}
 		}
 		file_sorting_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CubbyToItems); i {
 			case 0:
 				return &v.state
@@ -419,19 +1377,116 @@ func file_sorting_proto_init() {
 				return nil
 			}
 		}
+		file_sorting_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcquireLeaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Lease); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InjectFaultRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InventoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRobotEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sorting_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_sorting_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      3,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_sorting_proto_goTypes,
 		DependencyIndexes: file_sorting_proto_depIdxs,
+		EnumInfos:         file_sorting_proto_enumTypes,
 		MessageInfos:      file_sorting_proto_msgTypes,
 	}.Build()
 	File_sorting_proto = out.File
@@ -21,7 +21,18 @@ type SortingRobotClient interface {
 	LoadItems(ctx context.Context, in *LoadItemsRequest, opts ...grpc.CallOption) (*Empty, error)
 	MoveItem(ctx context.Context, in *MoveItemRequest, opts ...grpc.CallOption) (*Empty, error)
 	SelectItem(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SelectItemResponse, error)
+	ReturnItem(ctx context.Context, in *ReturnItemRequest, opts ...grpc.CallOption) (*Empty, error)
 	AuditState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AuditStateResponse, error)
+	AcquireLease(ctx context.Context, in *AcquireLeaseRequest, opts ...grpc.CallOption) (*Lease, error)
+	RenewLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*Lease, error)
+	ReleaseLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*Empty, error)
+	InjectFault(ctx context.Context, in *InjectFaultRequest, opts ...grpc.CallOption) (*Empty, error)
+	ClearFault(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	GetRobotStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RobotStatus, error)
+	EmergencyStop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	Resume(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	WatchRobotEvents(ctx context.Context, in *WatchRobotEventsRequest, opts ...grpc.CallOption) (SortingRobot_WatchRobotEventsClient, error)
+	ListInventory(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InventoryResponse, error)
 }
 
 type sortingRobotClient struct {
@@ -59,6 +70,15 @@ func (c *sortingRobotClient) SelectItem(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
+func (c *sortingRobotClient) ReturnItem(ctx context.Context, in *ReturnItemRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/SortingRobot/ReturnItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *sortingRobotClient) AuditState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AuditStateResponse, error) {
 	out := new(AuditStateResponse)
 	err := c.cc.Invoke(ctx, "/SortingRobot/AuditState", in, out, opts...)
@@ -68,6 +88,119 @@ func (c *sortingRobotClient) AuditState(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
+func (c *sortingRobotClient) AcquireLease(ctx context.Context, in *AcquireLeaseRequest, opts ...grpc.CallOption) (*Lease, error) {
+	out := new(Lease)
+	err := c.cc.Invoke(ctx, "/SortingRobot/AcquireLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) RenewLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*Lease, error) {
+	out := new(Lease)
+	err := c.cc.Invoke(ctx, "/SortingRobot/RenewLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) ReleaseLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/SortingRobot/ReleaseLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) InjectFault(ctx context.Context, in *InjectFaultRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/SortingRobot/InjectFault", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) ClearFault(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/SortingRobot/ClearFault", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) GetRobotStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RobotStatus, error) {
+	out := new(RobotStatus)
+	err := c.cc.Invoke(ctx, "/SortingRobot/GetRobotStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) EmergencyStop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/SortingRobot/EmergencyStop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) Resume(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/SortingRobot/Resume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sortingRobotClient) WatchRobotEvents(ctx context.Context, in *WatchRobotEventsRequest, opts ...grpc.CallOption) (SortingRobot_WatchRobotEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SortingRobot_ServiceDesc.Streams[0], "/SortingRobot/WatchRobotEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sortingRobotWatchRobotEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SortingRobot_WatchRobotEventsClient interface {
+	Recv() (*RobotEvent, error)
+	grpc.ClientStream
+}
+
+type sortingRobotWatchRobotEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *sortingRobotWatchRobotEventsClient) Recv() (*RobotEvent, error) {
+	m := new(RobotEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sortingRobotClient) ListInventory(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InventoryResponse, error) {
+	out := new(InventoryResponse)
+	err := c.cc.Invoke(ctx, "/SortingRobot/ListInventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SortingRobotServer is the server API for SortingRobot service.
 // All implementations should embed UnimplementedSortingRobotServer
 // for forward compatibility
@@ -75,7 +208,18 @@ type SortingRobotServer interface {
 	LoadItems(context.Context, *LoadItemsRequest) (*Empty, error)
 	MoveItem(context.Context, *MoveItemRequest) (*Empty, error)
 	SelectItem(context.Context, *Empty) (*SelectItemResponse, error)
+	ReturnItem(context.Context, *ReturnItemRequest) (*Empty, error)
 	AuditState(context.Context, *Empty) (*AuditStateResponse, error)
+	AcquireLease(context.Context, *AcquireLeaseRequest) (*Lease, error)
+	RenewLease(context.Context, *LeaseRequest) (*Lease, error)
+	ReleaseLease(context.Context, *LeaseRequest) (*Empty, error)
+	InjectFault(context.Context, *InjectFaultRequest) (*Empty, error)
+	ClearFault(context.Context, *Empty) (*Empty, error)
+	GetRobotStatus(context.Context, *Empty) (*RobotStatus, error)
+	EmergencyStop(context.Context, *Empty) (*Empty, error)
+	Resume(context.Context, *Empty) (*Empty, error)
+	WatchRobotEvents(*WatchRobotEventsRequest, SortingRobot_WatchRobotEventsServer) error
+	ListInventory(context.Context, *Empty) (*InventoryResponse, error)
 }
 
 // UnimplementedSortingRobotServer should be embedded to have forward compatible implementations.
@@ -91,9 +235,42 @@ func (UnimplementedSortingRobotServer) MoveItem(context.Context, *MoveItemReques
 func (UnimplementedSortingRobotServer) SelectItem(context.Context, *Empty) (*SelectItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SelectItem not implemented")
 }
+func (UnimplementedSortingRobotServer) ReturnItem(context.Context, *ReturnItemRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnItem not implemented")
+}
 func (UnimplementedSortingRobotServer) AuditState(context.Context, *Empty) (*AuditStateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AuditState not implemented")
 }
+func (UnimplementedSortingRobotServer) AcquireLease(context.Context, *AcquireLeaseRequest) (*Lease, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcquireLease not implemented")
+}
+func (UnimplementedSortingRobotServer) RenewLease(context.Context, *LeaseRequest) (*Lease, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewLease not implemented")
+}
+func (UnimplementedSortingRobotServer) ReleaseLease(context.Context, *LeaseRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLease not implemented")
+}
+func (UnimplementedSortingRobotServer) InjectFault(context.Context, *InjectFaultRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectFault not implemented")
+}
+func (UnimplementedSortingRobotServer) ClearFault(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearFault not implemented")
+}
+func (UnimplementedSortingRobotServer) GetRobotStatus(context.Context, *Empty) (*RobotStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRobotStatus not implemented")
+}
+func (UnimplementedSortingRobotServer) EmergencyStop(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmergencyStop not implemented")
+}
+func (UnimplementedSortingRobotServer) Resume(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedSortingRobotServer) WatchRobotEvents(*WatchRobotEventsRequest, SortingRobot_WatchRobotEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRobotEvents not implemented")
+}
+func (UnimplementedSortingRobotServer) ListInventory(context.Context, *Empty) (*InventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInventory not implemented")
+}
 
 // UnsafeSortingRobotServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to SortingRobotServer will
@@ -160,6 +337,24 @@ func _SortingRobot_SelectItem_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SortingRobot_ReturnItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).ReturnItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/ReturnItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).ReturnItem(ctx, req.(*ReturnItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SortingRobot_AuditState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -178,6 +373,189 @@ func _SortingRobot_AuditState_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SortingRobot_AcquireLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).AcquireLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/AcquireLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).AcquireLease(ctx, req.(*AcquireLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_RenewLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).RenewLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/RenewLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).RenewLease(ctx, req.(*LeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_ReleaseLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).ReleaseLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/ReleaseLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).ReleaseLease(ctx, req.(*LeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_InjectFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectFaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).InjectFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/InjectFault",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).InjectFault(ctx, req.(*InjectFaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_ClearFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).ClearFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/ClearFault",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).ClearFault(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_GetRobotStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).GetRobotStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/GetRobotStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).GetRobotStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_EmergencyStop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).EmergencyStop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/EmergencyStop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).EmergencyStop(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/Resume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).Resume(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SortingRobot_WatchRobotEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRobotEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SortingRobotServer).WatchRobotEvents(m, &sortingRobotWatchRobotEventsServer{stream})
+}
+
+type SortingRobot_WatchRobotEventsServer interface {
+	Send(*RobotEvent) error
+	grpc.ServerStream
+}
+
+type sortingRobotWatchRobotEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *sortingRobotWatchRobotEventsServer) Send(m *RobotEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SortingRobot_ListInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SortingRobotServer).ListInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SortingRobot/ListInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SortingRobotServer).ListInventory(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SortingRobot_ServiceDesc is the grpc.ServiceDesc for SortingRobot service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -197,11 +575,57 @@ var SortingRobot_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SelectItem",
 			Handler:    _SortingRobot_SelectItem_Handler,
 		},
+		{
+			MethodName: "ReturnItem",
+			Handler:    _SortingRobot_ReturnItem_Handler,
+		},
 		{
 			MethodName: "AuditState",
 			Handler:    _SortingRobot_AuditState_Handler,
 		},
+		{
+			MethodName: "AcquireLease",
+			Handler:    _SortingRobot_AcquireLease_Handler,
+		},
+		{
+			MethodName: "RenewLease",
+			Handler:    _SortingRobot_RenewLease_Handler,
+		},
+		{
+			MethodName: "ReleaseLease",
+			Handler:    _SortingRobot_ReleaseLease_Handler,
+		},
+		{
+			MethodName: "InjectFault",
+			Handler:    _SortingRobot_InjectFault_Handler,
+		},
+		{
+			MethodName: "ClearFault",
+			Handler:    _SortingRobot_ClearFault_Handler,
+		},
+		{
+			MethodName: "GetRobotStatus",
+			Handler:    _SortingRobot_GetRobotStatus_Handler,
+		},
+		{
+			MethodName: "EmergencyStop",
+			Handler:    _SortingRobot_EmergencyStop_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _SortingRobot_Resume_Handler,
+		},
+		{
+			MethodName: "ListInventory",
+			Handler:    _SortingRobot_ListInventory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRobotEvents",
+			Handler:       _SortingRobot_WatchRobotEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "sorting.proto",
 }
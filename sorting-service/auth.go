@@ -0,0 +1,19 @@
+package main
+
+import "github.com/Emoto13/sort-system/common/auth"
+
+// robotPolicy lets only the fulfillment service drive the robot and only
+// operators clear faults or restart it. Items may be loaded by order intake
+// or by operators. Anyone authenticated may watch the robot or stop it.
+var robotPolicy = auth.Policy{
+	"/SortingRobot/LoadItems":    {auth.RoleIntake, auth.RoleOperator},
+	"/SortingRobot/SelectItem":   {auth.RoleFulfillment},
+	"/SortingRobot/MoveItem":     {auth.RoleFulfillment},
+	"/SortingRobot/ReturnItem":   {auth.RoleFulfillment},
+	"/SortingRobot/AcquireLease": {auth.RoleFulfillment},
+	"/SortingRobot/RenewLease":   {auth.RoleFulfillment},
+	"/SortingRobot/ReleaseLease": {auth.RoleFulfillment},
+	"/SortingRobot/InjectFault":  {auth.RoleOperator},
+	"/SortingRobot/ClearFault":   {auth.RoleOperator},
+	"/SortingRobot/Resume":       {auth.RoleOperator},
+}
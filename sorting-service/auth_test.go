@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Emoto13/sort-system/common/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// callAs calls method through the robot's authorization as the holder of a
+// token with roles, and reports whether the call was let through.
+func callAs(t *testing.T, roles []auth.Role, method string) error {
+	dir, err := ioutil.TempDir("", "auth")
+	assert.Equal(t, err, nil, "Creating an auth directory should succeed")
+	defer os.RemoveAll(dir)
+
+	signingKey := []byte("test signing key")
+	signingKeyFile := filepath.Join(dir, "auth.key")
+	assert.Equal(t, ioutil.WriteFile(signingKeyFile, signingKey, 0600), nil, "Writing the signing key should succeed")
+	authenticator, err := auth.Load(signingKeyFile, "")
+	assert.Equal(t, err, nil, "The signing key should load")
+
+	token, err := auth.Sign(signingKey, auth.Claims{Subject: "test", Roles: roles})
+	assert.Equal(t, err, nil, "The token should be signed")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	_, err = authenticator.UnaryServerInterceptor(robotPolicy)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return err
+}
+
+func TestRobotPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []auth.Role
+		method  string
+		allowed bool
+	}{
+		{"Fulfillment selects items", []auth.Role{auth.RoleFulfillment}, "/SortingRobot/SelectItem", true},
+		{"Fulfillment takes the lease", []auth.Role{auth.RoleFulfillment}, "/SortingRobot/AcquireLease", true},
+		{"Fulfillment clears faults", []auth.Role{auth.RoleFulfillment}, "/SortingRobot/ClearFault", false},
+		{"Operator clears faults", []auth.Role{auth.RoleOperator}, "/SortingRobot/ClearFault", true},
+		{"Operator moves items", []auth.Role{auth.RoleOperator}, "/SortingRobot/MoveItem", false},
+		{"Operator loads items", []auth.Role{auth.RoleOperator}, "/SortingRobot/LoadItems", true},
+		{"Intake loads items", []auth.Role{auth.RoleIntake}, "/SortingRobot/LoadItems", true},
+		{"Intake resumes the robot", []auth.Role{auth.RoleIntake}, "/SortingRobot/Resume", false},
+		{"Anyone stops the robot", []auth.Role{auth.RoleIntake}, "/SortingRobot/EmergencyStop", true},
+		{"Anyone reads the status", nil, "/SortingRobot/GetRobotStatus", true},
+	}
+
+	for _, test := range tests {
+		err := callAs(t, test.roles, test.method)
+		if test.allowed {
+			assert.Equal(t, err, nil, test.name+" should be allowed")
+		} else {
+			assert.Equal(t, status.Code(err), codes.PermissionDenied, test.name+" should be denied")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Command gencerts writes a local CA and certificates for both services into
+// a directory, for running them with mutual TLS during development:
+//
+//	go run ./cmd/gencerts -out=../certs
+//
+// Every certificate is valid for both serving and client authentication.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Emoto13/sort-system/common/devcerts"
+)
+
+var (
+	out      = flag.String("out", "certs", "directory the certificates are written to")
+	hosts    = flag.String("hosts", "localhost,127.0.0.1", "comma separated names and addresses the services are reached at")
+	services = flag.String("services", "sorting-service,fulfillment-service", "comma separated services to issue certificates for")
+)
+
+func main() {
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		log.Fatalf("failed to create %s: %v", *out, err)
+	}
+
+	authority, err := devcerts.NewAuthority("sort-system development CA")
+	if err != nil {
+		log.Fatalf("failed to create CA: %v", err)
+	}
+	write("ca.pem", authority.CertificatePEM, 0644)
+
+	for _, service := range strings.Split(*services, ",") {
+		certificate, key, err := authority.Issue(service, strings.Split(*hosts, ",")...)
+		if err != nil {
+			log.Fatalf("failed to issue certificate: %v", err)
+		}
+		write(service+".pem", certificate, 0644)
+		write(service+"-key.pem", key, 0600)
+	}
+}
+
+func write(name string, content []byte, perm os.FileMode) {
+	file := filepath.Join(*out, name)
+	if err := ioutil.WriteFile(file, content, perm); err != nil {
+		log.Fatalf("failed to write %s: %v", file, err)
+	}
+	fmt.Println("Wrote", file)
+}
@@ -1,37 +1,176 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"math/rand"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/Emoto13/sort-system/common/auth"
+	"github.com/Emoto13/sort-system/common/config"
+	"github.com/Emoto13/sort-system/common/tlsconfig"
 	"github.com/Emoto13/sort-system/gen"
+	"github.com/Emoto13/sort-system/sorting-service/sortingrobot"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-const serverPort = "localhost:10000"
+// envPrefix starts the names of the environment variables that configure the
+// service.
+const envPrefix = "SORTING_SERVICE_"
+
+var (
+	serverAddress    = flag.String("server-address", "localhost:10000", "address the sorting service listens on")
+	numberOfCubbies  = flag.Int("number-of-cubbies", sortingrobot.DefaultNumberOfCubbies, "number of cubbies on the wall")
+	selectionTimeout = flag.Duration("selection-timeout", sortingrobot.DefaultSelectionTimeout, "how long a selected item is held before it is returned to the input bin")
+	seed             = flag.Int64("seed", 0, "seed for picking items from the input bin, 0 seeds from the clock")
+	shutdownTimeout  = flag.Duration("shutdown-timeout", 10*time.Second, "how long in-flight calls may take to finish on shutdown")
+
+	faultDropRate       = flag.Float64("fault-drop-rate", 0, "probability that a moved item is dropped")
+	faultMisSortRate    = flag.Float64("fault-mis-sort-rate", 0, "probability that a moved item lands in the wrong cubby")
+	faultJamProbability = flag.Float64("fault-jam-probability", 0, "probability that the robot jams on an operation")
+	faultScanFailure    = flag.Float64("fault-scan-failure-probability", 0, "probability that a selected item cannot be scanned")
+	faultSeed           = flag.Int64("fault-seed", 1, "seed for the fault simulation")
+
+	pickTime           = flag.Duration("pick-time", 0, "time the arm takes to pick an item from the input bin")
+	travelTimePerCubby = flag.Duration("travel-time-per-cubby", 0, "time the arm takes to travel the distance of one cubby")
+	placementTime      = flag.Duration("placement-time", 0, "time the arm takes to put an item into a cubby")
+	timingJitter       = flag.Float64("timing-jitter", 0, "fraction by which operation times randomly vary")
+	timingSeed         = flag.Int64("timing-seed", 1, "seed for the timing jitter")
+
+	tlsCertFile     = flag.String("tls-cert-file", "", "certificate the server presents, enables TLS")
+	tlsKeyFile      = flag.String("tls-key-file", "", "private key of tls-cert-file")
+	tlsClientCAFile = flag.String("tls-client-ca-file", "", "CA that client certificates must be signed by, enables mutual TLS")
+
+	authSigningKeyFile = flag.String("auth-signing-key-file", "", "key that bearer tokens are signed with, enables authentication")
+	authKeysFile       = flag.String("auth-keys-file", "", "JSON file of static bearer tokens and their claims, enables authentication")
+)
 
 func main() {
+	if err := config.Load(envPrefix); err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+	config.Print(os.Stdout)
 	initServer()
 }
 
 func initServer() {
-	grpcServer, lis := newSortingServer()
+	grpcServer, lis, service := newSortingServer()
+
+	go func() {
+		fmt.Printf("gRPC server started. Listening on %s\n", *serverAddress)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	log.Println("Received", waitForSignal(), "shutting down.")
+	service.Shutdown()
+	stopServer(grpcServer, *shutdownTimeout)
+}
+
+func waitForSignal() os.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	return <-signals
+}
 
-	fmt.Printf("gRPC server started. Listening on %s\n", serverPort)
-	grpcServer.Serve(lis)
+// stopServer lets in-flight calls finish, cutting them off after timeout.
+func stopServer(grpcServer *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		log.Println("Timed out waiting for in-flight calls, stopping the server.")
+		grpcServer.Stop()
+	}
 }
 
-func newSortingServer() (*grpc.Server, net.Listener) {
-	lis, err := net.Listen("tcp", serverPort)
+func newSortingServer() (*grpc.Server, net.Listener, *sortingrobot.Robot) {
+	if *numberOfCubbies <= 0 {
+		log.Fatalf("number-of-cubbies must be positive, got %d", *numberOfCubbies)
+	}
+	if *selectionTimeout <= 0 {
+		log.Fatalf("selection-timeout must be positive, got %v", *selectionTimeout)
+	}
+	if *shutdownTimeout <= 0 {
+		log.Fatalf("shutdown-timeout must be positive, got %v", *shutdownTimeout)
+	}
+
+	tlsConfig, err := tlsconfig.Server(*tlsCertFile, *tlsKeyFile, *tlsClientCAFile)
+	if err != nil {
+		log.Fatalf("invalid TLS configuration: %v", err)
+	}
+
+	authenticator, err := auth.Load(*authSigningKeyFile, *authKeysFile)
+	if err != nil {
+		log.Fatalf("invalid authentication configuration: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", *serverAddress)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
-	gen.RegisterSortingRobotServer(grpcServer, newSortingService())
+	faults := sortingrobot.FaultModel{
+		DropRate:               *faultDropRate,
+		MisSortRate:            *faultMisSortRate,
+		JamProbability:         *faultJamProbability,
+		ScanFailureProbability: *faultScanFailure,
+		Seed:                   *faultSeed,
+	}
+	if err := faults.Validate(); err != nil {
+		log.Fatalf("invalid fault model: %v", err)
+	}
+
+	timing := sortingrobot.TimingModel{
+		PickTime:           *pickTime,
+		TravelTimePerCubby: *travelTimePerCubby,
+		PlacementTime:      *placementTime,
+		Jitter:             *timingJitter,
+		Seed:               *timingSeed,
+	}
+	if err := timing.Validate(); err != nil {
+		log.Fatalf("invalid timing model: %v", err)
+	}
+
+	healthServer := health.NewServer()
+	service := sortingrobot.New(&sortingrobot.Parameters{
+		NumberOfCubbies:  *numberOfCubbies,
+		SelectionTimeout: *selectionTimeout,
+		Faults:           faults,
+		Timing:           timing,
+		Health:           healthServer,
+	})
+	if *seed != 0 {
+		rand.Seed(*seed)
+	}
+
+	serverOptions := []grpc.ServerOption{
+		grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor(robotPolicy)),
+		grpc.StreamInterceptor(authenticator.StreamServerInterceptor(robotPolicy)),
+	}
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcServer := grpc.NewServer(serverOptions...)
+	gen.RegisterSortingRobotServer(grpcServer, service)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
 	reflection.Register(grpcServer)
 
-	return grpcServer, lis
+	return grpcServer, lis, service
 }
@@ -0,0 +1,137 @@
+package sortingrobot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventHistorySize is the minimum number of past events kept so that a
+// reconnecting watcher can resume without missing any.
+const eventHistorySize = 1000
+
+// eventLog numbers robot events and fans them out to watchers. Sequence
+// numbers start at 1 and have no gaps.
+type eventLog struct {
+	events       []*gen.RobotEvent
+	nextSequence int64
+	// changed is closed and replaced every time an event is published.
+	changed chan struct{}
+	// closed is closed when the service shuts down and watchers should leave.
+	closed    chan struct{}
+	closeOnce sync.Once
+	mu        sync.Mutex
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{
+		nextSequence: 1,
+		changed:      make(chan struct{}),
+		closed:       make(chan struct{}),
+		mu:           sync.Mutex{},
+	}
+}
+
+// close ends every watch once it has sent the events published so far.
+func (l *eventLog) close() {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+}
+
+func (l *eventLog) publish(event *gen.RobotEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.Sequence = l.nextSequence
+	event.TimestampMillis = time.Now().UnixNano() / int64(time.Millisecond)
+	l.nextSequence++
+
+	l.events = append(l.events, event)
+	if len(l.events) >= 2*eventHistorySize {
+		l.events = append([]*gen.RobotEvent{}, l.events[len(l.events)-eventHistorySize:]...)
+	}
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// since returns the events published after sequence afterSequence, together
+// with a channel that is closed once more are published. Zero means every
+// event still kept. Resuming from an event that is no longer kept, or from
+// one that has not happened yet, fails with OutOfRange.
+func (l *eventLog) since(afterSequence int64) ([]*gen.RobotEvent, <-chan struct{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if afterSequence >= l.nextSequence {
+		return nil, nil, status.Errorf(codes.OutOfRange, "event %d has not happened yet, the last event is %d", afterSequence, l.nextSequence-1)
+	}
+
+	if len(l.events) == 0 {
+		return nil, l.changed, nil
+	}
+
+	oldest := l.events[0].Sequence
+	if afterSequence > 0 && afterSequence < oldest-1 {
+		return nil, nil, status.Errorf(codes.OutOfRange, "events after %d are no longer kept, the oldest event is %d", afterSequence, oldest)
+	}
+
+	start := afterSequence - oldest + 1
+	if start < 0 {
+		start = 0
+	}
+	return append([]*gen.RobotEvent{}, l.events[start:]...), l.changed, nil
+}
+
+// watch sends every event after afterSequence, and then every new event as
+// it is published, until ctx is done, send fails or the log is closed.
+func (l *eventLog) watch(ctx context.Context, afterSequence int64, send func(*gen.RobotEvent) error) error {
+	for {
+		events, changed, err := l.since(afterSequence)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return err
+			}
+			afterSequence = event.Sequence
+		}
+
+		select {
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+		case <-l.closed:
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// publishError records a failed robot operation. It is deferred with a
+// pointer to the operation's error so every way the operation can fail is
+// reported.
+func (s *Robot) publishError(err *error) {
+	if *err != nil {
+		s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ERROR, Message: (*err).Error()})
+	}
+}
+
+// publishFault records a fault once it has taken effect on the robot.
+func (s *Robot) publishFault(fault gen.FaultType) {
+	s.updateHealth()
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_FAULT, Fault: fault, Item: s.SelectedItem})
+}
+
+// WatchRobotEvents streams what the robot does. A client that reconnects
+// passes the sequence number of the last event it received to carry on where
+// it stopped.
+func (s *Robot) WatchRobotEvents(in *gen.WatchRobotEventsRequest, stream gen.SortingRobot_WatchRobotEventsServer) error {
+	return s.events.watch(stream.Context(), in.AfterSequence, stream.Send)
+}
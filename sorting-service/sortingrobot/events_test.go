@@ -0,0 +1,146 @@
+package sortingrobot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeEventStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *gen.RobotEvent
+}
+
+func (f *fakeEventStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeEventStream) Send(event *gen.RobotEvent) error {
+	f.events <- event
+	return nil
+}
+
+// watchEvents starts watching the robot's events in the background.
+func watchEvents(t *testing.T, sorting_service *Robot, afterSequence int64) (chan *gen.RobotEvent, chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	stream := &fakeEventStream{ctx: ctx, events: make(chan *gen.RobotEvent, eventHistorySize)}
+	done := make(chan error, 1)
+	go func() {
+		done <- sorting_service.WatchRobotEvents(&gen.WatchRobotEventsRequest{AfterSequence: afterSequence}, stream)
+	}()
+
+	return stream.events, done
+}
+
+func nextEvent(t *testing.T, events chan *gen.RobotEvent) *gen.RobotEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a robot event")
+		return nil
+	}
+}
+
+func TestRobotEvents(t *testing.T) {
+	sorting_service := newRobot()
+	events, _ := watchEvents(t, sorting_service, 0)
+
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: []*gen.Item{testItem}})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	event := nextEvent(t, events)
+	assert.Equal(t, event.Type, gen.RobotEventType_ITEMS_LOADED, "Loading items should be reported")
+	assert.Equal(t, event.ItemCount, int32(1), "The loaded event should count the items")
+	assert.Equal(t, event.Sequence, int64(1), "Sequence numbers should start at 1")
+
+	event = nextEvent(t, events)
+	assert.Equal(t, event.Type, gen.RobotEventType_ITEM_SELECTED, "Selecting an item should be reported")
+	assert.Equal(t, event.Item.Code, testItem.Code, "The selected event should carry the item")
+
+	event = nextEvent(t, events)
+	assert.Equal(t, event.Type, gen.RobotEventType_ITEM_MOVED, "Moving an item should be reported")
+	assert.Equal(t, event.Cubby.Id, "1", "The moved event should carry the cubby")
+
+	event = nextEvent(t, events)
+	assert.Equal(t, event.Type, gen.RobotEventType_ERROR, "A failed operation should be reported")
+	assert.Equal(t, event.Sequence, int64(4), "Sequence numbers should have no gaps")
+}
+
+func TestFaultEvents(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	events, _ := watchEvents(t, sorting_service, 1)
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_SCAN_FAILURE})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	event := nextEvent(t, events)
+	assert.Equal(t, event.Type, gen.RobotEventType_FAULT, "A fault should be reported")
+	assert.Equal(t, event.Fault, gen.FaultType_SCAN_FAILURE, "The fault event should carry the fault type")
+	assert.Equal(t, nextEvent(t, events).Type, gen.RobotEventType_ERROR, "The failed selection should be reported")
+}
+
+func TestResumeWatchingEvents(t *testing.T) {
+	sorting_service := newRobot()
+	for i := 0; i < 3; i++ {
+		sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "TestItem"}}})
+	}
+
+	events, _ := watchEvents(t, sorting_service, 2)
+	assert.Equal(t, nextEvent(t, events).Sequence, int64(3), "Watching should resume after the given event")
+
+	sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+	event := nextEvent(t, events)
+	assert.Equal(t, event.Sequence, int64(4), "New events should follow the replayed ones")
+	assert.Equal(t, event.Type, gen.RobotEventType_ROBOT_STOPPED, "A stop should be reported")
+}
+
+func TestResumeFromEventsNoLongerKept(t *testing.T) {
+	sorting_service := newRobot()
+	for i := 0; i < 2*eventHistorySize; i++ {
+		sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{})
+	}
+
+	_, done := watchEvents(t, sorting_service, 1)
+	assert.Equal(t, status.Code(<-done), codes.OutOfRange, "Resuming from a forgotten event should fail")
+
+	_, done = watchEvents(t, sorting_service, 3*eventHistorySize)
+	assert.Equal(t, status.Code(<-done), codes.OutOfRange, "Resuming from a future event should fail")
+
+	events, _ := watchEvents(t, sorting_service, 2*eventHistorySize-1)
+	assert.Equal(t, nextEvent(t, events).Sequence, int64(2*eventHistorySize), "Resuming from a kept event should succeed")
+}
+
+func TestWatchingStopsWithTheClient(t *testing.T) {
+	sorting_service := newRobot()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeEventStream{ctx: ctx, events: make(chan *gen.RobotEvent, 1)}
+
+	done := make(chan error)
+	go func() {
+		done <- sorting_service.WatchRobotEvents(&gen.WatchRobotEventsRequest{}, stream)
+	}()
+	cancel()
+
+	assert.Equal(t, status.Code(<-done), codes.Canceled, "Watching should end when the client goes away")
+}
+
+func TestShutdownEndsWatches(t *testing.T) {
+	sorting_service := newRobot()
+	_, done := watchEvents(t, sorting_service, 0)
+
+	sorting_service.Shutdown()
+	assert.Equal(t, <-done, nil, "Watching should end when the service shuts down")
+}
@@ -0,0 +1,181 @@
+package sortingrobot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FaultModel describes how often the simulated robot fails. Every rate is the
+// probability, between 0 and 1, that a single operation hits the fault.
+type FaultModel struct {
+	DropRate               float64
+	MisSortRate            float64
+	JamProbability         float64
+	ScanFailureProbability float64
+	Seed                   int64
+}
+
+func (m FaultModel) Validate() error {
+	rates := map[string]float64{
+		"drop rate":                m.DropRate,
+		"mis-sort rate":            m.MisSortRate,
+		"jam probability":          m.JamProbability,
+		"scan failure probability": m.ScanFailureProbability,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+	return nil
+}
+
+type faultSimulator struct {
+	model    FaultModel
+	random   *rand.Rand
+	injected []gen.FaultType
+	jammed   bool
+	// notify is told about every fault as it happens.
+	notify func(gen.FaultType)
+}
+
+func newFaultSimulator(model FaultModel, notify func(gen.FaultType)) *faultSimulator {
+	return &faultSimulator{
+		model:  model,
+		random: rand.New(rand.NewSource(model.Seed)),
+		notify: notify,
+	}
+}
+
+// occurs reports whether the fault happens on this operation, either because
+// it was injected on demand or because the fault model rolled it.
+func (f *faultSimulator) occurs(fault gen.FaultType, probability float64) bool {
+	for i, injected := range f.injected {
+		if injected == fault {
+			f.injected = append(f.injected[:i], f.injected[i+1:]...)
+			return true
+		}
+	}
+
+	return probability > 0 && f.random.Float64() < probability
+}
+
+func (f *faultSimulator) checkJam() error {
+	if f.jammed {
+		return status.Error(codes.Unavailable, "robot is jammed")
+	}
+
+	if f.occurs(gen.FaultType_JAM, f.model.JamProbability) {
+		f.jammed = true
+		f.notify(gen.FaultType_JAM)
+		log.Println("Fault: robot jammed")
+		return status.Error(codes.Unavailable, "robot jammed")
+	}
+
+	return nil
+}
+
+func (f *faultSimulator) beforeSelect() error {
+	if err := f.checkJam(); err != nil {
+		return err
+	}
+
+	if f.occurs(gen.FaultType_SCAN_FAILURE, f.model.ScanFailureProbability) {
+		f.notify(gen.FaultType_SCAN_FAILURE)
+		log.Println("Fault: item scan failed")
+		return status.Error(codes.Aborted, "failed to scan item")
+	}
+
+	return nil
+}
+
+func (f *faultSimulator) beforeMove() error {
+	if err := f.checkJam(); err != nil {
+		return err
+	}
+
+	if f.occurs(gen.FaultType_DROP, f.model.DropRate) {
+		f.notify(gen.FaultType_DROP)
+		log.Println("Fault: item dropped")
+		return status.Error(codes.DataLoss, "item was dropped")
+	}
+
+	return nil
+}
+
+// destination returns the cubby the item actually lands in, which differs
+// from the requested one when the move is mis-sorted into another of the
+// numberOfCubbies cubbies of its wall.
+func (f *faultSimulator) destination(cubby *gen.Cubby, numberOfCubbies int) *gen.Cubby {
+	if numberOfCubbies < 2 {
+		return cubby
+	}
+
+	if !f.occurs(gen.FaultType_MIS_SORT, f.model.MisSortRate) {
+		return cubby
+	}
+
+	slot := f.random.Intn(numberOfCubbies) + 1
+	for slot == cubbyPosition(cubby) {
+		slot = f.random.Intn(numberOfCubbies) + 1
+	}
+
+	misSorted := wallCubby(cubby.WallId, slot)
+	f.notify(gen.FaultType_MIS_SORT)
+	log.Println("Fault: item for cubby", cubby.Id, "mis-sorted into cubby", misSorted.Id)
+	return misSorted
+}
+
+func (s *Robot) setFaultModel(model FaultModel) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.faults = newFaultSimulator(model, s.publishFault)
+}
+
+// InjectFault makes the next matching operation fail: SCAN_FAILURE affects
+// the next SelectItem, DROP and MIS_SORT the next MoveItem. JAM takes effect
+// immediately and lasts until ClearFault. Only the lease holder may inject
+// faults into the picks it makes.
+func (s *Robot) InjectFault(ctx context.Context, in *gen.InjectFaultRequest) (*gen.Empty, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.checkRunning(); err != nil {
+		return nil, err
+	}
+
+	switch in.Fault {
+	case gen.FaultType_NO_FAULT:
+		return nil, status.Error(codes.InvalidArgument, "fault type is required")
+	case gen.FaultType_JAM:
+		s.faults.jammed = true
+		s.publishFault(in.Fault)
+	default:
+		s.faults.injected = append(s.faults.injected, in.Fault)
+	}
+
+	log.Println("Injected fault:", in.Fault)
+	return &gen.Empty{}, nil
+}
+
+// ClearFault unjams the robot and drops any injected faults that have not
+// fired yet.
+func (s *Robot) ClearFault(ctx context.Context, in *gen.Empty) (*gen.Empty, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.faults.jammed = false
+	s.faults.injected = nil
+	s.updateHealth()
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_FAULTS_CLEARED})
+
+	log.Println("Faults cleared")
+	return &gen.Empty{}, nil
+}
@@ -0,0 +1,110 @@
+package sortingrobot
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func loadedSortingService(items ...*gen.Item) *Robot {
+	sorting_service := newRobot()
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	return sorting_service
+}
+
+func TestInjectedScanFailure(t *testing.T) {
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	sorting_service := loadedSortingService(testItem)
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_SCAN_FAILURE})
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.Aborted, "A scan failure should abort the selection")
+	assert.Equal(t, len(sorting_service.Items), 1, "The item should stay in the input bin")
+
+	_, err = sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "An injected fault should only fire once")
+}
+
+func TestInjectedDrop(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_DROP})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	assert.Equal(t, status.Code(err), codes.DataLoss, "A dropped item should fail the move")
+	assert.Nil(t, sorting_service.SelectedItem, "A dropped item should no longer be held")
+
+	audit, _ := sorting_service.AuditState(context.Background(), &gen.Empty{})
+	assert.Empty(t, audit.CubbiesToItems, "A dropped item should not reach any cubby")
+}
+
+func TestInjectedMisSort(t *testing.T) {
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	sorting_service := loadedSortingService(testItem)
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_MIS_SORT})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	assert.Equal(t, err, nil, "A mis-sort is not reported to the caller")
+
+	audit, _ := sorting_service.AuditState(context.Background(), &gen.Empty{})
+	assert.Len(t, audit.CubbiesToItems, 1)
+	assert.NotEqual(t, audit.CubbiesToItems[0].Cubby.Id, "1", "The item should land in another cubby")
+	assert.Equal(t, audit.CubbiesToItems[0].Items, []*gen.Item{testItem})
+}
+
+func TestMisSortStaysOnTheWall(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+
+		sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_MIS_SORT})
+		selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+		sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "A-2", WallId: "A", Slot: 2}, PickToken: selected.PickToken})
+
+		audit, _ := sorting_service.AuditState(context.Background(), &gen.Empty{})
+		var slot int
+		_, err := fmt.Sscanf(audit.CubbiesToItems[0].Cubby.Id, "A-%d", &slot)
+		assert.Equal(t, err, nil, "The item should land in a cubby of the wall it was meant for")
+		assert.NotEqual(t, slot, 2, "The item should land in another cubby")
+		assert.Equal(t, slot >= 1 && slot <= sorting_service.numberOfCubbies, true, "The item should land in a cubby the wall has")
+	}
+}
+
+func TestJamUntilCleared(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_JAM})
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "A jammed robot should not select items")
+	_, err = sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "The robot should stay jammed until cleared")
+
+	sorting_service.ClearFault(context.Background(), &gen.Empty{})
+	_, err = sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "A cleared robot should select items again")
+}
+
+func TestFaultModelIsReproducible(t *testing.T) {
+	model := FaultModel{ScanFailureProbability: 0.5, Seed: 42}
+	outcomes := func() []bool {
+		simulator := newFaultSimulator(model, func(gen.FaultType) {})
+		result := []bool{}
+		for i := 0; i < 20; i++ {
+			result = append(result, simulator.beforeSelect() != nil)
+		}
+		return result
+	}
+
+	assert.Equal(t, outcomes(), outcomes(), "The same seed should produce the same faults")
+}
+
+func TestFaultModelValidation(t *testing.T) {
+	assert.Equal(t, FaultModel{DropRate: 0.1}.Validate(), nil)
+	assert.NotEqual(t, FaultModel{DropRate: 1.5}.Validate(), nil, "Rates above 1 should be rejected")
+	assert.NotEqual(t, FaultModel{JamProbability: -0.1}.Validate(), nil, "Negative rates should be rejected")
+}
@@ -0,0 +1,22 @@
+package sortingrobot
+
+import (
+	"context"
+
+	"github.com/Emoto13/sort-system/common/inventory"
+	"github.com/Emoto13/sort-system/gen"
+)
+
+// ListInventory counts the items that are not sorted yet by code: those in
+// the input bin and the item in the gripper, which is put back if it is not
+// moved.
+func (s *Robot) ListInventory(ctx context.Context, in *gen.Empty) (*gen.InventoryResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	items := append([]*gen.Item{}, s.Items...)
+	if s.SelectedItem != nil {
+		items = append(items, s.SelectedItem)
+	}
+	return &gen.InventoryResponse{Items: inventory.Levels(inventory.Count(items))}, nil
+}
@@ -0,0 +1,120 @@
+package sortingrobot
+
+import (
+	"context"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/token"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// leaseMetadataKey is the request header the lease holder uses to
+	// identify itself on SelectItem and MoveItem calls.
+	leaseMetadataKey = "lease-id"
+
+	defaultLeaseTTL = 10 * time.Second
+	maxLeaseTTL     = time.Minute
+)
+
+type controllerLease struct {
+	id        string
+	holder    string
+	ttl       time.Duration
+	expiresAt time.Time
+}
+
+func (l *controllerLease) isActive(now time.Time) bool {
+	return l != nil && now.Before(l.expiresAt)
+}
+
+func (l *controllerLease) toProto() *gen.Lease {
+	return &gen.Lease{LeaseId: l.id, Holder: l.holder, ExpiresAtMillis: l.expiresAt.UnixNano() / int64(time.Millisecond)}
+}
+
+func leaseTTL(ttlMillis int64) time.Duration {
+	ttl := time.Duration(ttlMillis) * time.Millisecond
+	if ttl <= 0 {
+		return defaultLeaseTTL
+	}
+	if ttl > maxLeaseTTL {
+		return maxLeaseTTL
+	}
+	return ttl
+}
+
+// checkLease rejects the call if the robot is leased to a controller other
+// than the caller. While nobody holds the lease the robot can be driven by
+// anyone. It guards every call that moves the arm or changes how it moves,
+// except for the calls people at the robot make while a controller runs:
+// LoadItems, as intake restocks the input bin and backordered orders wait for
+// items loaded while they are sorted, EmergencyStop and Resume, as anyone
+// must be able to stop the robot, and InjectFault and ClearFault, as an
+// operator injects faults into a robot a controller drives and clears jams at
+// the robot. Who may make those is up to authorization. Must be called with
+// s.m held.
+func (s *Robot) checkLease(ctx context.Context) error {
+	if !s.lease.isActive(time.Now()) {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	leaseIds := md.Get(leaseMetadataKey)
+	if len(leaseIds) == 0 || leaseIds[0] != s.lease.id {
+		return status.Errorf(codes.PermissionDenied, "robot is leased to %s", s.lease.holder)
+	}
+
+	return nil
+}
+
+func (s *Robot) AcquireLease(ctx context.Context, in *gen.AcquireLeaseRequest) (*gen.Lease, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if in.Holder == "" {
+		return nil, status.Error(codes.InvalidArgument, "lease holder is required")
+	}
+
+	// The holder names itself, so an active lease is never handed out again,
+	// not even to a caller of the same name. Its holder renews it by its id.
+	now := time.Now()
+	if s.lease.isActive(now) {
+		return nil, status.Errorf(codes.FailedPrecondition, "robot is already leased to %s", s.lease.holder)
+	}
+
+	leaseId, err := token.New()
+	if err != nil {
+		return nil, err
+	}
+	ttl := leaseTTL(in.TtlMillis)
+	s.lease = &controllerLease{id: leaseId, holder: in.Holder, ttl: ttl, expiresAt: now.Add(ttl)}
+	return s.lease.toProto(), nil
+}
+
+func (s *Robot) RenewLease(ctx context.Context, in *gen.LeaseRequest) (*gen.Lease, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	now := time.Now()
+	if !s.lease.isActive(now) || s.lease.id != in.LeaseId {
+		return nil, status.Error(codes.NotFound, "lease has expired or is held by another controller")
+	}
+
+	s.lease.expiresAt = now.Add(s.lease.ttl)
+	return s.lease.toProto(), nil
+}
+
+func (s *Robot) ReleaseLease(ctx context.Context, in *gen.LeaseRequest) (*gen.Empty, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.lease.isActive(time.Now()) || s.lease.id != in.LeaseId {
+		return nil, status.Error(codes.NotFound, "lease has expired or is held by another controller")
+	}
+
+	s.lease = nil
+	return &gen.Empty{}, nil
+}
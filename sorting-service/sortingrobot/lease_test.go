@@ -0,0 +1,120 @@
+package sortingrobot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withLease(lease *gen.Lease) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(leaseMetadataKey, lease.LeaseId))
+}
+
+func TestAcquireLease(t *testing.T) {
+	sorting_service := newRobot()
+
+	lease, err := sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-1"})
+	assert.Equal(t, err, nil, "There should be no error")
+	assert.NotEqual(t, lease.LeaseId, "", "The lease should have an id")
+
+	_, err = sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-2"})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "A second controller should not be able to acquire a held lease")
+
+	_, err = sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-1"})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "A caller naming the holder should not get the held lease")
+
+	renewed, err := sorting_service.RenewLease(context.Background(), &gen.LeaseRequest{LeaseId: lease.LeaseId})
+	assert.Equal(t, err, nil, "The holder should renew the lease by its id")
+	assert.Equal(t, renewed.LeaseId, lease.LeaseId, "Renewing should keep the lease")
+}
+
+func TestMutatingCallsRequireLease(t *testing.T) {
+	sorting_service := newRobot()
+	items := []*gen.Item{&gen.Item{Code: "TestItem", Label: "TestItem"}}
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+
+	lease, _ := sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-1"})
+
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.NotEqual(t, err, nil, "SelectItem without the lease should be rejected")
+
+	selected, err := sorting_service.SelectItem(withLease(lease), &gen.Empty{})
+	assert.Equal(t, err, nil, "SelectItem from the lease holder should succeed")
+
+	_, err = sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: selected.PickToken})
+	assert.NotEqual(t, err, nil, "MoveItem without the lease should be rejected")
+
+	_, err = sorting_service.MoveItem(withLease(lease), &gen.MoveItemRequest{PickToken: selected.PickToken})
+	assert.Equal(t, err, nil, "MoveItem from the lease holder should succeed")
+}
+
+func TestLeaseExpiresWithoutHeartbeat(t *testing.T) {
+	sorting_service := newRobot()
+
+	lease, _ := sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-1", TtlMillis: 20})
+	time.Sleep(10 * time.Millisecond)
+	_, err := sorting_service.RenewLease(context.Background(), &gen.LeaseRequest{LeaseId: lease.LeaseId})
+	assert.Equal(t, err, nil, "Renewing an active lease should succeed")
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = sorting_service.RenewLease(context.Background(), &gen.LeaseRequest{LeaseId: lease.LeaseId})
+	assert.NotEqual(t, err, nil, "Renewing an expired lease should fail")
+
+	_, err = sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-2"})
+	assert.Equal(t, err, nil, "Another controller should be able to take over an expired lease")
+}
+
+func TestReleaseLease(t *testing.T) {
+	sorting_service := newRobot()
+
+	lease, _ := sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-1"})
+	_, err := sorting_service.ReleaseLease(context.Background(), &gen.LeaseRequest{LeaseId: "foreign"})
+	assert.NotEqual(t, err, nil, "Only the holder should be able to release the lease")
+
+	_, err = sorting_service.ReleaseLease(context.Background(), &gen.LeaseRequest{LeaseId: lease.LeaseId})
+	assert.Equal(t, err, nil, "The holder should be able to release the lease")
+
+	_, err = sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-2"})
+	assert.Equal(t, err, nil, "A released lease should be available to other controllers")
+}
+
+func TestCallsExemptFromLease(t *testing.T) {
+	sorting_service := newRobot()
+	sorting_service.AcquireLease(context.Background(), &gen.AcquireLeaseRequest{Holder: "fulfillment-1"})
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"InjectFault", func() error {
+			_, err := sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_DROP})
+			return err
+		}},
+		{"LoadItems", func() error {
+			_, err := sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "TestItem"}}})
+			return err
+		}},
+		{"EmergencyStop", func() error {
+			_, err := sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+			return err
+		}},
+		{"ClearFault", func() error {
+			_, err := sorting_service.ClearFault(context.Background(), &gen.Empty{})
+			return err
+		}},
+		{"Resume", func() error {
+			_, err := sorting_service.Resume(context.Background(), &gen.Empty{})
+			return err
+		}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.call(), nil, test.name+" should not require the lease")
+	}
+}
@@ -0,0 +1,24 @@
+package sortingrobot
+
+import (
+	"time"
+
+	"google.golang.org/grpc/health"
+)
+
+type Parameters struct {
+	// NumberOfCubbies is the size of the wall, DefaultNumberOfCubbies if it
+	// is not set.
+	NumberOfCubbies int
+	// SelectionTimeout is how long a selected item is held before it is
+	// returned to the input bin, DefaultSelectionTimeout if it is not set.
+	SelectionTimeout time.Duration
+	// Faults is how often the robot fails. It never does if it is not set.
+	Faults FaultModel
+	// Timing is how long the robot's operations take. They are instant if it
+	// is not set.
+	Timing TimingModel
+	// Health, if set, reports the robot as not serving while it is stopped
+	// or jammed.
+	Health *health.Server
+}
@@ -0,0 +1,332 @@
+// Package sortingrobot simulates the sorting robot: an input bin that items
+// are loaded into, an arm that picks them one at a time and a wall of cubbies
+// it puts them in. The robot can be set to fail and to take time the way a
+// real one does, see FaultModel and TimingModel.
+package sortingrobot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Emoto13/sort-system/common/token"
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultSelectionTimeout is how long a selected item is held unless
+	// Parameters say otherwise.
+	DefaultSelectionTimeout = 30 * time.Second
+	// DefaultNumberOfCubbies is the size of the wall unless Parameters say
+	// otherwise.
+	DefaultNumberOfCubbies = 10
+)
+
+var errRobotBusy = status.Error(codes.FailedPrecondition, "robot is busy with another operation")
+
+// Robot is the simulated sorting robot, served as gen.SortingRobotServer.
+type Robot struct {
+	Items            []*gen.Item
+	SelectedItem     *gen.Item
+	pickToken        string
+	selectionTimer   *time.Timer
+	selectionTimeout time.Duration
+	numberOfCubbies  int
+	lease            *controllerLease
+	faults           *faultSimulator
+	timing           *robotTiming
+	armPosition      int
+	busy             bool
+	stopped          bool
+	stop             chan struct{}
+	counters         robotCounters
+	events           *eventLog
+	health           *health.Server
+	cubbies          map[string][]*gen.Item
+	m                sync.Mutex
+}
+
+// New returns a robot with an empty input bin and empty cubbies.
+func New(params *Parameters) *Robot {
+	s := newRobot()
+	if params.NumberOfCubbies != 0 {
+		s.numberOfCubbies = params.NumberOfCubbies
+	}
+	if params.SelectionTimeout != 0 {
+		s.selectionTimeout = params.SelectionTimeout
+	}
+	s.faults = newFaultSimulator(params.Faults, s.publishFault)
+	s.timing = newRobotTiming(params.Timing)
+	s.health = params.Health
+	s.updateHealth()
+	return s
+}
+
+func newRobot() *Robot {
+	rand.Seed(time.Now().UnixNano())
+	s := &Robot{
+		selectionTimeout: DefaultSelectionTimeout,
+		numberOfCubbies:  DefaultNumberOfCubbies,
+		timing:           newRobotTiming(TimingModel{}),
+		cubbies:          make(map[string][]*gen.Item),
+		stop:             make(chan struct{}),
+		events:           newEventLog(),
+		m:                sync.Mutex{},
+	}
+	s.faults = newFaultSimulator(FaultModel{}, s.publishFault)
+	return s
+}
+
+func (s *Robot) LoadItems(ctx context.Context, in *gen.LoadItemsRequest) (*gen.Empty, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.checkRunning(); err != nil {
+		return nil, err
+	}
+
+	s.Items = append(s.Items, in.Items...)
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ITEMS_LOADED, ItemCount: int32(len(in.Items))})
+	log.Println("Called LoadItems: ")
+	log.Println(len(s.Items))
+	return &gen.Empty{}, nil
+}
+
+func (s *Robot) SelectItem(ctx context.Context, in *gen.Empty) (_ *gen.SelectItemResponse, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	defer s.publishError(&err)
+
+	log.Println("SelectedItem:", s.SelectedItem)
+
+	if err := s.checkRunning(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLease(ctx); err != nil {
+		return nil, err
+	}
+
+	if s.busy {
+		return nil, errRobotBusy
+	}
+
+	if s.SelectedItem != nil {
+		return nil, fmt.Errorf("item has already been selected")
+	}
+
+	if s.Items == nil || len(s.Items) == 0 {
+		return nil, fmt.Errorf("no items in the cargo")
+	}
+
+	if err := s.move(ctx, s.timing.pickDuration(s.armPosition)); err != nil {
+		return nil, err
+	}
+	s.armPosition = inputBinPosition
+
+	if err := s.faults.beforeSelect(); err != nil {
+		if status.Code(err) == codes.Aborted {
+			s.counters.scanFailures++
+		}
+		return nil, err
+	}
+
+	pickToken, err := token.New()
+	if err != nil {
+		return nil, err
+	}
+
+	randomIndex := rand.Intn(len(s.Items))
+
+	s.SelectedItem = s.Items[randomIndex]
+	s.Items = append(s.Items[:randomIndex], s.Items[randomIndex+1:]...)
+	s.pickToken = pickToken
+	s.armSelectionTimer()
+	s.counters.picked++
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ITEM_SELECTED, Item: s.SelectedItem})
+
+	return &gen.SelectItemResponse{Item: s.SelectedItem, PickToken: pickToken}, nil
+}
+
+// armSelectionTimer starts the countdown after which the held item is put
+// back into the input bin. Must be called with s.m held.
+func (s *Robot) armSelectionTimer() {
+	pickToken := s.pickToken
+	s.selectionTimer = time.AfterFunc(s.selectionTimeout, func() {
+		s.returnSelectedItem(pickToken)
+	})
+}
+
+// returnSelectedItem puts an item that was selected but never moved back
+// into the input bin. It is a no-op if the selection identified by pickToken
+// has already been moved.
+func (s *Robot) returnSelectedItem(pickToken string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.SelectedItem == nil || s.pickToken != pickToken {
+		return
+	}
+
+	log.Println("Selection timed out, returning item to the input bin:", s.SelectedItem.Code)
+	s.Items = append(s.Items, s.SelectedItem)
+	s.counters.selectionTimeouts++
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ITEM_RETURNED, Item: s.SelectedItem})
+	s.clearSelection()
+}
+
+// ReturnItem carries the selected item back into the input bin, for a
+// controller that cannot or will not move it to a cubby.
+func (s *Robot) ReturnItem(ctx context.Context, in *gen.ReturnItemRequest) (_ *gen.Empty, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	defer s.publishError(&err)
+
+	if err := s.checkRunning(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLease(ctx); err != nil {
+		return nil, err
+	}
+
+	if s.SelectedItem == nil {
+		return nil, fmt.Errorf("item is not selected")
+	}
+
+	if in.PickToken != s.pickToken {
+		return nil, fmt.Errorf("pick token does not match the selected item")
+	}
+
+	if s.busy {
+		return nil, errRobotBusy
+	}
+
+	if err := s.move(ctx, s.timing.pickDuration(s.armPosition)); err != nil {
+		return nil, err
+	}
+	s.armPosition = inputBinPosition
+
+	if s.SelectedItem == nil || in.PickToken != s.pickToken {
+		return nil, fmt.Errorf("selection expired before the item was returned")
+	}
+
+	log.Println("Returning item to the input bin:", s.SelectedItem.Code)
+	s.Items = append(s.Items, s.SelectedItem)
+	s.counters.returned++
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ITEM_RETURNED, Item: s.SelectedItem})
+	s.clearSelection()
+	return &gen.Empty{}, nil
+}
+
+func (s *Robot) clearSelection() {
+	if s.selectionTimer != nil {
+		s.selectionTimer.Stop()
+	}
+
+	s.SelectedItem = nil
+	s.pickToken = ""
+	s.selectionTimer = nil
+}
+
+func (s *Robot) MoveItem(ctx context.Context, in *gen.MoveItemRequest) (_ *gen.Empty, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	defer s.publishError(&err)
+
+	if err := s.checkRunning(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLease(ctx); err != nil {
+		return nil, err
+	}
+
+	if s.SelectedItem == nil {
+		return nil, fmt.Errorf("item is not selected")
+	}
+
+	if in.PickToken != s.pickToken {
+		return nil, fmt.Errorf("pick token does not match the selected item")
+	}
+
+	if s.busy {
+		return nil, errRobotBusy
+	}
+
+	if in.Cubby != nil {
+		destination := cubbyPosition(in.Cubby)
+		if err := s.move(ctx, s.timing.placeDuration(s.armPosition, destination)); err != nil {
+			return nil, err
+		}
+		s.armPosition = destination
+
+		if s.SelectedItem == nil || in.PickToken != s.pickToken {
+			return nil, fmt.Errorf("selection expired before the item was placed")
+		}
+	}
+
+	if err := s.faults.beforeMove(); err != nil {
+		if status.Code(err) == codes.DataLoss {
+			s.counters.dropped++
+			s.clearSelection()
+		}
+		return nil, err
+	}
+
+	var cubby *gen.Cubby
+	if in.Cubby != nil {
+		cubby = s.faults.destination(in.Cubby, s.numberOfCubbies)
+		if cubby.Id != in.Cubby.Id {
+			s.counters.misSorted++
+		}
+		s.cubbies[cubby.Id] = append(s.cubbies[cubby.Id], s.SelectedItem)
+	}
+
+	s.counters.placed++
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ITEM_MOVED, Item: s.SelectedItem, Cubby: cubby})
+	s.clearSelection()
+	log.Println("Item moved. Items left: ", len(s.Items))
+	return &gen.Empty{}, nil
+}
+
+func (s *Robot) AuditState(ctx context.Context, in *gen.Empty) (*gen.AuditStateResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	cubbyIds := []string{}
+	for cubbyId := range s.cubbies {
+		cubbyIds = append(cubbyIds, cubbyId)
+	}
+	sort.Strings(cubbyIds)
+
+	cubbiesToItems := []*gen.CubbyToItems{}
+	for _, cubbyId := range cubbyIds {
+		cubbiesToItems = append(cubbiesToItems, &gen.CubbyToItems{Cubby: &gen.Cubby{Id: cubbyId}, Items: s.cubbies[cubbyId]})
+	}
+
+	return &gen.AuditStateResponse{CubbiesToItems: cubbiesToItems}, nil
+}
+
+// Shutdown reports the robot as no longer serving and ends every event watch
+// so the server can stop gracefully. An item still in the gripper is
+// reported, as it is lost with the robot's state.
+func (s *Robot) Shutdown() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+	if s.SelectedItem != nil {
+		log.Println("Shutting down while holding item:", s.SelectedItem.Code)
+	}
+	s.events.close()
+}
@@ -0,0 +1,156 @@
+package sortingrobot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadItems(t *testing.T) {
+	items := []*gen.Item{
+		&gen.Item{Code: "TestItem", Label: "TestItem"},
+		&gen.Item{Code: "TestItem", Label: "TestItem"},
+	}
+
+	var tests = []struct {
+		name     string
+		items    []*gen.Item
+		expected int
+		message  string
+	}{
+		{"Test LoadItems When Called Once", items, 2, "There should be 2 items in the cargo"},
+		{"Test LoadItems When Called More Than Once", items, 4, "There should be 4 items in the cargo"},
+	}
+
+	sorting_service := newRobot()
+
+	for _, test := range tests {
+		sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: test.items})
+		assert.Equal(t, len(sorting_service.Items), test.expected, test.message)
+	}
+}
+
+func TestSelectItem(t *testing.T) {
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+
+	var tests = []struct {
+		name    string
+		item    *gen.Item
+		message string
+	}{
+		{"Test SelectItems When Called Once", testItem, "There should be a selected item"},
+	}
+
+	sorting_service := newRobot()
+	items := []*gen.Item{testItem}
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+
+	for _, _ = range tests {
+		sorting_service.SelectItem(context.Background(), &gen.Empty{})
+		assert.Equal(t, sorting_service.SelectedItem, testItem, "There should be a selected item")
+	}
+}
+
+func TestSelectItem_ErrorCases(t *testing.T) {
+	sorting_service := newRobot()
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	items := []*gen.Item{testItem}
+
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	assert.NotEqual(t, err, nil, "When Item is selected, the method shoud return error")
+}
+
+func TestSelectItemWhenThereAreNoItemsLeft(t *testing.T) {
+	sorting_service := newRobot()
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.NotEqual(t, err, nil, "When there are no items in the cargo, the method shoud return error")
+}
+
+func TestMoveItem(t *testing.T) {
+	sorting_service := newRobot()
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	items := []*gen.Item{testItem}
+
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	res, err := sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: selected.PickToken})
+	assert.NotEqual(t, res, nil, "Result should be empty MoveItemResponse")
+	assert.Equal(t, err, nil, "There should be no error")
+}
+
+func TestMoveItemWithForeignPickToken(t *testing.T) {
+	sorting_service := newRobot()
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	items := []*gen.Item{testItem}
+
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: "foreign"})
+	assert.NotEqual(t, err, nil, "When the pick token does not match the selection, the method should return error")
+	assert.Equal(t, sorting_service.SelectedItem, testItem, "The selection should be kept for its owner")
+}
+
+func TestMoveItemWithStalePickToken(t *testing.T) {
+	sorting_service := newRobot()
+	items := []*gen.Item{
+		&gen.Item{Code: "TestItem", Label: "TestItem"},
+		&gen.Item{Code: "TestItem", Label: "TestItem"},
+	}
+
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	first, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: first.PickToken})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: first.PickToken})
+	assert.NotEqual(t, err, nil, "A pick token that was already used should be rejected")
+}
+
+func TestSelectedItemIsReturnedAfterTimeout(t *testing.T) {
+	sorting_service := newRobot()
+	sorting_service.selectionTimeout = 10 * time.Millisecond
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	items := []*gen.Item{testItem}
+
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: items})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	assert.Eventually(t, func() bool {
+		sorting_service.m.Lock()
+		defer sorting_service.m.Unlock()
+		return sorting_service.SelectedItem == nil && len(sorting_service.Items) == 1
+	}, time.Second, 5*time.Millisecond, "An abandoned item should be returned to the input bin")
+
+	_, err := sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: selected.PickToken})
+	assert.NotEqual(t, err, nil, "The pick token of a returned item should no longer be accepted")
+}
+
+func TestMoveItemWhenNoItemIsSelected(t *testing.T) {
+	sorting_service := newRobot()
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.NotEqual(t, err, nil, "When there are no items in the cargo, the method shoud return error")
+}
+
+func TestReturnItem(t *testing.T) {
+	sorting_service := newRobot()
+	sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "TestItem", Label: "TestItem"}}})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	_, err := sorting_service.ReturnItem(context.Background(), &gen.ReturnItemRequest{PickToken: "foreign"})
+	assert.NotNil(t, err, "Returning an item with a foreign pick token should fail")
+
+	_, err = sorting_service.ReturnItem(context.Background(), &gen.ReturnItemRequest{PickToken: selected.PickToken})
+	assert.Equal(t, err, nil, "Returning the selected item should succeed")
+	assert.Nil(t, sorting_service.SelectedItem, "A returned item should no longer be held")
+	assert.Equal(t, len(sorting_service.Items), 1, "A returned item should be back in the input bin")
+
+	_, err = sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{PickToken: selected.PickToken})
+	assert.NotNil(t, err, "A returned item should not be movable")
+}
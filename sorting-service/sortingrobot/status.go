@@ -0,0 +1,127 @@
+package sortingrobot
+
+import (
+	"context"
+	"log"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var errEmergencyStopped = status.Error(codes.Unavailable, "robot is emergency stopped")
+
+type robotCounters struct {
+	picked            int64
+	placed            int64
+	dropped           int64
+	misSorted         int64
+	scanFailures      int64
+	selectionTimeouts int64
+	returned          int64
+}
+
+// checkRunning rejects calls that would change the robot's state while it is
+// emergency stopped. Lease calls, ClearFault and the stop controls stay
+// available so a controller keeps its lease and an operator can clear the
+// cause of the stop. Must be called with s.m held.
+func (s *Robot) checkRunning() error {
+	if s.stopped {
+		return errEmergencyStopped
+	}
+	return nil
+}
+
+// robotState reports what the robot is doing. A stop takes precedence over a
+// jam, and both take precedence over the work in progress. Must be called
+// with s.m held.
+func (s *Robot) robotState() gen.RobotState {
+	switch {
+	case s.stopped:
+		return gen.RobotState_STOPPED
+	case s.faults.jammed:
+		return gen.RobotState_FAULTED
+	case s.busy:
+		return gen.RobotState_BUSY
+	case s.SelectedItem != nil:
+		return gen.RobotState_HOLDING_ITEM
+	default:
+		return gen.RobotState_IDLE
+	}
+}
+
+// updateHealth reports the robot as not serving while it is stopped or
+// jammed. Must be called with s.m held.
+func (s *Robot) updateHealth() {
+	if s.health == nil {
+		return
+	}
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if s.stopped || s.faults.jammed {
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus(gen.SortingRobot_ServiceDesc.ServiceName, servingStatus)
+}
+
+func (s *Robot) GetRobotStatus(ctx context.Context, in *gen.Empty) (*gen.RobotStatus, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return &gen.RobotStatus{
+		State:             s.robotState(),
+		SelectedItem:      s.SelectedItem,
+		ItemsInBin:        int32(len(s.Items)),
+		ItemsPicked:       s.counters.picked,
+		ItemsPlaced:       s.counters.placed,
+		ItemsDropped:      s.counters.dropped,
+		ItemsMisSorted:    s.counters.misSorted,
+		ScanFailures:      s.counters.scanFailures,
+		SelectionTimeouts: s.counters.selectionTimeouts,
+		ItemsReturned:     s.counters.returned,
+	}, nil
+}
+
+// EmergencyStop halts the arm where it is. An item in the gripper stays there
+// and its selection does not time out until the robot is resumed. Anyone may
+// stop the robot, whether or not they hold the lease.
+func (s *Robot) EmergencyStop(ctx context.Context, in *gen.Empty) (*gen.Empty, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.stopped {
+		return &gen.Empty{}, nil
+	}
+
+	s.stopped = true
+	close(s.stop)
+	if s.selectionTimer != nil {
+		s.selectionTimer.Stop()
+	}
+
+	s.updateHealth()
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ROBOT_STOPPED})
+	log.Println("Emergency stop")
+	return &gen.Empty{}, nil
+}
+
+func (s *Robot) Resume(ctx context.Context, in *gen.Empty) (*gen.Empty, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.stopped {
+		return &gen.Empty{}, nil
+	}
+
+	s.stopped = false
+	s.stop = make(chan struct{})
+	if s.SelectedItem != nil {
+		s.armSelectionTimer()
+	}
+
+	s.updateHealth()
+	s.events.publish(&gen.RobotEvent{Type: gen.RobotEventType_ROBOT_RESUMED})
+	log.Println("Robot resumed")
+	return &gen.Empty{}, nil
+}
@@ -0,0 +1,143 @@
+package sortingrobot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func robotState(sorting_service *Robot) gen.RobotState {
+	robotStatus, _ := sorting_service.GetRobotStatus(context.Background(), &gen.Empty{})
+	return robotStatus.State
+}
+
+func TestRobotStatus(t *testing.T) {
+	testItem := &gen.Item{Code: "TestItem", Label: "TestItem"}
+	sorting_service := loadedSortingService(testItem, &gen.Item{Code: "OtherItem", Label: "OtherItem"})
+	assert.Equal(t, robotState(sorting_service), gen.RobotState_IDLE, "A fresh robot should be idle")
+
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	robotStatus, _ := sorting_service.GetRobotStatus(context.Background(), &gen.Empty{})
+	assert.Equal(t, robotStatus.State, gen.RobotState_HOLDING_ITEM, "The robot should report the item it holds")
+	assert.Equal(t, robotStatus.SelectedItem, selected.Item, "The status should include the current selection")
+	assert.Equal(t, robotStatus.ItemsInBin, int32(1), "The status should count the items left in the bin")
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_MIS_SORT})
+	sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_SCAN_FAILURE})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	robotStatus, _ = sorting_service.GetRobotStatus(context.Background(), &gen.Empty{})
+	assert.Equal(t, robotStatus.State, gen.RobotState_IDLE, "The robot should be idle after placing its item")
+	assert.Equal(t, robotStatus.ItemsPicked, int64(1), "Picked items should be counted")
+	assert.Equal(t, robotStatus.ItemsPlaced, int64(1), "Placed items should be counted")
+	assert.Equal(t, robotStatus.ItemsMisSorted, int64(1), "Mis-sorted items should be counted")
+	assert.Equal(t, robotStatus.ScanFailures, int64(1), "Scan failures should be counted")
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_JAM})
+	assert.Equal(t, robotState(sorting_service), gen.RobotState_FAULTED, "A jammed robot should be faulted")
+
+	sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+	assert.Equal(t, robotState(sorting_service), gen.RobotState_STOPPED, "A stop should take precedence over a fault")
+}
+
+func TestMutatingCallsFailWhileStopped(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+
+	_, err := sorting_service.LoadItems(context.Background(), &gen.LoadItemsRequest{Items: []*gen.Item{{Code: "OtherItem"}}})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "LoadItems should fail while stopped")
+	_, err = sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "SelectItem should fail while stopped")
+	_, err = sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "MoveItem should fail while stopped")
+	_, err = sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_DROP})
+	assert.Equal(t, status.Code(err), codes.Unavailable, "InjectFault should fail while stopped")
+
+	_, err = sorting_service.ClearFault(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "Faults should be clearable while stopped")
+	_, err = sorting_service.AuditState(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "The robot should be auditable while stopped")
+
+	sorting_service.Resume(context.Background(), &gen.Empty{})
+	_, err = sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	assert.Equal(t, err, nil, "The held item should be movable after resuming")
+}
+
+func TestEmergencyStopHaltsOperationInProgress(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	sorting_service.setTimingModel(TimingModel{PickTime: time.Minute})
+
+	done := make(chan error)
+	go func() {
+		_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		return robotState(sorting_service) == gen.RobotState_BUSY
+	}, time.Second, time.Millisecond, "The robot should be busy while picking")
+
+	sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+	assert.Equal(t, status.Code(<-done), codes.Unavailable, "A stop should halt the pick in progress")
+	assert.Equal(t, len(sorting_service.Items), 1, "A halted pick should leave the item in the bin")
+}
+
+func TestSelectionDoesNotTimeOutWhileStopped(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	sorting_service.selectionTimeout = 20 * time.Millisecond
+
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, robotState(sorting_service), gen.RobotState_STOPPED, "The robot should stay stopped")
+	sorting_service.m.Lock()
+	assert.NotNil(t, sorting_service.SelectedItem, "The item should stay in the gripper while stopped")
+	sorting_service.m.Unlock()
+
+	sorting_service.Resume(context.Background(), &gen.Empty{})
+	assert.Eventually(t, func() bool {
+		return robotState(sorting_service) == gen.RobotState_IDLE
+	}, time.Second, time.Millisecond, "The selection should time out again after resuming")
+}
+
+func servingStatus(sorting_service *Robot) healthpb.HealthCheckResponse_ServingStatus {
+	response, _ := sorting_service.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: gen.SortingRobot_ServiceDesc.ServiceName})
+	return response.Status
+}
+
+func TestHealthFollowsRobotState(t *testing.T) {
+	sorting_service := newRobot()
+	sorting_service.health = health.NewServer()
+	sorting_service.updateHealth()
+	assert.Equal(t, servingStatus(sorting_service), healthpb.HealthCheckResponse_SERVING, "A running robot should be serving")
+
+	sorting_service.EmergencyStop(context.Background(), &gen.Empty{})
+	assert.Equal(t, servingStatus(sorting_service), healthpb.HealthCheckResponse_NOT_SERVING, "A stopped robot should not be serving")
+
+	sorting_service.Resume(context.Background(), &gen.Empty{})
+	assert.Equal(t, servingStatus(sorting_service), healthpb.HealthCheckResponse_SERVING, "A resumed robot should be serving")
+
+	sorting_service.InjectFault(context.Background(), &gen.InjectFaultRequest{Fault: gen.FaultType_JAM})
+	assert.Equal(t, servingStatus(sorting_service), healthpb.HealthCheckResponse_NOT_SERVING, "A jammed robot should not be serving")
+
+	sorting_service.ClearFault(context.Background(), &gen.Empty{})
+	assert.Equal(t, servingStatus(sorting_service), healthpb.HealthCheckResponse_SERVING, "A cleared robot should be serving")
+}
+
+func TestListInventory(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "b"}, &gen.Item{Code: "a"}, &gen.Item{Code: "b"})
+	sorting_service.SelectItem(context.Background(), &gen.Empty{})
+
+	inventory, err := sorting_service.ListInventory(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "Listing the inventory should succeed")
+	assert.Equal(t, inventory.Items, []*gen.StockLevel{{Code: "a", Quantity: 1}, {Code: "b", Quantity: 2}}, "The held item should be counted with the items in the bin, by code")
+}
@@ -0,0 +1,165 @@
+package sortingrobot
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// inputBinPosition is where the arm stands when it picks from the input bin.
+// Cubby n is n cubbies away from it.
+const inputBinPosition = 0
+
+// TimingModel describes how long the simulated arm takes to do its work. The
+// zero value makes every operation instant.
+type TimingModel struct {
+	PickTime           time.Duration
+	TravelTimePerCubby time.Duration
+	PlacementTime      time.Duration
+	// Jitter is the fraction, between 0 and 1, by which an operation may
+	// randomly run faster or slower than the model says.
+	Jitter float64
+	Seed   int64
+}
+
+func (m TimingModel) Validate() error {
+	durations := map[string]time.Duration{
+		"pick time":             m.PickTime,
+		"travel time per cubby": m.TravelTimePerCubby,
+		"placement time":        m.PlacementTime,
+	}
+	for name, duration := range durations {
+		if duration < 0 {
+			return fmt.Errorf("%s must not be negative, got %v", name, duration)
+		}
+	}
+
+	if m.Jitter < 0 || m.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1, got %v", m.Jitter)
+	}
+	return nil
+}
+
+type robotTiming struct {
+	model  TimingModel
+	random *rand.Rand
+}
+
+func newRobotTiming(model TimingModel) *robotTiming {
+	return &robotTiming{
+		model:  model,
+		random: rand.New(rand.NewSource(model.Seed)),
+	}
+}
+
+func (t *robotTiming) withJitter(duration time.Duration) time.Duration {
+	if t.model.Jitter == 0 || duration == 0 {
+		return duration
+	}
+
+	factor := 1 + t.model.Jitter*(2*t.random.Float64()-1)
+	return time.Duration(float64(duration) * factor)
+}
+
+func (t *robotTiming) travel(from, to int) time.Duration {
+	distance := to - from
+	if distance < 0 {
+		distance = -distance
+	}
+	return time.Duration(distance) * t.model.TravelTimePerCubby
+}
+
+// pickDuration is how long it takes to return to the input bin from the
+// arm's current position and pick an item.
+func (t *robotTiming) pickDuration(from int) time.Duration {
+	return t.withJitter(t.travel(from, inputBinPosition) + t.model.PickTime)
+}
+
+// placeDuration is how long it takes to carry the held item to a cubby and
+// put it down.
+func (t *robotTiming) placeDuration(from, to int) time.Duration {
+	return t.withJitter(t.travel(from, to) + t.model.PlacementTime)
+}
+
+// cubbyPosition maps a cubby to its place on its wall, its slot. Slots are
+// numbered from 1 on every wall, the bin standing by the first of each. A
+// cubby without a slot is placed by its id, and one whose id is not a number
+// is treated as the cubby next to the bin.
+func cubbyPosition(cubby *gen.Cubby) int {
+	if cubby.Slot > 0 {
+		return int(cubby.Slot)
+	}
+
+	position, err := strconv.Atoi(cubby.Id)
+	if err != nil || position < 1 {
+		return 1
+	}
+	return position
+}
+
+// wallCubby returns the cubby in slot of the wall wallId, named the way the
+// fulfillment service names it: after its wall and slot, or after its slot
+// alone on an unnamed wall.
+func wallCubby(wallId string, slot int) *gen.Cubby {
+	cubbyId := strconv.Itoa(slot)
+	if wallId != "" {
+		cubbyId = wallId + "-" + cubbyId
+	}
+	return &gen.Cubby{Id: cubbyId, WallId: wallId, Slot: int32(slot)}
+}
+
+// waitFor blocks for the duration of an operation. An operation that cannot
+// finish before the caller's deadline is refused up front rather than
+// abandoned half way. Closing stop halts the operation where it is.
+func waitFor(ctx context.Context, stop <-chan struct{}, duration time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < duration {
+		return status.Error(codes.DeadlineExceeded, "operation cannot finish before the deadline")
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return contextError(ctx.Err())
+	case <-stop:
+		return errEmergencyStopped
+	case <-timer.C:
+		return nil
+	}
+}
+
+func contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Canceled, err.Error())
+}
+
+func (s *Robot) setTimingModel(model TimingModel) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.timing = newRobotTiming(model)
+}
+
+// move keeps the robot busy for the duration of an operation. The lock is
+// released while the arm is in motion so the robot can still be audited.
+// Must be called with s.m held.
+func (s *Robot) move(ctx context.Context, duration time.Duration) error {
+	s.busy = true
+	stop := s.stop
+	s.m.Unlock()
+
+	err := waitFor(ctx, stop, duration)
+
+	s.m.Lock()
+	s.busy = false
+	return err
+}
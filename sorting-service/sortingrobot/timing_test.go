@@ -0,0 +1,122 @@
+package sortingrobot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Emoto13/sort-system/gen"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOperationsTakeModelledTime(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	sorting_service.setTimingModel(TimingModel{PickTime: 20 * time.Millisecond, TravelTimePerCubby: 10 * time.Millisecond, PlacementTime: 20 * time.Millisecond})
+
+	start := time.Now()
+	selected, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "Selecting an item should succeed")
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond), "Picking should take the pick time")
+
+	start = time.Now()
+	_, err = sorting_service.MoveItem(context.Background(), &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "3"}, PickToken: selected.PickToken})
+	assert.Equal(t, err, nil, "Moving an item should succeed")
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond), "Moving should take the travel and placement time")
+	assert.Equal(t, sorting_service.armPosition, 3, "The arm should stay at the cubby it placed the item in")
+}
+
+func TestReturningToTheBinTakesTravelTime(t *testing.T) {
+	timing := newRobotTiming(TimingModel{PickTime: time.Second, TravelTimePerCubby: time.Second})
+
+	assert.Equal(t, timing.pickDuration(inputBinPosition), time.Second, "Picking next to the bin should not need travel")
+	assert.Equal(t, timing.pickDuration(4), 5*time.Second, "Picking from cubby 4 should need four cubbies of travel")
+}
+
+func TestCubbyPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		cubby    *gen.Cubby
+		position int
+	}{
+		{"Cubby of an unnamed wall", &gen.Cubby{Id: "3", Slot: 3}, 3},
+		{"Cubby of a named wall", &gen.Cubby{Id: "A-7", WallId: "A", Slot: 7}, 7},
+		{"Cubby without a slot", &gen.Cubby{Id: "4"}, 4},
+		{"Cubby without a slot or a number", &gen.Cubby{Id: "A-4"}, 1},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, cubbyPosition(test.cubby), test.position, test.name+" should be in the right place")
+	}
+}
+
+func TestJitterStaysWithinBounds(t *testing.T) {
+	timing := newRobotTiming(TimingModel{PlacementTime: time.Second, Jitter: 0.1, Seed: 7})
+
+	for i := 0; i < 100; i++ {
+		duration := timing.placeDuration(inputBinPosition, inputBinPosition)
+		assert.GreaterOrEqual(t, int64(duration), int64(900*time.Millisecond), "Jitter should not speed up an operation by more than 10%")
+		assert.LessOrEqual(t, int64(duration), int64(1100*time.Millisecond), "Jitter should not slow down an operation by more than 10%")
+	}
+}
+
+func TestCanceledMoveKeepsItemHeld(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	selected, _ := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	sorting_service.setTimingModel(TimingModel{PlacementTime: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := sorting_service.MoveItem(ctx, &gen.MoveItemRequest{Cubby: &gen.Cubby{Id: "1"}, PickToken: selected.PickToken})
+	assert.Equal(t, status.Code(err), codes.Canceled, "A canceled move should fail")
+	assert.NotNil(t, sorting_service.SelectedItem, "The item should still be held after a canceled move")
+	assert.False(t, sorting_service.busy, "The robot should be free after a canceled move")
+
+	audit, _ := sorting_service.AuditState(context.Background(), &gen.Empty{})
+	assert.Equal(t, len(audit.CubbiesToItems), 0, "A canceled move should not place the item")
+}
+
+func TestOperationPastDeadlineIsRefused(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	sorting_service.setTimingModel(TimingModel{PickTime: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := sorting_service.SelectItem(ctx, &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.DeadlineExceeded, "A pick that cannot finish in time should fail")
+	assert.Less(t, int64(time.Since(start)), int64(time.Second), "A pick that cannot finish in time should not be started")
+	assert.Equal(t, len(sorting_service.Items), 1, "The item should stay in the input bin")
+}
+
+func TestRobotIsBusyDuringOperation(t *testing.T) {
+	sorting_service := loadedSortingService(&gen.Item{Code: "TestItem", Label: "TestItem"})
+	sorting_service.setTimingModel(TimingModel{PickTime: 200 * time.Millisecond})
+
+	done := make(chan error)
+	go func() {
+		_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		sorting_service.m.Lock()
+		defer sorting_service.m.Unlock()
+		return sorting_service.busy
+	}, time.Second, time.Millisecond, "The robot should be busy while picking")
+
+	_, err := sorting_service.SelectItem(context.Background(), &gen.Empty{})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition, "A second operation should be refused while the robot is busy")
+
+	_, err = sorting_service.AuditState(context.Background(), &gen.Empty{})
+	assert.Equal(t, err, nil, "The robot should be auditable while busy")
+	assert.Equal(t, <-done, nil, "The first pick should still succeed")
+}
+
+func TestTimingModelValidation(t *testing.T) {
+	assert.Equal(t, TimingModel{PickTime: time.Second, Jitter: 0.5}.Validate(), nil, "A valid timing model should pass validation")
+	assert.NotNil(t, TimingModel{PickTime: -time.Second}.Validate(), "Negative durations should be rejected")
+	assert.NotNil(t, TimingModel{Jitter: 1.5}.Validate(), "Jitter above 1 should be rejected")
+}